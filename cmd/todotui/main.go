@@ -1,13 +1,17 @@
 package main
 
 import (
-	"log"
+	"fmt"
+	"os"
 
 	"github.com/yuucu/todotui/pkg/app"
 )
 
 func main() {
 	if err := app.Run(); err != nil {
-		log.Fatalf("%+v\n", err)
+		// The standard logger is redirected to slog once logging is initialized,
+		// so report errors on stderr directly to keep them visible at any log level
+		fmt.Fprintf(os.Stderr, "Error: %+v\n", err)
+		os.Exit(app.ExitCode(err))
 	}
 }
@@ -0,0 +1,31 @@
+// Package testutil provides helpers shared by the tests of several packages
+package testutil
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// WriteExternally writes content to path as another program editing the file would
+func WriteExternally(t testing.TB, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// mtimeの解像度が粗いファイルシステムでも変更として検出させる
+	future := time.Now().Add(2 * time.Second)
+	if err := os.Chtimes(path, future, future); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// ReadFile returns the contents of path
+func ReadFile(t testing.TB, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
@@ -19,6 +19,7 @@ type config struct {
 	todoFile    string
 	showVersion bool
 	showHelp    bool
+	args        []string // Subcommand and its arguments, if any
 }
 
 // parseLogLevel converts string log level to slog.Level
@@ -39,20 +40,24 @@ func parseLogLevel(levelStr string) slog.Level {
 
 func printUsage() {
 	fmt.Printf(`Usage: %s [OPTIONS] [TODO_FILE]
+       %s [OPTIONS] COMMAND [ARGS]
 
 A terminal todo.txt manager with vim-like keybindings.
 
 Arguments:
   TODO_FILE    Path to todo.txt file (required unless set in config)
 
+Commands:
+%s
 Options:
   -c, --config CONFIG       Path to configuration file
+  -f, --file TODO_FILE      Path to todo.txt file used by commands
   -t, --theme THEME         Set color theme (catppuccin, nord, everforest-dark, everforest-light)
   -v, --version             Show version information
   -h, --help               Show this help message
 
 For detailed documentation and keybindings, see: https://github.com/yuucu/todotui
-`, os.Args[0])
+`, os.Args[0], os.Args[0], commandsUsage())
 }
 
 // parseFlags parses command line flags and returns configuration
@@ -60,6 +65,7 @@ func parseFlags() (*config, error) {
 	// Define command line flags
 	var (
 		configFile  = flag.String("config", "", "Path to configuration file")
+		todoFile    = flag.String("file", "", "Path to todo.txt file used by commands")
 		themeName   = flag.String("theme", "", "Set color theme (catppuccin, nord, everforest-dark, everforest-light)")
 		showVersion = flag.Bool("version", false, "Show version information")
 		showHelp    = flag.Bool("help", false, "Show this help message")
@@ -67,6 +73,7 @@ func parseFlags() (*config, error) {
 
 	// Define short flag aliases
 	flag.StringVar(configFile, "c", "", "Path to configuration file")
+	flag.StringVar(todoFile, "f", "", "Path to todo.txt file used by commands")
 	flag.StringVar(themeName, "t", "", "Set color theme (catppuccin, nord, everforest-dark, everforest-light)")
 	flag.BoolVar(showVersion, "v", false, "Show version information")
 	flag.BoolVar(showHelp, "h", false, "Show this help message")
@@ -77,39 +84,48 @@ func parseFlags() (*config, error) {
 	// Parse command line flags
 	flag.Parse()
 
-	// Get remaining non-flag arguments (todo file)
+	cfg := &config{
+		configFile:  *configFile,
+		themeName:   *themeName,
+		todoFile:    *todoFile,
+		showVersion: *showVersion,
+		showHelp:    *showHelp,
+	}
+
+	// Get remaining non-flag arguments (subcommand or todo file)
 	args := flag.Args()
-	var todoFile string
 	if len(args) > 0 {
-		todoFile = args[0]
+		if _, ok := findCommand(args[0]); ok {
+			cfg.args = args
+			return cfg, nil
+		}
+	}
+	if len(args) > 0 {
+		if cfg.todoFile != "" {
+			return nil, usageError("todo file specified twice: --file %s and %s", cfg.todoFile, args[0])
+		}
+		cfg.todoFile = args[0]
 	}
 	if len(args) > 1 {
-		return nil, fmt.Errorf("too many arguments")
+		return nil, usageError("too many arguments")
 	}
 
-	return &config{
-		configFile:  *configFile,
-		themeName:   *themeName,
-		todoFile:    todoFile,
-		showVersion: *showVersion,
-		showHelp:    *showHelp,
-	}, nil
+	return cfg, nil
 }
 
-// runBubbleTea runs the bubble tea application with given configuration
-func runBubbleTea(cfg *config) error {
-	// Setup IME environment for Japanese input support
-	ui.SetupIMEEnvironment()
-
-	// Load configuration
+// loadAppConfig loads the configuration file and applies command line overrides
+func loadAppConfig(cfg *config) ui.AppConfig {
 	appConfig := ui.LoadConfig(cfg.configFile)
 
 	// Override theme if specified via command line
 	if cfg.themeName != "" {
 		appConfig.Theme = cfg.themeName
 	}
+	return appConfig
+}
 
-	// Initialize logging system
+// initLogger initializes the logging system from the configuration
+func initLogger(appConfig ui.AppConfig) {
 	var finalLogLevel string
 	if appConfig.Logging.LogLevel != "" {
 		finalLogLevel = appConfig.Logging.LogLevel
@@ -126,10 +142,10 @@ func runBubbleTea(cfg *config) error {
 	if err := logger.Init(logConfig); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: Failed to initialize logger: %v\n", err)
 	}
+}
 
-	logger.Info("todotui started", "version", GetVersion(), "commit", GetCommit())
-
-	// Determine todo file path
+// resolveTodoFile determines the todo file from the command line or the configuration
+func resolveTodoFile(cfg *config, appConfig ui.AppConfig) (string, error) {
 	var finalTodoFile string
 	if cfg.todoFile != "" {
 		finalTodoFile = cfg.todoFile
@@ -139,11 +155,27 @@ func runBubbleTea(cfg *config) error {
 		logger.Debug("TODO file specified via config", "file", finalTodoFile)
 	} else {
 		logger.Error("No todo file specified")
-		return fmt.Errorf("no todo file specified. Use CLI argument or set default_todo_file in config")
+		return "", fmt.Errorf("no todo file specified. Use CLI argument or set default_todo_file in config")
 	}
 
 	// Expand ~ in path if present
-	finalTodoFile = ui.ExpandHomePath(finalTodoFile)
+	return ui.ExpandHomePath(finalTodoFile), nil
+}
+
+// runBubbleTea runs the bubble tea application with given configuration
+func runBubbleTea(cfg *config) error {
+	// Setup IME environment for Japanese input support
+	ui.SetupIMEEnvironment()
+
+	appConfig := loadAppConfig(cfg)
+	initLogger(appConfig)
+
+	logger.Info("todotui started", "version", GetVersion(), "commit", GetCommit())
+
+	finalTodoFile, err := resolveTodoFile(cfg, appConfig)
+	if err != nil {
+		return err
+	}
 
 	// Create model with configuration
 	logger.Debug("Initializing model", "todo_file", finalTodoFile, "theme", appConfig.Theme)
@@ -184,5 +216,9 @@ func Run() error {
 		return nil
 	}
 
+	if len(cfg.args) > 0 {
+		return runCommand(cfg, cfg.args, os.Stdin, os.Stdout, os.Stderr)
+	}
+
 	return runBubbleTea(cfg)
 }
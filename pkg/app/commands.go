@@ -0,0 +1,308 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/yuucu/todotui/pkg/todo"
+	"github.com/yuucu/todotui/pkg/ui"
+)
+
+// history で表示するエントリ数のデフォルト
+const defaultHistoryLimit = 20
+
+// commandEnv holds everything a subcommand needs to run
+type commandEnv struct {
+	appConfig ui.AppConfig
+	todoFile  string
+	stdin     io.Reader
+	stdout    io.Writer
+	stderr    io.Writer
+}
+
+// command is a non-interactive subcommand such as "todotui backup list"
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(env *commandEnv, args []string) error
+}
+
+// commands lists the available subcommands in the order shown in the usage
+var commands = []command{
+	{
+		name:    "add",
+		usage:   "add <task>",
+		summary: "Add a task and print its line number",
+		run:     runAdd,
+	},
+	{
+		name:    "ls",
+		usage:   "ls [--all] [--format F] [query]",
+		summary: "List open tasks matching a query with their line numbers",
+		run:     runList,
+	},
+	{
+		name:    "do",
+		usage:   "do <N>...",
+		summary: "Complete tasks by line number",
+		run:     runDo,
+	},
+	{
+		name:    "pri",
+		usage:   "pri <N> <A-Z|none>",
+		summary: "Set or remove the priority of a task",
+		run:     runPriority,
+	},
+	{
+		name:    "due",
+		usage:   "due <N> <date|none>",
+		summary: "Set or remove the due date of a task",
+		run:     runDue,
+	},
+	{
+		name:    "rm",
+		usage:   "rm <N>...",
+		summary: "Delete tasks by line number",
+		run:     runRemove,
+	},
+	{
+		name:    "export",
+		usage:   "export [--format F] [-o F] [query]",
+		summary: "Export tasks as json, csv, tsv or ics",
+		run:     runExport,
+	},
+	{
+		name:    "import",
+		usage:   "import [--from F] [-n] <file>",
+		summary: "Add tasks from Taskwarrior, Markdown or CSV",
+		run:     runImport,
+	},
+	{
+		name:    "backup",
+		usage:   "backup list | backup restore <id>",
+		summary: "List backups of the todo file or restore one",
+		run:     runBackup,
+	},
+	{
+		name:    "archive",
+		usage:   "archive [--purge-deleted-after N]",
+		summary: "Move completed tasks to done.txt",
+		run:     runArchive,
+	},
+	{
+		name:    "history",
+		usage:   "history [-n N]",
+		summary: "Show the journal of task changes, newest first",
+		run:     runHistory,
+	},
+	{
+		name:    "revert",
+		usage:   "revert <entry>",
+		summary: "Undo the change recorded in a journal entry",
+		run:     runRevert,
+	},
+}
+
+// findCommand returns the subcommand with the given name, if any
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// commandsUsage returns the Commands section of the usage message
+func commandsUsage() string {
+	var b strings.Builder
+	for _, cmd := range commands {
+		fmt.Fprintf(&b, "  %-34s %s\n", cmd.usage, cmd.summary)
+	}
+	return b.String()
+}
+
+// runCommand runs the subcommand named by args[0]
+func runCommand(cfg *config, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	cmd, _ := findCommand(args[0])
+
+	appConfig := loadAppConfig(cfg)
+	initLogger(appConfig)
+
+	todoFile, err := resolveTodoFile(cfg, appConfig)
+	if err != nil {
+		return err
+	}
+
+	env := &commandEnv{
+		appConfig: appConfig,
+		todoFile:  todoFile,
+		stdin:     stdin,
+		stdout:    stdout,
+		stderr:    stderr,
+	}
+	return cmd.run(env, args[1:])
+}
+
+// runBackup implements "todotui backup"
+func runBackup(env *commandEnv, args []string) error {
+	opts := env.appConfig.Backup.Options()
+
+	if len(args) == 0 {
+		return usageError("usage: todotui backup list | backup restore <id>")
+	}
+
+	switch args[0] {
+	case "list":
+		if len(args) != 1 {
+			return usageError("usage: todotui backup list")
+		}
+		backups, err := todo.ListBackups(env.todoFile, opts)
+		if err != nil {
+			return fmt.Errorf("failed to list backups: %w", err)
+		}
+		if len(backups) == 0 {
+			fmt.Fprintf(env.stdout, "No backups of %s\n", env.todoFile)
+			return nil
+		}
+
+		w := tabwriter.NewWriter(env.stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tCREATED\tSIZE")
+		for _, backup := range backups {
+			fmt.Fprintf(w, "%s\t%s\t%d\n", backup.ID, backup.Time.Format(time.DateTime), backup.Size)
+		}
+		return w.Flush()
+
+	case "restore":
+		if len(args) != 2 {
+			return usageError("usage: todotui backup restore <id>")
+		}
+		backup, err := todo.RestoreBackup(env.todoFile, opts, args[1])
+		if err != nil {
+			return fmt.Errorf("failed to restore backup: %w", err)
+		}
+		fmt.Fprintf(env.stdout, "Restored %s from backup %s\n", env.todoFile, backup.ID)
+		return nil
+
+	default:
+		return usageError("unknown backup command %q (expected list or restore)", args[0])
+	}
+}
+
+// runArchive implements "todotui archive"
+func runArchive(env *commandEnv, args []string) error {
+	archiveConfig := env.appConfig.Archive
+
+	flags := flag.NewFlagSet("archive", flag.ContinueOnError)
+	flags.SetOutput(env.stderr)
+	flags.IntVar(&archiveConfig.PurgeDeletedAfterDays, "purge-deleted-after", archiveConfig.PurgeDeletedAfterDays,
+		"Also archive deleted tasks older than this many days (0 keeps them)")
+	if err := flags.Parse(args); err != nil {
+		return usageError("%v", err)
+	}
+	if flags.NArg() > 0 {
+		return usageError("usage: todotui archive [--purge-deleted-after N]")
+	}
+
+	opts := archiveConfig.Options(time.Now())
+	store := todo.NewStore(env.todoFile)
+	store.SetBackup(env.appConfig.Backup.Options())
+	_, archived, err := store.Archive(opts)
+	if err != nil {
+		return fmt.Errorf("failed to archive tasks: %w", err)
+	}
+	entries := todo.NewArchiveEntries(env.todoFile, archived)
+	if journalErr := todo.AppendJournal(env.todoFile, env.appConfig.Journal.Options(), entries...); journalErr != nil {
+		return fmt.Errorf("archived tasks but failed to write journal: %w", journalErr)
+	}
+
+	doneFile := archiveConfig.DoneFile
+	if doneFile == "" {
+		doneFile = todo.DefaultDoneFile(env.todoFile)
+	}
+	fmt.Fprintf(env.stdout, "Archived %d task(s) to %s\n", len(archived), doneFile)
+	return nil
+}
+
+// runHistory implements "todotui history"
+func runHistory(env *commandEnv, args []string) error {
+	limit := defaultHistoryLimit
+
+	flags := flag.NewFlagSet("history", flag.ContinueOnError)
+	flags.SetOutput(env.stderr)
+	flags.IntVar(&limit, "n", limit, "Number of entries to show (0 shows all)")
+	if err := flags.Parse(args); err != nil {
+		return usageError("%v", err)
+	}
+	if flags.NArg() > 0 {
+		return usageError("usage: todotui history [-n N]")
+	}
+
+	entries, err := todo.ReadJournal(env.todoFile, env.appConfig.Journal.Options())
+	if err != nil {
+		return fmt.Errorf("failed to read journal: %w", err)
+	}
+	if len(entries) == 0 {
+		fmt.Fprintf(env.stdout, "No journal entries for %s\n", env.todoFile)
+		return nil
+	}
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+
+	w := tabwriter.NewWriter(env.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTIME\tUSER\tKIND\tCHANGE")
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n",
+			entry.ID, entry.Time.Local().Format(time.DateTime), entry.User, entry.Kind, describeChange(entry))
+	}
+	return w.Flush()
+}
+
+// describeChange summarizes the before and after text of a journal entry
+func describeChange(entry todo.JournalEntry) string {
+	switch {
+	case entry.Before == "":
+		return "+ " + entry.After
+	case entry.After == "":
+		return "- " + entry.Before
+	default:
+		return entry.Before + " → " + entry.After
+	}
+}
+
+// runRevert implements "todotui revert"
+func runRevert(env *commandEnv, args []string) error {
+	if len(args) != 1 {
+		return usageError("usage: todotui revert <entry>")
+	}
+
+	journalOpts := env.appConfig.Journal.Options()
+	entries, err := todo.ReadJournal(env.todoFile, journalOpts)
+	if err != nil {
+		return fmt.Errorf("failed to read journal: %w", err)
+	}
+	entry, err := todo.FindJournalEntry(entries, args[0])
+	if err != nil {
+		return err
+	}
+
+	store := todo.NewStore(env.todoFile)
+	store.SetBackup(env.appConfig.Backup.Options())
+	reverted, err := store.Revert(entry)
+	if err != nil {
+		return fmt.Errorf("failed to revert entry %d: %w", entry.ID, err)
+	}
+	if journalErr := todo.AppendJournal(env.todoFile, journalOpts, reverted); journalErr != nil {
+		return fmt.Errorf("reverted entry %d but failed to write journal: %w", entry.ID, journalErr)
+	}
+
+	fmt.Fprintf(env.stdout, "Reverted entry %d (%s): %s\n", entry.ID, entry.Kind, describeChange(reverted))
+	return nil
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Exit codes of todotui, so scripts can tell failures apart
+const (
+	ExitOK     = 0 // Success
+	ExitError  = 1 // The command failed, e.g. the todo file could not be saved
+	ExitUsage  = 2 // Invalid arguments
+	ExitNoTask = 3 // No task on the given line
+)
+
+// exitError is an error that ends todotui with a specific exit code
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// usageError returns an error for invalid command line arguments
+func usageError(format string, args ...any) error {
+	return &exitError{code: ExitUsage, err: fmt.Errorf(format, args...)}
+}
+
+// ExitCode returns the exit code for an error returned by Run
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	return ExitError
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/yuucu/todotui/pkg/importer"
+)
+
+// runImport implements "todotui import".
+// Tasks already in the todo file are skipped, and the rest are appended to it.
+func runImport(env *commandEnv, args []string) error {
+	var from string
+	var dryRun bool
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	flags.SetOutput(env.stderr)
+	flags.StringVar(&from, "from", "", "Format of the file: "+strings.Join(importer.Names(), ", ")+" (default: from the extension)")
+	flags.BoolVar(&dryRun, "dry-run", false, "Show what would be imported without changing the todo file")
+	flags.BoolVar(&dryRun, "n", false, "Show what would be imported without changing the todo file")
+	if err := flags.Parse(args); err != nil {
+		return usageError("%v", err)
+	}
+	if flags.NArg() != 1 {
+		return usageError("usage: todotui import [--from FORMAT] [--dry-run] <file|->")
+	}
+	source := flags.Arg(0)
+
+	var format importer.Format
+	var err error
+	switch {
+	case from != "":
+		format, err = importer.Lookup(from)
+	case source == "-":
+		err = fmt.Errorf("--from is needed to import from standard input")
+	default:
+		format, err = importer.Detect(source)
+	}
+	if err != nil {
+		return usageError("%v", err)
+	}
+
+	var in io.Reader = env.stdin
+	if source != "-" {
+		input, openErr := os.Open(source)
+		if openErr != nil {
+			return fmt.Errorf("failed to open %s: %w", source, openErr)
+		}
+		defer input.Close()
+		in = input
+	}
+	imported, err := format.Reader.Read(in)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", source, err)
+	}
+
+	file, err := openTaskFile(env)
+	if err != nil {
+		return err
+	}
+	plan := importer.NewPlan(file.tasks, imported)
+
+	for _, duplicate := range plan.Duplicates {
+		fmt.Fprintf(env.stdout, "= %s (already on line %d)\n", duplicate.Task.String(), duplicate.Line)
+	}
+	if dryRun {
+		for _, task := range plan.Add {
+			fmt.Fprintf(env.stdout, "+ %s\n", task.String())
+		}
+		fmt.Fprintf(env.stdout, "Would import %d task(s) and skip %d duplicate(s) (dry run)\n", len(plan.Add), len(plan.Duplicates))
+		return nil
+	}
+
+	if len(plan.Add) > 0 {
+		for _, task := range plan.Add {
+			file.add("Import", task)
+		}
+		if saveErr := file.save(); saveErr != nil {
+			return saveErr
+		}
+	}
+	for _, task := range plan.Add {
+		fmt.Fprintf(env.stdout, "+ %d %s\n", file.lineOf(task.String()), task.String())
+	}
+	fmt.Fprintf(env.stdout, "Imported %d task(s) and skipped %d duplicate(s)\n", len(plan.Add), len(plan.Duplicates))
+	return nil
+}
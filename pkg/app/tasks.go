@@ -0,0 +1,411 @@
+package app
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	todotxt "github.com/1set/todotxt"
+	"github.com/yuucu/todotui/pkg/domain"
+	"github.com/yuucu/todotui/pkg/export"
+	"github.com/yuucu/todotui/pkg/todo"
+)
+
+// Permission of files written by export
+const exportFileMode = 0644
+
+// noneArg removes the priority or due date, as in "todotui due N none"
+const noneArg = "none"
+
+// taskFile is the todo file as loaded by a subcommand that lists or changes tasks.
+// Tasks are numbered by the line they are on, so numbers shown by ls stay
+// valid until lines above them are added or removed.
+type taskFile struct {
+	env     *commandEnv
+	store   *todo.Store
+	tasks   domain.Tasks
+	entries []todo.JournalEntry // Changes to write to the journal after saving
+}
+
+// openTaskFile loads the todo file
+func openTaskFile(env *commandEnv) (*taskFile, error) {
+	store := todo.NewStore(env.todoFile)
+	store.SetBackup(env.appConfig.Backup.Options())
+	list, err := store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", env.todoFile, err)
+	}
+	return &taskFile{env: env, store: store, tasks: domain.NewTasks(list)}, nil
+}
+
+// task returns the task on the line given as a command argument
+func (f *taskFile) task(arg string) (*domain.Task, error) {
+	line, err := strconv.Atoi(arg)
+	if err != nil || line <= 0 {
+		return nil, usageError("invalid task number %q", arg)
+	}
+	for i := range f.tasks {
+		if f.tasks[i].ID().Line == line {
+			return &f.tasks[i], nil
+		}
+	}
+	return nil, &exitError{code: ExitNoTask, err: fmt.Errorf("no task on line %d", line)}
+}
+
+// tasksOf returns the tasks on the lines given as command arguments
+func (f *taskFile) tasksOf(args []string) ([]*domain.Task, error) {
+	tasks := make([]*domain.Task, 0, len(args))
+	for _, arg := range args {
+		task, err := f.task(arg)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// editable returns an error for a task that cannot be changed.
+// Deleted tasks are read-only until they are restored, as in the UI.
+func editable(task *domain.Task) error {
+	if task.IsDeleted() {
+		return fmt.Errorf("task %d is deleted", task.ID().Line)
+	}
+	return nil
+}
+
+// update applies fn to task and records the change under kind
+func (f *taskFile) update(kind string, task *domain.Task, fn func(*domain.Task) error) error {
+	before := task.String()
+	if err := fn(task); err != nil {
+		return fmt.Errorf("line %d: %w", task.ID().Line, err)
+	}
+	if after := task.String(); after != before {
+		f.entries = append(f.entries, todo.NewJournalEntry(f.env.todoFile, kind, task.ID().Line, before, after))
+	}
+	return nil
+}
+
+// add appends a new task and records it under kind
+func (f *taskFile) add(kind string, task *todotxt.Task) {
+	list := append(f.tasks.ToTaskList(), *task)
+	f.tasks = domain.NewTasks(list)
+	f.entries = append(f.entries, todo.NewJournalEntry(f.env.todoFile, kind, 0, "", task.String()))
+}
+
+// save writes the tasks to the file and the changes to the journal.
+// The tasks are reloaded from the saved file, so lineOf finds new tasks.
+func (f *taskFile) save() error {
+	saved, err := f.store.Save(f.tasks.ToTaskList())
+	if err != nil {
+		return fmt.Errorf("failed to save %s: %w", f.env.todoFile, err)
+	}
+	f.tasks = domain.NewTasks(saved)
+
+	// 追加したタスクの行番号は保存後に決まる
+	for i, entry := range f.entries {
+		if entry.Line == 0 {
+			f.entries[i].Line = f.lineOf(entry.After)
+		}
+	}
+	if journalErr := todo.AppendJournal(f.env.todoFile, f.env.appConfig.Journal.Options(), f.entries...); journalErr != nil {
+		return fmt.Errorf("saved %s but failed to write journal: %w", f.env.todoFile, journalErr)
+	}
+	f.entries = nil
+	return nil
+}
+
+// lineOf returns the line of the last task with the given text, or 0
+func (f *taskFile) lineOf(text string) int {
+	for i := len(f.tasks) - 1; i >= 0; i-- {
+		if f.tasks[i].String() == text {
+			return f.tasks[i].ID().Line
+		}
+	}
+	return 0
+}
+
+// lineWidth returns the width of the largest line number, so numbers line up
+func (f *taskFile) lineWidth() int {
+	width := 1
+	for i := range f.tasks {
+		width = max(width, len(strconv.Itoa(f.tasks[i].ID().Line)))
+	}
+	return width
+}
+
+// printTask prints a task with its line number
+func (f *taskFile) printTask(prefix string, task *domain.Task) {
+	fmt.Fprintf(f.env.stdout, "%s%*d %s\n", prefix, f.lineWidth(), task.ID().Line, task.String())
+}
+
+// runAdd implements "todotui add"
+func runAdd(env *commandEnv, args []string) error {
+	text := strings.TrimSpace(strings.Join(args, " "))
+	if text == "" {
+		return usageError("usage: todotui add <task>")
+	}
+
+	// Date expressions such as due:tomorrow are saved as ISO dates, as in the UI
+	normalized, err := env.appConfig.Calendar().NormalizeDates(text, time.Now())
+	if err != nil {
+		return usageError("%v", err)
+	}
+	task, err := todotxt.ParseTask(normalized)
+	if err != nil {
+		return usageError("invalid task %q: %v", text, err)
+	}
+
+	file, err := openTaskFile(env)
+	if err != nil {
+		return err
+	}
+	file.add("Add", task)
+	if saveErr := file.save(); saveErr != nil {
+		return saveErr
+	}
+	fmt.Fprintf(env.stdout, "%d %s\n", file.lineOf(task.String()), task.String())
+	return nil
+}
+
+// runList implements "todotui ls"
+func runList(env *commandEnv, args []string) error {
+	var all bool
+	var format string
+	flags := flag.NewFlagSet("ls", flag.ContinueOnError)
+	flags.SetOutput(env.stderr)
+	flags.BoolVar(&all, "all", false, "Also list completed and deleted tasks")
+	flags.BoolVar(&all, "a", false, "Also list completed and deleted tasks")
+	flags.StringVar(&format, "format", "", "Output format: json, csv, tsv or ics (default: one task per line)")
+	if err := flags.Parse(args); err != nil {
+		return usageError("%v", err)
+	}
+
+	file, tasks, err := matchingTasks(env, flags.Args(), all)
+	if err != nil {
+		return err
+	}
+	if format != "" {
+		return writeTasks(env.stdout, format, tasks)
+	}
+	for i := range tasks {
+		file.printTask("", &tasks[i])
+	}
+	return nil
+}
+
+// runExport implements "todotui export".
+// Unlike ls, every task is exported by default, including completed and deleted ones.
+func runExport(env *commandEnv, args []string) error {
+	var format, output string
+	var open bool
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	flags.SetOutput(env.stderr)
+	flags.StringVar(&format, "format", string(export.FormatJSON), "Output format: json, csv, tsv or ics")
+	flags.StringVar(&output, "o", "", "Write to this file instead of standard output")
+	flags.BoolVar(&open, "open", false, "Only export tasks that are not completed or deleted")
+	if err := flags.Parse(args); err != nil {
+		return usageError("%v", err)
+	}
+
+	_, tasks, err := matchingTasks(env, flags.Args(), !open)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		return writeTasks(env.stdout, format, tasks)
+	}
+
+	var buf bytes.Buffer
+	if writeErr := writeTasks(&buf, format, tasks); writeErr != nil {
+		return writeErr
+	}
+	if writeErr := os.WriteFile(output, buf.Bytes(), exportFileMode); writeErr != nil {
+		return fmt.Errorf("failed to write %s: %w", output, writeErr)
+	}
+	fmt.Fprintf(env.stdout, "Exported %d task(s) to %s\n", len(tasks), output)
+	return nil
+}
+
+// matchingTasks loads the todo file and returns the tasks matching the query
+// in args. Completed and deleted tasks are left out unless all is set.
+func matchingTasks(env *commandEnv, args []string, all bool) (*taskFile, domain.Tasks, error) {
+	query, err := domain.ParseQuery(strings.Join(args, " "))
+	if err != nil {
+		return nil, nil, usageError("%v", err)
+	}
+
+	file, err := openTaskFile(env)
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	calendar := env.appConfig.Calendar()
+	tasks := file.tasks.Filter(func(task domain.Task, _ int) bool {
+		if !all && (task.IsCompleted() || task.IsDeleted()) {
+			return false
+		}
+		return query.Match(&task, calendar, now)
+	})
+	return file, tasks, nil
+}
+
+// writeTasks writes tasks in a structured format such as json
+func writeTasks(w io.Writer, name string, tasks domain.Tasks) error {
+	format, err := export.ParseFormat(name)
+	if err != nil {
+		return usageError("%v", err)
+	}
+	return export.Write(w, format, tasks)
+}
+
+// runDo implements "todotui do"
+func runDo(env *commandEnv, args []string) error {
+	if len(args) == 0 {
+		return usageError("usage: todotui do <N>...")
+	}
+	file, err := openTaskFile(env)
+	if err != nil {
+		return err
+	}
+	tasks, err := file.tasksOf(args)
+	if err != nil {
+		return err
+	}
+
+	var next []*todotxt.Task
+	for _, task := range tasks {
+		if editErr := editable(task); editErr != nil {
+			return editErr
+		}
+		if task.IsCompleted() {
+			return fmt.Errorf("task %d is already done", task.ID().Line)
+		}
+		updateErr := file.update("Complete", task, func(task *domain.Task) error {
+			task.ToggleCompletion()
+			// Completing a recurring task creates its next occurrence
+			occurrence, nextErr := task.NextOccurrence(task.GetCompletedDate())
+			if nextErr != nil {
+				fmt.Fprintf(env.stderr, "Warning: task %d not repeated: %v\n", task.ID().Line, nextErr)
+			} else if occurrence != nil {
+				next = append(next, occurrence)
+			}
+			return nil
+		})
+		if updateErr != nil {
+			return updateErr
+		}
+	}
+	for _, task := range next {
+		file.add("Repeat", task)
+	}
+
+	if saveErr := file.save(); saveErr != nil {
+		return saveErr
+	}
+	for _, task := range tasks {
+		file.printTask("Done: ", task)
+	}
+	for _, task := range next {
+		fmt.Fprintf(env.stdout, "Repeat: %d %s\n", file.lineOf(task.String()), task.String())
+	}
+	return nil
+}
+
+// runPriority implements "todotui pri"
+func runPriority(env *commandEnv, args []string) error {
+	if len(args) != 2 {
+		return usageError("usage: todotui pri <N> <A-Z|none>")
+	}
+	priority := args[1]
+	if strings.EqualFold(priority, noneArg) {
+		priority = ""
+	}
+	return updateOne(env, args[0], "Change priority", func(task *domain.Task) error {
+		if err := task.SetPriority(priority); err != nil {
+			return usageError("%v", err)
+		}
+		return nil
+	})
+}
+
+// runRemove implements "todotui rm".
+// Tasks are soft deleted like with d in the UI, so no line number changes;
+// "todotui archive --purge-deleted-after N" removes them for good.
+func runRemove(env *commandEnv, args []string) error {
+	if len(args) == 0 {
+		return usageError("usage: todotui rm <N>...")
+	}
+	file, err := openTaskFile(env)
+	if err != nil {
+		return err
+	}
+	tasks, err := file.tasksOf(args)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, task := range tasks {
+		if editErr := editable(task); editErr != nil {
+			return editErr
+		}
+		if updateErr := file.update("Delete", task, func(task *domain.Task) error {
+			return task.SoftDelete(now)
+		}); updateErr != nil {
+			return updateErr
+		}
+	}
+	if saveErr := file.save(); saveErr != nil {
+		return saveErr
+	}
+	for _, task := range tasks {
+		file.printTask("Deleted: ", task)
+	}
+	return nil
+}
+
+// runDue implements "todotui due"
+func runDue(env *commandEnv, args []string) error {
+	if len(args) != 2 {
+		return usageError("usage: todotui due <N> <date|none>")
+	}
+	var due time.Time
+	if !strings.EqualFold(args[1], noneArg) {
+		parsed, parseErr := env.appConfig.Calendar().ParseDate(args[1], time.Now())
+		if parseErr != nil {
+			return usageError("%v", parseErr)
+		}
+		due = parsed
+	}
+	return updateOne(env, args[0], "Set due date", func(task *domain.Task) error {
+		return task.SetDueDate(due)
+	})
+}
+
+// updateOne applies fn to the task on the line given by arg, saves and prints it
+func updateOne(env *commandEnv, arg, kind string, fn func(*domain.Task) error) error {
+	file, err := openTaskFile(env)
+	if err != nil {
+		return err
+	}
+	task, err := file.task(arg)
+	if err != nil {
+		return err
+	}
+	if editErr := editable(task); editErr != nil {
+		return editErr
+	}
+	if updateErr := file.update(kind, task, fn); updateErr != nil {
+		return updateErr
+	}
+	if saveErr := file.save(); saveErr != nil {
+		return saveErr
+	}
+	file.printTask("", task)
+	return nil
+}
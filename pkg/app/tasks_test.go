@@ -0,0 +1,230 @@
+package app
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yuucu/todotui/pkg/domain"
+)
+
+// 空行を含む todo ファイル。行番号は空行も数える
+const testTodoContent = "Task A\n\n(B) Task B +work\n\nTask C deleted_at:2025-01-01\nx 2025-01-02 Task D\n"
+
+func TestRunCommand(t *testing.T) {
+	today := time.Now().Format(domain.DateFormat)
+
+	tests := []struct {
+		name         string
+		args         []string
+		blockBackups bool // Make saving fail by putting a file where the backups go
+		expectedOut  string
+		expectedFile string
+		expectedCode int
+		description  string
+	}{
+		{
+			name:         "ls",
+			args:         []string{"ls"},
+			expectedOut:  "1 Task A\n3 (B) Task B +work\n",
+			expectedFile: testTodoContent,
+			expectedCode: ExitOK,
+			description:  "空行を飛ばして実際の行番号で表示する",
+		},
+		{
+			name:         "ls_all",
+			args:         []string{"ls", "--all"},
+			expectedOut:  "1 Task A\n3 (B) Task B +work\n5 Task C deleted_at:2025-01-01\n6 x 2025-01-02 Task D\n",
+			expectedFile: testTodoContent,
+			expectedCode: ExitOK,
+			description:  "完了・削除済みのタスクも表示する",
+		},
+		{
+			name:         "add",
+			args:         []string{"add", "Task E"},
+			expectedOut:  "7 Task E\n",
+			expectedFile: testTodoContent + "Task E\n",
+			expectedCode: ExitOK,
+			description:  "追加したタスクの行番号を表示する",
+		},
+		{
+			name:         "do",
+			args:         []string{"do", "3"},
+			expectedOut:  "Done: 3 x " + today + " (B) Task B +work\n",
+			expectedFile: "Task A\n\nx " + today + " (B) Task B +work\n\nTask C deleted_at:2025-01-01\nx 2025-01-02 Task D\n",
+			expectedCode: ExitOK,
+			description:  "空行を残したまま指定した行だけを完了にする",
+		},
+		{
+			name:         "pri",
+			args:         []string{"pri", "1", "A"},
+			expectedOut:  "1 (A) Task A\n",
+			expectedFile: "(A) Task A\n\n(B) Task B +work\n\nTask C deleted_at:2025-01-01\nx 2025-01-02 Task D\n",
+			expectedCode: ExitOK,
+			description:  "優先度を設定する",
+		},
+		{
+			name:         "due",
+			args:         []string{"due", "3", "2025-03-01"},
+			expectedOut:  "3 (B) Task B +work due:2025-03-01\n",
+			expectedFile: "Task A\n\n(B) Task B +work due:2025-03-01\n\nTask C deleted_at:2025-01-01\nx 2025-01-02 Task D\n",
+			expectedCode: ExitOK,
+			description:  "期日を設定する",
+		},
+		{
+			name:         "rm",
+			args:         []string{"rm", "1"},
+			expectedOut:  "Deleted: 1 Task A deleted_at:" + today + "\n",
+			expectedFile: "Task A deleted_at:" + today + "\n\n(B) Task B +work\n\nTask C deleted_at:2025-01-01\nx 2025-01-02 Task D\n",
+			expectedCode: ExitOK,
+			description:  "行を消さずに削除済みにする",
+		},
+		{
+			name:         "do_without_number",
+			args:         []string{"do"},
+			expectedFile: testTodoContent,
+			expectedCode: ExitUsage,
+			description:  "タスク番号がなければ使い方のエラー",
+		},
+		{
+			name:         "do_invalid_number",
+			args:         []string{"do", "abc"},
+			expectedFile: testTodoContent,
+			expectedCode: ExitUsage,
+			description:  "数値でないタスク番号は使い方のエラー",
+		},
+		{
+			name:         "pri_invalid_priority",
+			args:         []string{"pri", "1", "AA"},
+			expectedFile: testTodoContent,
+			expectedCode: ExitUsage,
+			description:  "不正な優先度は使い方のエラー",
+		},
+		{
+			name:         "due_invalid_date",
+			args:         []string{"due", "1", "someday"},
+			expectedFile: testTodoContent,
+			expectedCode: ExitUsage,
+			description:  "解釈できない日付は使い方のエラー",
+		},
+		{
+			name:         "do_blank_line",
+			args:         []string{"do", "2"},
+			expectedFile: testTodoContent,
+			expectedCode: ExitNoTask,
+			description:  "空行にはタスクがない",
+		},
+		{
+			name:         "pri_missing_line",
+			args:         []string{"pri", "9", "A"},
+			expectedFile: testTodoContent,
+			expectedCode: ExitNoTask,
+			description:  "ファイルの末尾より後ろにはタスクがない",
+		},
+		{
+			name:         "do_several_with_missing_line",
+			args:         []string{"do", "1", "9"},
+			expectedFile: testTodoContent,
+			expectedCode: ExitNoTask,
+			description:  "存在しない行が含まれていれば何も変更しない",
+		},
+		{
+			name:         "do_completed",
+			args:         []string{"do", "6"},
+			expectedFile: testTodoContent,
+			expectedCode: ExitError,
+			description:  "完了済みのタスクは完了にできない",
+		},
+		{
+			name:         "do_deleted",
+			args:         []string{"do", "5"},
+			expectedFile: testTodoContent,
+			expectedCode: ExitError,
+			description:  "削除済みのタスクは完了にできない",
+		},
+		{
+			name:         "pri_deleted",
+			args:         []string{"pri", "5", "A"},
+			expectedFile: testTodoContent,
+			expectedCode: ExitError,
+			description:  "削除済みのタスクの優先度は変更できない",
+		},
+		{
+			name:         "due_deleted",
+			args:         []string{"due", "5", "2025-03-01"},
+			expectedFile: testTodoContent,
+			expectedCode: ExitError,
+			description:  "削除済みのタスクの期日は変更できない",
+		},
+		{
+			name:         "rm_deleted",
+			args:         []string{"rm", "5"},
+			expectedFile: testTodoContent,
+			expectedCode: ExitError,
+			description:  "削除済みのタスクは再び削除できない",
+		},
+		{
+			name:         "rm_several_with_deleted",
+			args:         []string{"rm", "1", "5"},
+			expectedFile: testTodoContent,
+			expectedCode: ExitError,
+			description:  "削除済みのタスクが含まれていれば何も削除しない",
+		},
+		{
+			name:         "add_save_failure",
+			args:         []string{"add", "Task E"},
+			blockBackups: true,
+			expectedFile: testTodoContent,
+			expectedCode: ExitError,
+			description:  "保存に失敗したらファイルを変更せずにエラー",
+		},
+		{
+			name:         "do_save_failure",
+			args:         []string{"do", "1"},
+			blockBackups: true,
+			expectedFile: testTodoContent,
+			expectedCode: ExitError,
+			description:  "保存に失敗したらファイルを変更せずにエラー",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// ユーザーの設定ファイルを読み込まないようにする
+			t.Setenv("HOME", t.TempDir())
+
+			dir := t.TempDir()
+			todoPath := filepath.Join(dir, "todo.txt")
+			if err := os.WriteFile(todoPath, []byte(testTodoContent), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if tt.blockBackups {
+				// バックアップ用のディレクトリを作れないので保存が失敗する
+				if err := os.WriteFile(filepath.Join(dir, ".todotui"), nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var stdout, stderr bytes.Buffer
+			cfg := &config{todoFile: todoPath}
+			err := runCommand(cfg, tt.args, strings.NewReader(""), &stdout, &stderr)
+
+			if code := ExitCode(err); code != tt.expectedCode {
+				t.Errorf("ExitCode() = %d (err: %v), expected %d for %s", code, err, tt.expectedCode, tt.description)
+			}
+			if stdout.String() != tt.expectedOut {
+				t.Errorf("stdout = %q, expected %q for %s", stdout.String(), tt.expectedOut, tt.description)
+			}
+			content, readErr := os.ReadFile(todoPath)
+			if readErr != nil {
+				t.Fatal(readErr)
+			}
+			if string(content) != tt.expectedFile {
+				t.Errorf("file content = %q, expected %q for %s", content, tt.expectedFile, tt.description)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// DefaultWeekStart is the first day of the week unless configured otherwise
+const DefaultWeekStart = time.Sunday
+
+// 1週間の日数
+const daysPerWeek = 7
+
+// Calendar answers questions about days and weeks.
+// Day boundaries are midnight in Location, so dates do not shift the way
+// they do with time.Truncate, which works in UTC.
+type Calendar struct {
+	WeekStart time.Weekday
+	Location  *time.Location // nil means time.Local
+}
+
+// NewCalendar creates a calendar in the local time zone whose weeks start on weekStart
+func NewCalendar(weekStart time.Weekday) Calendar {
+	return Calendar{WeekStart: weekStart, Location: time.Local}
+}
+
+// DefaultCalendar returns the calendar used when none is configured
+func DefaultCalendar() Calendar {
+	return NewCalendar(DefaultWeekStart)
+}
+
+// ParseWeekday parses a weekday name such as "monday" or "mon"
+func ParseWeekday(name string) (time.Weekday, error) {
+	weekday, ok := weekdayNames[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return DefaultWeekStart, fmt.Errorf("invalid weekday %q", name)
+	}
+	return weekday, nil
+}
+
+// location returns the time zone of the calendar
+func (c Calendar) location() *time.Location {
+	if c.Location == nil {
+		return time.Local
+	}
+	return c.Location
+}
+
+// StartOfDay returns midnight of the day of t.
+// The day is the date t shows in its own location, so a date parsed from a
+// todo.txt line and the current time are compared on the dates they display.
+func (c Calendar) StartOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, c.location())
+}
+
+// DaysBetween returns the number of calendar days from a to b
+func (c Calendar) DaysBetween(a, b time.Time) int {
+	// 夏時間の切り替えで1日が23時間や25時間になっても日数がずれないよう丸める
+	return int(math.Round(c.StartOfDay(b).Sub(c.StartOfDay(a)).Hours() / 24))
+}
+
+// StartOfWeek returns the first day of the week containing t
+func (c Calendar) StartOfWeek(t time.Time) time.Time {
+	day := c.StartOfDay(t)
+	offset := (int(day.Weekday()) - int(c.WeekStart) + daysPerWeek) % daysPerWeek
+	return day.AddDate(0, 0, -offset)
+}
+
+// EndOfWeek returns the last day of the week containing t
+func (c Calendar) EndOfWeek(t time.Time) time.Time {
+	return c.StartOfWeek(t).AddDate(0, 0, daysPerWeek-1)
+}
+
+// IsSameDay reports whether a and b fall on the same day
+func (c Calendar) IsSameDay(a, b time.Time) bool {
+	return c.StartOfDay(a).Equal(c.StartOfDay(b))
+}
+
+// IsSameWeek reports whether a and b fall in the same week
+func (c Calendar) IsSameWeek(a, b time.Time) bool {
+	return c.StartOfWeek(a).Equal(c.StartOfWeek(b))
+}
+
+// startOfDay returns midnight of the day of t in the default calendar
+func startOfDay(t time.Time) time.Time {
+	return DefaultCalendar().StartOfDay(t)
+}
+
+// daysBetween returns the number of calendar days from a to b in the default calendar
+func daysBetween(a, b time.Time) int {
+	return DefaultCalendar().DaysBetween(a, b)
+}
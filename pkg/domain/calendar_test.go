@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	todotxt "github.com/1set/todotxt"
+)
+
+func TestCalendar_Week(t *testing.T) {
+	// 2025年1月15日（水）
+	now := time.Date(2025, 1, 15, 10, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		weekStart time.Weekday
+		start     time.Time
+		end       time.Time
+	}{
+		{weekStart: time.Sunday, start: date(2025, 1, 12), end: date(2025, 1, 18)},
+		{weekStart: time.Monday, start: date(2025, 1, 13), end: date(2025, 1, 19)},
+		{weekStart: time.Wednesday, start: date(2025, 1, 15), end: date(2025, 1, 21)},
+		{weekStart: time.Thursday, start: date(2025, 1, 9), end: date(2025, 1, 15)},
+		{weekStart: time.Saturday, start: date(2025, 1, 11), end: date(2025, 1, 17)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.weekStart.String(), func(t *testing.T) {
+			calendar := NewCalendar(tt.weekStart)
+			if got := calendar.StartOfWeek(now); !got.Equal(tt.start) {
+				t.Errorf("StartOfWeek() = %s, expected %s", got.Format(DateFormat), tt.start.Format(DateFormat))
+			}
+			if got := calendar.EndOfWeek(now); !got.Equal(tt.end) {
+				t.Errorf("EndOfWeek() = %s, expected %s", got.Format(DateFormat), tt.end.Format(DateFormat))
+			}
+			if got, _ := calendar.ParseDate("eow", now); !got.Equal(tt.end) {
+				t.Errorf("ParseDate(eow) = %s, expected %s", got.Format(DateFormat), tt.end.Format(DateFormat))
+			}
+		})
+	}
+}
+
+func TestCalendar_DayBoundariesInTimeZone(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	newYork := time.FixedZone("EST", -5*60*60)
+
+	tests := []struct {
+		name     string
+		location *time.Location
+		now      time.Time
+		due      time.Time
+		sameDay  bool
+		sameWeek bool
+	}{
+		{
+			// UTCでは前日になる早朝でも、その地域の日付で判定する
+			name:     "early morning east of UTC",
+			location: tokyo,
+			now:      time.Date(2025, 1, 13, 1, 0, 0, 0, tokyo),
+			due:      time.Date(2025, 1, 13, 0, 0, 0, 0, tokyo),
+			sameDay:  true,
+			sameWeek: true,
+		},
+		{
+			// UTCでは翌日になる夜でも、その地域の日付で判定する
+			name:     "late evening west of UTC",
+			location: newYork,
+			now:      time.Date(2025, 1, 19, 22, 0, 0, 0, newYork),
+			due:      time.Date(2025, 1, 19, 0, 0, 0, 0, newYork),
+			sameDay:  true,
+			sameWeek: true,
+		},
+		{
+			name:     "next week starts on Monday",
+			location: newYork,
+			now:      time.Date(2025, 1, 19, 22, 0, 0, 0, newYork),
+			due:      time.Date(2025, 1, 20, 0, 0, 0, 0, newYork),
+			sameDay:  false,
+			sameWeek: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calendar := Calendar{WeekStart: time.Monday, Location: tt.location}
+			if got := calendar.IsSameDay(tt.due, tt.now); got != tt.sameDay {
+				t.Errorf("IsSameDay() = %v, expected %v", got, tt.sameDay)
+			}
+			if got := calendar.IsSameWeek(tt.due, tt.now); got != tt.sameWeek {
+				t.Errorf("IsSameWeek() = %v, expected %v", got, tt.sameWeek)
+			}
+		})
+	}
+}
+
+func TestTask_IsDueThisWeek(t *testing.T) {
+	// 2025年1月19日（日）
+	now := time.Date(2025, 1, 19, 20, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name      string
+		task      string
+		weekStart time.Weekday
+		expected  bool
+	}{
+		{name: "Sunday starts a new week", task: "Task due:2025-01-19", weekStart: time.Sunday, expected: true},
+		{name: "Saturday is last week when weeks start on Sunday", task: "Task due:2025-01-18", weekStart: time.Sunday, expected: false},
+		{name: "Sunday ends the week when weeks start on Monday", task: "Task due:2025-01-19", weekStart: time.Monday, expected: true},
+		{name: "Monday is in the week when weeks start on Monday", task: "Task due:2025-01-13", weekStart: time.Monday, expected: true},
+		{name: "next Monday is next week", task: "Task due:2025-01-20", weekStart: time.Monday, expected: false},
+		{name: "completed task", task: "x 2025-01-19 Task due:2025-01-19", weekStart: time.Sunday, expected: false},
+		{name: "no due date", task: "Task", weekStart: time.Sunday, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todoTask, err := todotxt.ParseTask(tt.task)
+			if err != nil {
+				t.Fatal(err)
+			}
+			task, _ := NewTask(todoTask)
+			if got := task.IsDueThisWeek(NewCalendar(tt.weekStart), now); got != tt.expected {
+				t.Errorf("IsDueThisWeek() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseWeekday(t *testing.T) {
+	for name, expected := range map[string]time.Weekday{"monday": time.Monday, "Sun": time.Sunday, " SATURDAY ": time.Saturday} {
+		if got, err := ParseWeekday(name); err != nil || got != expected {
+			t.Errorf("ParseWeekday(%q) = %v, %v, expected %v", name, got, err, expected)
+		}
+	}
+	if _, err := ParseWeekday("someday"); err == nil {
+		t.Error("ParseWeekday(someday) should fail")
+	}
+}
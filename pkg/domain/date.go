@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidDate is returned for a date expression that cannot be understood
+var ErrInvalidDate = errors.New("invalid date")
+
+// 相対日付（例: +3d, -1w, 2m）
+var relativeDateRx = regexp.MustCompile(`^([+-]?)(\d+)([dbwmy])$`)
+
+// 日付を受け付けるフィールド（例: due:tomorrow, t:+3d）
+var dateFieldRx = regexp.MustCompile(`(^|\s)(` + TaskFieldDue + `|` + TaskFieldThreshold + `):(\S+)`)
+
+// 次の曜日を指定するときの接頭辞（例: next-monday）
+const nextWeekdayPrefix = "next-"
+
+// weekdayNames maps full and abbreviated weekday names to time.Weekday
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// ParseDate parses a date expression relative to now.
+// Besides ISO dates (2025-01-31) it accepts:
+//
+//	today, tomorrow, yesterday (or tod, tom)
+//	mon ... sun, monday ... sunday  the first such day after today
+//	next-mon ... next-sunday        one week after that day
+//	+3d, -1w, 2m, +1y, +5b          days, weeks, months, years or business days from today
+//	eow, eom, eoy                   the last day of this week, month or year
+//
+// The result is midnight of the day in the calendar's time zone.
+func (c Calendar) ParseDate(expr string, now time.Time) (time.Time, error) {
+	value := strings.ToLower(strings.TrimSpace(expr))
+	today := c.StartOfDay(now)
+
+	if parsed, err := time.ParseInLocation(DateFormat, value, c.location()); err == nil {
+		return parsed, nil
+	}
+
+	switch value {
+	case "today", "tod":
+		return today, nil
+	case "tomorrow", "tom":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "eow":
+		return c.EndOfWeek(today), nil
+	case "eom":
+		return time.Date(today.Year(), today.Month()+1, 0, 0, 0, 0, 0, c.location()), nil
+	case "eoy":
+		return time.Date(today.Year(), time.December, 31, 0, 0, 0, 0, c.location()), nil
+	}
+
+	if weekday, ok := weekdayNames[value]; ok {
+		return nextWeekday(today, weekday), nil
+	}
+	if name, ok := strings.CutPrefix(value, nextWeekdayPrefix); ok {
+		if weekday, found := weekdayNames[name]; found {
+			return nextWeekday(today, weekday).AddDate(0, 0, 7), nil
+		}
+	}
+
+	if match := relativeDateRx.FindStringSubmatch(value); match != nil {
+		amount, err := strconv.Atoi(match[2])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidDate, expr)
+		}
+		if match[1] == "-" {
+			amount = -amount
+		}
+		return shiftDate(today, amount, RecurrenceUnit(match[3][0])), nil
+	}
+
+	return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidDate, expr)
+}
+
+// ParseDate parses a date expression relative to now in the default calendar
+func ParseDate(expr string, now time.Time) (time.Time, error) {
+	return DefaultCalendar().ParseDate(expr, now)
+}
+
+// nextWeekday returns the first day after today that falls on weekday
+func nextWeekday(today time.Time, weekday time.Weekday) time.Time {
+	days := (int(weekday) - int(today.Weekday()) + 7) % 7
+	if days == 0 {
+		days = 7
+	}
+	return today.AddDate(0, 0, days)
+}
+
+// NormalizeDates rewrites the due: and t: fields of a task text written with
+// date expressions (due:tomorrow, t:+3d) to ISO dates, so the text can be
+// parsed as todo.txt. The rest of the text is left as it is.
+func (c Calendar) NormalizeDates(text string, now time.Time) (string, error) {
+	var b strings.Builder
+	last := 0
+	for _, match := range dateFieldRx.FindAllStringSubmatchIndex(text, -1) {
+		valueStart, valueEnd := match[6], match[7]
+		parsed, err := c.ParseDate(text[valueStart:valueEnd], now)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", text[match[4]:match[5]], err)
+		}
+		b.WriteString(text[last:valueStart])
+		b.WriteString(parsed.Format(DateFormat))
+		last = valueEnd
+	}
+	b.WriteString(text[last:])
+	return b.String(), nil
+}
+
+// NormalizeDates rewrites date expressions in the due: and t: fields of text
+// to ISO dates in the default calendar
+func NormalizeDates(text string, now time.Time) (string, error) {
+	return DefaultCalendar().NormalizeDates(text, now)
+}
@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	// 基準日時: 2025年1月15日（水）10:30
+	now := time.Date(2025, 1, 15, 10, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		expr     string
+		expected time.Time
+	}{
+		// ISO形式はそのまま
+		{expr: "2025-03-01", expected: date(2025, 3, 1)},
+		{expr: "2024-02-29", expected: date(2024, 2, 29)},
+
+		// 今日・明日・昨日
+		{expr: "today", expected: date(2025, 1, 15)},
+		{expr: "tod", expected: date(2025, 1, 15)},
+		{expr: "tomorrow", expected: date(2025, 1, 16)},
+		{expr: "tom", expected: date(2025, 1, 16)},
+		{expr: "yesterday", expected: date(2025, 1, 14)},
+		{expr: "Tomorrow", expected: date(2025, 1, 16)},
+		{expr: " TODAY ", expected: date(2025, 1, 15)},
+
+		// 曜日は今日より後の最初の日
+		{expr: "thu", expected: date(2025, 1, 16)},
+		{expr: "fri", expected: date(2025, 1, 17)},
+		{expr: "friday", expected: date(2025, 1, 17)},
+		{expr: "sat", expected: date(2025, 1, 18)},
+		{expr: "sun", expected: date(2025, 1, 19)},
+		{expr: "mon", expected: date(2025, 1, 20)},
+		{expr: "tuesday", expected: date(2025, 1, 21)},
+		{expr: "wed", expected: date(2025, 1, 22)},
+		{expr: "Wednesday", expected: date(2025, 1, 22)},
+
+		// next-曜日はその1週間後
+		{expr: "next-fri", expected: date(2025, 1, 24)},
+		{expr: "next-monday", expected: date(2025, 1, 27)},
+		{expr: "next-wed", expected: date(2025, 1, 29)},
+
+		// 相対日付
+		{expr: "+0d", expected: date(2025, 1, 15)},
+		{expr: "+3d", expected: date(2025, 1, 18)},
+		{expr: "3d", expected: date(2025, 1, 18)},
+		{expr: "-3d", expected: date(2025, 1, 12)},
+		{expr: "+20d", expected: date(2025, 2, 4)},
+		{expr: "+2w", expected: date(2025, 1, 29)},
+		{expr: "-1w", expected: date(2025, 1, 8)},
+		{expr: "+1m", expected: date(2025, 2, 15)},
+		{expr: "-2m", expected: date(2024, 11, 15)},
+		{expr: "+1y", expected: date(2026, 1, 15)},
+		{expr: "+3b", expected: date(2025, 1, 20)},
+		{expr: "-3b", expected: date(2025, 1, 10)},
+
+		// 期間の終わり
+		{expr: "eow", expected: date(2025, 1, 18)},
+		{expr: "eom", expected: date(2025, 1, 31)},
+		{expr: "eoy", expected: date(2025, 12, 31)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := ParseDate(tt.expr, now)
+			if err != nil {
+				t.Fatalf("ParseDate(%q) failed: %v", tt.expr, err)
+			}
+			if !got.Equal(tt.expected) {
+				t.Errorf("ParseDate(%q) = %s, expected %s", tt.expr, got.Format(DateFormat), tt.expected.Format(DateFormat))
+			}
+		})
+	}
+}
+
+func TestParseDate_Boundaries(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     string
+		now      time.Time
+		expected time.Time
+	}{
+		{name: "same weekday is a week ahead", expr: "fri", now: date(2025, 1, 17), expected: date(2025, 1, 24)},
+		{name: "next same weekday is two weeks ahead", expr: "next-fri", now: date(2025, 1, 17), expected: date(2025, 1, 31)},
+		{name: "tomorrow across year end", expr: "tomorrow", now: date(2025, 12, 31), expected: date(2026, 1, 1)},
+		{name: "month clamps to end of February", expr: "+1m", now: date(2025, 1, 31), expected: date(2025, 2, 28)},
+		{name: "end of February in a leap year", expr: "eom", now: date(2024, 2, 10), expected: date(2024, 2, 29)},
+		{name: "end of December", expr: "eom", now: date(2025, 12, 5), expected: date(2025, 12, 31)},
+		{name: "end of week on Saturday is today", expr: "eow", now: date(2025, 1, 18), expected: date(2025, 1, 18)},
+		{name: "end of week on Sunday", expr: "eow", now: date(2025, 1, 19), expected: date(2025, 1, 25)},
+		{name: "business day from Friday", expr: "+1b", now: date(2025, 1, 17), expected: date(2025, 1, 20)},
+		{name: "business day back from Monday", expr: "-1b", now: date(2025, 1, 20), expected: date(2025, 1, 17)},
+		{name: "late evening counts as the same day", expr: "tom", now: time.Date(2025, 1, 15, 23, 59, 0, 0, time.Local), expected: date(2025, 1, 16)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDate(tt.expr, tt.now)
+			if err != nil {
+				t.Fatalf("ParseDate(%q) failed: %v", tt.expr, err)
+			}
+			if !got.Equal(tt.expected) {
+				t.Errorf("ParseDate(%q) = %s, expected %s", tt.expr, got.Format(DateFormat), tt.expected.Format(DateFormat))
+			}
+		})
+	}
+}
+
+func TestParseDate_Invalid(t *testing.T) {
+	now := date(2025, 1, 15)
+	for _, expr := range []string{"", "soon", "next-week", "next-", "+d", "3x", "++3d", "2025-02-30", "2025/01/31", "fri-"} {
+		t.Run(expr, func(t *testing.T) {
+			if got, err := ParseDate(expr, now); !errors.Is(err, ErrInvalidDate) {
+				t.Errorf("ParseDate(%q) = %s, %v, expected ErrInvalidDate", expr, got, err)
+			}
+		})
+	}
+}
+
+func TestNormalizeDates(t *testing.T) {
+	now := time.Date(2025, 1, 15, 10, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		name      string
+		text      string
+		expected  string
+		expectErr bool
+	}{
+		{name: "due date expression", text: "Call Bob due:tomorrow", expected: "Call Bob due:2025-01-16"},
+		{name: "threshold and due", text: "(A) Report t:+3d due:fri +work", expected: "(A) Report t:2025-01-18 due:2025-01-17 +work"},
+		{name: "next weekday", text: "Meeting due:next-monday", expected: "Meeting due:2025-01-27"},
+		{name: "end of month", text: "Pay rent due:eom rec:+1m", expected: "Pay rent due:2025-01-31 rec:+1m"},
+		{name: "ISO dates are kept", text: "Task due:2025-03-01 t:2025-02-01", expected: "Task due:2025-03-01 t:2025-02-01"},
+		{name: "field at the start", text: "due:today Task", expected: "due:2025-01-15 Task"},
+		{name: "other tags are untouched", text: "Task at:tomorrow overdue:fri", expected: "Task at:tomorrow overdue:fri"},
+		{name: "spacing is kept", text: "Task  due:tom", expected: "Task  due:2025-01-16"},
+		{name: "no dates", text: "x 2025-01-14 Done task", expected: "x 2025-01-14 Done task"},
+		{name: "unknown expression", text: "Task due:someday", expectErr: true},
+		{name: "unknown threshold", text: "Task t:later", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeDates(tt.text, now)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("NormalizeDates(%q) error = %v, expectErr %v", tt.text, err, tt.expectErr)
+			}
+			if tt.expectErr {
+				if !errors.Is(err, ErrInvalidDate) {
+					t.Errorf("NormalizeDates(%q) error = %v, expected ErrInvalidDate", tt.text, err)
+				}
+				return
+			}
+			if got != tt.expected {
+				t.Errorf("NormalizeDates(%q) = %q, expected %q", tt.text, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidEdit is returned for a bulk edit that cannot be understood
+var ErrInvalidEdit = errors.New("invalid edit")
+
+// 期限を削除する値（例: due:none）
+const dueNone = "none"
+
+// TaskEdits are changes applied to several tasks at once
+type TaskEdits struct {
+	AddTags    []string   // Projects and contexts to add, e.g. "+work"
+	RemoveTags []string   // Projects and contexts to remove
+	Due        *time.Time // New due date; nil leaves it, the zero time removes it
+}
+
+// ParseTaskEdits parses a list of edits separated by spaces:
+//
+//	+project, @context    add the project or context
+//	-+project, -@context  remove it
+//	due:<date>            set the due date; any date shortcut such as tomorrow or +3d works
+//	due:none, -due        remove the due date
+func (c Calendar) ParseTaskEdits(text string, now time.Time) (TaskEdits, error) {
+	var edits TaskEdits
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return edits, fmt.Errorf("%w: nothing to change", ErrInvalidEdit)
+	}
+
+	for _, field := range fields {
+		switch {
+		case isProjectOrContext(field):
+			edits.AddTags = append(edits.AddTags, field)
+		case strings.HasPrefix(field, "-") && isProjectOrContext(field[1:]):
+			edits.RemoveTags = append(edits.RemoveTags, field[1:])
+		case field == "-"+TaskFieldDue || strings.EqualFold(field, TaskFieldDuePrefix+dueNone):
+			edits.Due = &time.Time{}
+		case strings.HasPrefix(field, TaskFieldDuePrefix):
+			due, err := c.ParseDate(strings.TrimPrefix(field, TaskFieldDuePrefix), now)
+			if err != nil {
+				return TaskEdits{}, fmt.Errorf("%w: %s: %w", ErrInvalidEdit, field, err)
+			}
+			edits.Due = &due
+		default:
+			return TaskEdits{}, fmt.Errorf("%w: %q is not +project, @context or due:", ErrInvalidEdit, field)
+		}
+	}
+	return edits, nil
+}
+
+// Apply makes the edits to task
+func (e TaskEdits) Apply(task *Task) error {
+	for _, tag := range e.RemoveTags {
+		if err := task.RemoveTag(tag); err != nil {
+			return err
+		}
+	}
+	for _, tag := range e.AddTags {
+		if err := task.AddTag(tag); err != nil {
+			return err
+		}
+	}
+	if e.Due != nil {
+		return task.SetDueDate(*e.Due)
+	}
+	return nil
+}
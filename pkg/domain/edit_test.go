@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	todotxt "github.com/1set/todotxt"
+)
+
+func TestParseTaskEdits(t *testing.T) {
+	// 基準日時: 2025年1月15日（水）
+	now := time.Date(2025, 1, 15, 9, 0, 0, 0, time.Local)
+	calendar := NewCalendar(time.Sunday)
+
+	tests := []struct {
+		name     string
+		line     string
+		edits    string
+		expected string
+	}{
+		{name: "add project", line: "Write report", edits: "+work", expected: "Write report +work"},
+		{name: "add existing project", line: "Write report +Work", edits: "+work", expected: "Write report +Work"},
+		{name: "remove context", line: "Call mom @phone @home", edits: "-@PHONE", expected: "Call mom @home"},
+		{name: "move project", line: "Task +inbox due:2025-01-20", edits: "-+inbox +work", expected: "Task +work due:2025-01-20"},
+		{name: "set due date", line: "(A) Task", edits: "due:tomorrow", expected: "(A) Task due:2025-01-16"},
+		{name: "replace due date", line: "Task due:2025-01-01 +work", edits: "due:fri", expected: "Task +work due:2025-01-17"},
+		{name: "remove due date", line: "Task due:2025-01-01", edits: "-due", expected: "Task"},
+		{name: "remove due date with none", line: "Task due:2025-01-01", edits: "due:none", expected: "Task"},
+		{name: "several edits", line: "Task", edits: "+work @office due:+1w", expected: "Task @office +work due:2025-01-22"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edits, err := calendar.ParseTaskEdits(tt.edits, now)
+			if err != nil {
+				t.Fatalf("ParseTaskEdits(%q) failed: %v", tt.edits, err)
+			}
+			todoTask, _ := todotxt.ParseTask(tt.line)
+			task, _ := NewTask(todoTask)
+			if err := edits.Apply(task); err != nil {
+				t.Fatalf("Apply failed: %v", err)
+			}
+			if got := task.String(); got != tt.expected {
+				t.Errorf("%q with %q = %q, expected %q", tt.line, tt.edits, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseTaskEdits_Invalid(t *testing.T) {
+	now := time.Date(2025, 1, 15, 9, 0, 0, 0, time.Local)
+	for _, edits := range []string{"", "  ", "work", "+", "-@", "due:someday", "pri:A"} {
+		if _, err := DefaultCalendar().ParseTaskEdits(edits, now); !errors.Is(err, ErrInvalidEdit) {
+			t.Errorf("ParseTaskEdits(%q) error = %v, expected ErrInvalidEdit", edits, err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+)
+
+// FuzzyMatch reports whether every word of pattern appears in text as a
+// subsequence, ignoring case, e.g. "rprt wk" matches "Write report +work".
+// It returns the rune positions in text of the matched characters in
+// ascending order, so they can be highlighted.
+func FuzzyMatch(pattern, text string) ([]int, bool) {
+	words := strings.Fields(pattern)
+	if len(words) == 0 {
+		return nil, true
+	}
+
+	target := toLowerRunes(text)
+	matched := make(map[int]bool)
+	for _, word := range words {
+		positions, ok := fuzzyMatchWord(toLowerRunes(word), target)
+		if !ok {
+			return nil, false
+		}
+		for _, position := range positions {
+			matched[position] = true
+		}
+	}
+
+	positions := make([]int, 0, len(matched))
+	for i := range target {
+		if matched[i] {
+			positions = append(positions, i)
+		}
+	}
+	return positions, true
+}
+
+// fuzzyMatchWord finds pattern in target as a subsequence.
+// After the first match is found, it is narrowed down from its end so that
+// "rep" highlights "report" rather than the scattered letters of "Write report".
+func fuzzyMatchWord(pattern, target []rune) ([]int, bool) {
+	// 前方から走査して最初にマッチが完成する位置を探す
+	end := -1
+	p := 0
+	for i, r := range target {
+		if r == pattern[p] {
+			p++
+			if p == len(pattern) {
+				end = i
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return nil, false
+	}
+
+	// 後方から走査して最も短いマッチの開始位置を探す
+	start := end
+	p = len(pattern) - 1
+	for i := end; i >= 0; i-- {
+		if target[i] == pattern[p] {
+			p--
+			if p < 0 {
+				start = i
+				break
+			}
+		}
+	}
+
+	positions := make([]int, 0, len(pattern))
+	p = 0
+	for i := start; i <= end && p < len(pattern); i++ {
+		if target[i] == pattern[p] {
+			positions = append(positions, i)
+			p++
+		}
+	}
+	return positions, true
+}
+
+// toLowerRunes lower-cases s rune by rune, so positions still line up with s
+func toLowerRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
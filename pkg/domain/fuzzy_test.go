@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+
+	todotxt "github.com/1set/todotxt"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   string
+		text      string
+		matched   bool
+		positions []int
+	}{
+		{name: "空のパターンは全てにマッチ", pattern: "", text: "Buy milk", matched: true, positions: nil},
+		{name: "部分文字列", pattern: "milk", text: "Buy milk", matched: true, positions: []int{4, 5, 6, 7}},
+		{name: "大文字小文字を無視", pattern: "BUY", text: "buy milk", matched: true, positions: []int{0, 1, 2}},
+		{name: "飛び飛びの文字", pattern: "bmk", text: "Buy milk", matched: true, positions: []int{0, 4, 7}},
+		{name: "最短のマッチを選ぶ", pattern: "rep", text: "Write report", matched: true, positions: []int{6, 7, 8}},
+		{name: "複数の単語", pattern: "rpt +wo", text: "Write report +work", matched: true, positions: []int{6, 8, 11, 13, 14, 15}},
+		{name: "順序が違う", pattern: "klim", text: "Buy milk", matched: false},
+		{name: "単語の一つがマッチしない", pattern: "buy eggs", text: "Buy milk", matched: false},
+		{name: "マルチバイト文字", pattern: "会議", text: "明日の会議 @office", matched: true, positions: []int{3, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			positions, matched := FuzzyMatch(tt.pattern, tt.text)
+			if matched != tt.matched {
+				t.Fatalf("FuzzyMatch(%q, %q) matched = %v, expected %v", tt.pattern, tt.text, matched, tt.matched)
+			}
+			if tt.positions != nil && !slices.Equal(positions, tt.positions) {
+				t.Errorf("FuzzyMatch(%q, %q) positions = %v, expected %v", tt.pattern, tt.text, positions, tt.positions)
+			}
+		})
+	}
+}
+
+func TestTask_SearchText(t *testing.T) {
+	todoTask, err := todotxt.ParseTask("(A) 2025-01-01 Write report +work @office due:2025-01-17 t:2025-01-10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	task, _ := NewTask(todoTask)
+
+	if got := task.SearchText(); got != "Write report +work @office" {
+		t.Errorf("SearchText() = %q", got)
+	}
+}
@@ -0,0 +1,458 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrInvalidQuery is returned for a query that cannot be parsed
+var ErrInvalidQuery = errors.New("invalid query")
+
+// クエリの演算子（大文字のみ。小文字は検索語として扱う）
+const (
+	queryAnd = "AND"
+	queryOr  = "OR"
+	queryNot = "NOT"
+)
+
+// クエリで使えるフィールド名
+const (
+	queryFieldPriority  = "pri"
+	queryFieldStatus    = "status"
+	queryFieldCreated   = "created"
+	queryFieldCompleted = "completed"
+)
+
+// Status values accepted by status: in a query
+const (
+	QueryStatusOpen     = "open"
+	QueryStatusDone     = "done"
+	QueryStatusDeleted  = "deleted"
+	QueryStatusUpcoming = "upcoming"
+	QueryStatusOverdue  = "overdue"
+)
+
+// 日付・優先度の範囲指定の区切り（例: pri:A..B, due:today..+7d）
+const queryRangeSeparator = ".."
+
+// 値が「ある」「ない」ことを表すキーワード
+const (
+	queryValueAny  = "any"
+	queryValueNone = "none"
+)
+
+// queryContext is what a query is evaluated against besides the task
+type queryContext struct {
+	calendar Calendar
+	now      time.Time
+}
+
+// queryPredicate reports whether a task matches part of a query
+type queryPredicate func(t *Task, ctx queryContext) bool
+
+// Query is a parsed filter expression such as
+//
+//	+work @office pri:A..B due:<=+7d -status:done "weekly report"
+//
+// Terms next to each other must all match; AND, OR, NOT and parentheses
+// combine them, and a leading "-" negates a term. Supported terms:
+//
+//	+project, @context          the task has the project or context
+//	pri:A, pri:A..C, pri:none   the priority, a range of priorities, or none
+//	due:, t:, created:, completed:
+//	                            a date expression (see ParseDate), optionally with
+//	                            <, <=, > or >=, a range (today..+7d), any or none
+//	status:open|done|deleted|upcoming|overdue
+//	key:value, key:*            any other tag with the value, or with any value
+//	word, "some words"          text contained in the task description
+//
+// Relative dates are resolved when the query is matched, so a saved query
+// keeps meaning the same thing as days pass.
+type Query struct {
+	source string
+	match  queryPredicate
+}
+
+// ParseQuery parses a query. An empty query matches every task.
+func ParseQuery(input string) (*Query, error) {
+	tokens, err := tokenizeQuery(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{tokens: tokens}
+	match := func(*Task, queryContext) bool { return true }
+	if len(tokens) > 0 {
+		match, err = p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.done() {
+			return nil, fmt.Errorf("%w: unexpected %q", ErrInvalidQuery, p.peek().text)
+		}
+	}
+	return &Query{source: strings.TrimSpace(input), match: match}, nil
+}
+
+// String returns the query as it was written
+func (q *Query) String() string {
+	return q.source
+}
+
+// Match reports whether the task matches the query on the day of now
+func (q *Query) Match(task *Task, calendar Calendar, now time.Time) bool {
+	return q.match(task, queryContext{calendar: calendar, now: now})
+}
+
+// Filter returns a TaskFilter keeping the tasks that match the query
+func (q *Query) Filter(calendar Calendar, now time.Time) TaskFilter {
+	return func(tasks Tasks) Tasks {
+		return tasks.Filter(func(task Task, _ int) bool {
+			return q.Match(&task, calendar, now)
+		})
+	}
+}
+
+// queryTokenKind is the kind of a query token
+type queryTokenKind int
+
+const (
+	queryTokenWord queryTokenKind = iota
+	queryTokenText                // quoted text
+	queryTokenOpen
+	queryTokenClose
+	queryTokenNegate // "-" directly in front of a term
+)
+
+// queryToken is one token of a query
+type queryToken struct {
+	kind queryTokenKind
+	text string
+}
+
+// tokenizeQuery splits a query into words, quoted text, parentheses and negations
+func tokenizeQuery(input string) ([]queryToken, error) {
+	var tokens []queryToken
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, queryToken{kind: queryTokenOpen, text: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, queryToken{kind: queryTokenClose, text: ")"})
+			i++
+		case r == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]):
+			tokens = append(tokens, queryToken{kind: queryTokenNegate, text: "-"})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("%w: unterminated quote", ErrInvalidQuery)
+			}
+			tokens = append(tokens, queryToken{kind: queryTokenText, text: string(runes[i+1 : end])})
+			i = end + 1
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && runes[end] != '(' && runes[end] != ')' {
+				end++
+			}
+			tokens = append(tokens, queryToken{kind: queryTokenWord, text: string(runes[i:end])})
+			i = end
+		}
+	}
+	return tokens, nil
+}
+
+// queryParser is a recursive descent parser over query tokens.
+// NOT binds tighter than AND, which binds tighter than OR.
+type queryParser struct {
+	tokens []queryToken
+	pos    int
+}
+
+func (p *queryParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *queryParser) peek() queryToken {
+	return p.tokens[p.pos]
+}
+
+// isOperator reports whether the next token is the given operator word
+func (p *queryParser) isOperator(op string) bool {
+	return !p.done() && p.peek().kind == queryTokenWord && p.peek().text == op
+}
+
+func (p *queryParser) parseOr() (queryPredicate, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	alternatives := []queryPredicate{first}
+	for p.isOperator(queryOr) {
+		p.pos++
+		next, nextErr := p.parseAnd()
+		if nextErr != nil {
+			return nil, nextErr
+		}
+		alternatives = append(alternatives, next)
+	}
+	if len(alternatives) == 1 {
+		return first, nil
+	}
+	return func(t *Task, ctx queryContext) bool {
+		for _, match := range alternatives {
+			if match(t, ctx) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func (p *queryParser) parseAnd() (queryPredicate, error) {
+	first, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	terms := []queryPredicate{first}
+	for !p.done() && !p.isOperator(queryOr) && p.peek().kind != queryTokenClose {
+		if p.isOperator(queryAnd) {
+			p.pos++
+		}
+		next, nextErr := p.parseUnary()
+		if nextErr != nil {
+			return nil, nextErr
+		}
+		terms = append(terms, next)
+	}
+	if len(terms) == 1 {
+		return first, nil
+	}
+	return func(t *Task, ctx queryContext) bool {
+		for _, match := range terms {
+			if !match(t, ctx) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+func (p *queryParser) parseUnary() (queryPredicate, error) {
+	if p.done() {
+		return nil, fmt.Errorf("%w: unexpected end of query", ErrInvalidQuery)
+	}
+	token := p.peek()
+	switch {
+	case token.kind == queryTokenNegate || p.isOperator(queryNot):
+		p.pos++
+		match, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(t *Task, ctx queryContext) bool { return !match(t, ctx) }, nil
+	case token.kind == queryTokenOpen:
+		p.pos++
+		match, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.done() || p.peek().kind != queryTokenClose {
+			return nil, fmt.Errorf("%w: missing )", ErrInvalidQuery)
+		}
+		p.pos++
+		return match, nil
+	case token.kind == queryTokenClose:
+		return nil, fmt.Errorf("%w: unexpected )", ErrInvalidQuery)
+	case token.kind == queryTokenText:
+		p.pos++
+		return textPredicate(token.text), nil
+	case p.isOperator(queryAnd) || p.isOperator(queryOr):
+		return nil, fmt.Errorf("%w: %s needs a term before it", ErrInvalidQuery, token.text)
+	default:
+		p.pos++
+		return parseQueryTerm(token.text)
+	}
+}
+
+// parseQueryTerm parses a single word of a query
+func parseQueryTerm(word string) (queryPredicate, error) {
+	if name, ok := strings.CutPrefix(word, "+"); ok && name != "" {
+		return func(t *Task, _ queryContext) bool { return containsFold(t.task.Projects, name) }, nil
+	}
+	if name, ok := strings.CutPrefix(word, "@"); ok && name != "" {
+		return func(t *Task, _ queryContext) bool { return containsFold(t.task.Contexts, name) }, nil
+	}
+	key, value, ok := strings.Cut(word, ":")
+	if !ok || key == "" || value == "" {
+		return textPredicate(word), nil
+	}
+
+	switch strings.ToLower(key) {
+	case queryFieldPriority:
+		return priorityPredicate(value)
+	case queryFieldStatus:
+		return statusPredicate(value)
+	case TaskFieldDue:
+		return datePredicate(value, func(t *Task) (time.Time, bool) {
+			return t.task.DueDate, t.task.HasDueDate()
+		})
+	case TaskFieldThreshold:
+		return datePredicate(value, (*Task).GetThresholdDate)
+	case queryFieldCreated:
+		return datePredicate(value, func(t *Task) (time.Time, bool) {
+			return t.task.CreatedDate, t.task.HasCreatedDate()
+		})
+	case queryFieldCompleted:
+		return datePredicate(value, func(t *Task) (time.Time, bool) {
+			return t.task.CompletedDate, t.task.HasCompletedDate()
+		})
+	}
+
+	// Any other key is matched against the task's tags
+	return func(t *Task, _ queryContext) bool {
+		tagValue, found := t.task.AdditionalTags[key]
+		return found && (value == "*" || strings.EqualFold(tagValue, value))
+	}, nil
+}
+
+// textPredicate matches tasks whose description contains text, ignoring case
+func textPredicate(text string) queryPredicate {
+	text = strings.ToLower(text)
+	return func(t *Task, _ queryContext) bool {
+		return strings.Contains(strings.ToLower(t.task.Todo), text)
+	}
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// priorityPredicate parses pri:A, pri:A..C, pri:any and pri:none
+func priorityPredicate(value string) (queryPredicate, error) {
+	switch strings.ToLower(value) {
+	case queryValueAny:
+		return func(t *Task, _ queryContext) bool { return t.HasPriority() }, nil
+	case queryValueNone:
+		return func(t *Task, _ queryContext) bool { return !t.HasPriority() }, nil
+	}
+
+	low, high, isRange := strings.Cut(strings.ToUpper(value), queryRangeSeparator)
+	if !isRange {
+		high = low
+	}
+	if !isPriorityLetter(low) || !isPriorityLetter(high) {
+		return nil, fmt.Errorf("%w: invalid priority %q", ErrInvalidQuery, value)
+	}
+	// A is the highest priority, so A..C and C..A mean the same
+	if low > high {
+		low, high = high, low
+	}
+	return func(t *Task, _ queryContext) bool {
+		priority := t.GetPriority()
+		return priority != "" && priority >= low && priority <= high
+	}, nil
+}
+
+// isPriorityLetter reports whether s is a single priority letter A-Z
+func isPriorityLetter(s string) bool {
+	return len(s) == 1 && s[0] >= 'A' && s[0] <= 'Z'
+}
+
+// statusPredicate parses status:open, done, deleted, upcoming and overdue
+func statusPredicate(value string) (queryPredicate, error) {
+	switch strings.ToLower(value) {
+	case QueryStatusOpen:
+		return func(t *Task, _ queryContext) bool { return !t.IsCompleted() && !t.IsDeleted() }, nil
+	case QueryStatusDone:
+		return func(t *Task, _ queryContext) bool { return t.IsCompleted() && !t.IsDeleted() }, nil
+	case QueryStatusDeleted:
+		return func(t *Task, _ queryContext) bool { return t.IsDeleted() }, nil
+	case QueryStatusUpcoming:
+		return func(t *Task, ctx queryContext) bool { return t.IsUpcoming(ctx.now) }, nil
+	case QueryStatusOverdue:
+		return func(t *Task, ctx queryContext) bool { return t.IsOverdue(ctx.now) }, nil
+	}
+	return nil, fmt.Errorf("%w: invalid status %q", ErrInvalidQuery, value)
+}
+
+// datePredicate parses a date condition such as today, <=+7d, 2025-01-01..eom, any or none.
+// date returns the date of the task that is compared and whether it has one.
+func datePredicate(value string, date func(*Task) (time.Time, bool)) (queryPredicate, error) {
+	switch strings.ToLower(value) {
+	case queryValueAny:
+		return func(t *Task, _ queryContext) bool { _, ok := date(t); return ok }, nil
+	case queryValueNone:
+		return func(t *Task, _ queryContext) bool { _, ok := date(t); return !ok }, nil
+	}
+
+	// bound is one side of the condition; cmp compares the task's day with the bound
+	type bound struct {
+		expr string
+		cmp  func(day, bound time.Time) bool
+	}
+	var bounds []bound
+	if low, high, isRange := strings.Cut(value, queryRangeSeparator); isRange {
+		bounds = []bound{
+			{expr: low, cmp: func(day, b time.Time) bool { return !day.Before(b) }},
+			{expr: high, cmp: func(day, b time.Time) bool { return !day.After(b) }},
+		}
+	} else {
+		operators := []struct {
+			prefix string
+			cmp    func(day, b time.Time) bool
+		}{
+			{prefix: "<=", cmp: func(day, b time.Time) bool { return !day.After(b) }},
+			{prefix: ">=", cmp: func(day, b time.Time) bool { return !day.Before(b) }},
+			{prefix: "<", cmp: func(day, b time.Time) bool { return day.Before(b) }},
+			{prefix: ">", cmp: func(day, b time.Time) bool { return day.After(b) }},
+			{prefix: "=", cmp: func(day, b time.Time) bool { return day.Equal(b) }},
+		}
+		b := bound{expr: value, cmp: func(day, b time.Time) bool { return day.Equal(b) }}
+		for _, op := range operators {
+			if expr, ok := strings.CutPrefix(value, op.prefix); ok {
+				b = bound{expr: expr, cmp: op.cmp}
+				break
+			}
+		}
+		bounds = []bound{b}
+	}
+
+	// Check the date expressions now so mistakes are reported while typing
+	for _, b := range bounds {
+		if _, err := ParseDate(b.expr, time.Now()); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidQuery, err)
+		}
+	}
+
+	return func(t *Task, ctx queryContext) bool {
+		taskDate, ok := date(t)
+		if !ok {
+			return false
+		}
+		day := ctx.calendar.StartOfDay(taskDate)
+		for _, b := range bounds {
+			boundDate, err := ctx.calendar.ParseDate(b.expr, ctx.now)
+			if err != nil || !b.cmp(day, boundDate) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
@@ -0,0 +1,162 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	todotxt "github.com/1set/todotxt"
+)
+
+// queryTestTasks is the task list the query tests are run against; tests refer to tasks by index
+var queryTestTasks = []string{
+	"(A) Write report +work @office due:2025-01-17",
+	"(B) Review budget +work @home due:2025-01-25 t:2025-01-20",
+	"(C) Call plumber +home @phone due:2025-01-10",
+	"Buy milk @store",
+	"x 2025-01-14 2025-01-01 Send invoice +work due:2025-01-13",
+	"Old idea +work deleted_at:2025-01-02",
+	"2025-01-05 Plan \"weekly report\" meeting +work waiting:bob",
+	"(D) Renew passport due:2025-03-01 t:2025-02-01 rec:+1y @errands",
+}
+
+func TestParseQuery(t *testing.T) {
+	// 基準日時: 2025年1月15日（水）
+	now := time.Date(2025, 1, 15, 9, 0, 0, 0, time.Local)
+	calendar := NewCalendar(time.Sunday)
+
+	var tasks Tasks
+	for _, line := range queryTestTasks {
+		todoTask, err := todotxt.ParseTask(line)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", line, err)
+		}
+		task, _ := NewTask(todoTask)
+		tasks = append(tasks, *task)
+	}
+
+	tests := []struct {
+		query    string
+		expected []int
+	}{
+		{query: "", expected: []int{0, 1, 2, 3, 4, 5, 6, 7}},
+		{query: "+work", expected: []int{0, 1, 4, 5, 6}},
+		{query: "+WORK", expected: []int{0, 1, 4, 5, 6}},
+		{query: "@office", expected: []int{0}},
+		{query: "+work @home", expected: []int{1}},
+		{query: "+work AND @home", expected: []int{1}},
+		{query: "@home OR @phone", expected: []int{1, 2}},
+		{query: "+work -status:done -status:deleted", expected: []int{0, 1, 6}},
+		{query: "+work NOT status:open", expected: []int{4, 5}},
+		{query: "status:open", expected: []int{0, 1, 2, 3, 6, 7}},
+		{query: "status:done", expected: []int{4}},
+		{query: "status:deleted", expected: []int{5}},
+		{query: "status:upcoming", expected: []int{1, 7}},
+		{query: "status:overdue", expected: []int{2}},
+		{query: "pri:A", expected: []int{0}},
+		{query: "pri:a..b", expected: []int{0, 1}},
+		{query: "pri:C..A", expected: []int{0, 1, 2}},
+		{query: "pri:none", expected: []int{3, 4, 5, 6}},
+		{query: "pri:any", expected: []int{0, 1, 2, 7}},
+		{query: "due:<=+7d", expected: []int{0, 2, 4}},
+		{query: "due:<today", expected: []int{2, 4}},
+		{query: "due:>today", expected: []int{0, 1, 7}},
+		{query: "due:fri", expected: []int{0}},
+		{query: "due:=2025-01-17", expected: []int{0}},
+		{query: "due:today..eom", expected: []int{0, 1}},
+		{query: "due:>=2025-01-25", expected: []int{1, 7}},
+		{query: "due:none", expected: []int{3, 5, 6}},
+		{query: "due:any -due:<today", expected: []int{0, 1, 7}},
+		{query: "t:any", expected: []int{1, 7}},
+		{query: "t:>eom", expected: []int{7}},
+		{query: "created:2025-01-05", expected: []int{6}},
+		{query: "completed:yesterday", expected: []int{4}},
+		{query: "rec:+1y", expected: []int{7}},
+		{query: "waiting:*", expected: []int{6}},
+		{query: "waiting:alice", expected: []int{}},
+		{query: "report", expected: []int{0, 6}},
+		{query: "\"weekly report\"", expected: []int{6}},
+		{query: "-report +work", expected: []int{1, 4, 5}},
+		{query: "milk OR plumber OR invoice", expected: []int{2, 3, 4}},
+		{query: "(+home OR @home) pri:B..C", expected: []int{1, 2}},
+		{query: "NOT (+work OR +home)", expected: []int{3, 7}},
+		{query: "-(+work OR +home) -@store", expected: []int{7}},
+		{query: "+work @office OR @errands", expected: []int{0, 7}},
+		{query: "+work (@office OR @home) due:<=+7d -status:done", expected: []int{0}},
+		{query: "NOT NOT @store", expected: []int{3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			query, err := ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ParseQuery(%q) failed: %v", tt.query, err)
+			}
+			var got []int
+			for i := range tasks {
+				if query.Match(&tasks[i], calendar, now) {
+					got = append(got, i)
+				}
+			}
+			if len(got) != len(tt.expected) {
+				t.Fatalf("ParseQuery(%q) matched %v, expected %v", tt.query, got, tt.expected)
+			}
+			for i := range got {
+				if got[i] != tt.expected[i] {
+					t.Fatalf("ParseQuery(%q) matched %v, expected %v", tt.query, got, tt.expected)
+				}
+			}
+			if filtered := query.Filter(calendar, now)(tasks); filtered.Len() != len(tt.expected) {
+				t.Errorf("Filter() returned %d tasks, expected %d", filtered.Len(), len(tt.expected))
+			}
+		})
+	}
+}
+
+func TestParseQuery_Invalid(t *testing.T) {
+	tests := []string{
+		"(+work",
+		"+work)",
+		"()",
+		"\"unterminated",
+		"OR +work",
+		"+work AND",
+		"+work OR",
+		"NOT",
+		"pri:AB",
+		"pri:1",
+		"pri:A..",
+		"status:maybe",
+		"due:someday",
+		"due:<=soon",
+		"t:today..later",
+	}
+
+	for _, query := range tests {
+		t.Run(query, func(t *testing.T) {
+			if _, err := ParseQuery(query); !errors.Is(err, ErrInvalidQuery) {
+				t.Errorf("ParseQuery(%q) error = %v, expected ErrInvalidQuery", query, err)
+			}
+		})
+	}
+}
+
+func TestQuery_RelativeDatesFollowNow(t *testing.T) {
+	todoTask, _ := todotxt.ParseTask("Report due:2025-01-20")
+	task, _ := NewTask(todoTask)
+	query, err := ParseQuery("due:<=+3d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	calendar := DefaultCalendar()
+
+	if query.Match(task, calendar, date(2025, 1, 15)) {
+		t.Error("task due in 5 days should not match due:<=+3d")
+	}
+	if !query.Match(task, calendar, date(2025, 1, 17)) {
+		t.Error("task due in 3 days should match due:<=+3d")
+	}
+	if query.String() != "due:<=+3d" {
+		t.Errorf("String() = %q", query.String())
+	}
+}
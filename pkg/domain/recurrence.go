@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	todotxt "github.com/1set/todotxt"
+)
+
+// rec:タグの値（例: 1w, +1m, 3d）
+var recurrenceRx = regexp.MustCompile(`^(\+?)(\d+)([dbwmy])$`)
+
+// RecurrenceUnit is the unit of a recurrence interval
+type RecurrenceUnit byte
+
+// Recurrence units as written in rec: tags
+const (
+	RecurDays         RecurrenceUnit = 'd'
+	RecurBusinessDays RecurrenceUnit = 'b'
+	RecurWeeks        RecurrenceUnit = 'w'
+	RecurMonths       RecurrenceUnit = 'm'
+	RecurYears        RecurrenceUnit = 'y'
+)
+
+// Recurrence is the interval given by a rec: tag.
+// A strict recurrence ("rec:+1m") advances from the previous due date;
+// otherwise the next occurrence is counted from the completion date.
+type Recurrence struct {
+	Amount int
+	Unit   RecurrenceUnit
+	Strict bool
+}
+
+// ParseRecurrence parses the value of a rec: tag such as "1w", "+1m" or "3d"
+func ParseRecurrence(value string) (Recurrence, error) {
+	match := recurrenceRx.FindStringSubmatch(value)
+	if match == nil {
+		return Recurrence{}, fmt.Errorf("invalid recurrence %q", value)
+	}
+	amount, err := strconv.Atoi(match[2])
+	if err != nil || amount <= 0 {
+		return Recurrence{}, fmt.Errorf("invalid recurrence %q", value)
+	}
+	return Recurrence{
+		Amount: amount,
+		Unit:   RecurrenceUnit(match[3][0]),
+		Strict: match[1] == "+",
+	}, nil
+}
+
+// String returns the recurrence as written in a rec: tag
+func (r Recurrence) String() string {
+	prefix := ""
+	if r.Strict {
+		prefix = "+"
+	}
+	return fmt.Sprintf("%s%d%c", prefix, r.Amount, r.Unit)
+}
+
+// Advance returns the date one interval after from.
+// Months and years are clamped to the end of the month, so Jan 31 plus one
+// month is the last day of February rather than a day in March.
+func (r Recurrence) Advance(from time.Time) time.Time {
+	return shiftDate(from, r.Amount, r.Unit)
+}
+
+// shiftDate moves from by amount units; a negative amount moves it back
+func shiftDate(from time.Time, amount int, unit RecurrenceUnit) time.Time {
+	switch unit {
+	case RecurBusinessDays:
+		step := 1
+		if amount < 0 {
+			step, amount = -1, -amount
+		}
+		next := from
+		for added := 0; added < amount; {
+			next = next.AddDate(0, 0, step)
+			if next.Weekday() != time.Saturday && next.Weekday() != time.Sunday {
+				added++
+			}
+		}
+		return next
+	case RecurWeeks:
+		return from.AddDate(0, 0, 7*amount)
+	case RecurMonths:
+		return addMonths(from, amount)
+	case RecurYears:
+		return addMonths(from, 12*amount)
+	default:
+		return from.AddDate(0, 0, amount)
+	}
+}
+
+// addMonths adds months to t, clamping the day to the length of the resulting month
+func addMonths(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	firstOfMonth := time.Date(year, month+time.Month(months), 1, 0, 0, 0, 0, t.Location())
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(firstOfMonth.Year(), firstOfMonth.Month(), day,
+		t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+// Recurrence returns the interval given by the task's rec: tag.
+// ok is false if the task does not recur; err reports an invalid rec: value.
+func (t *Task) Recurrence() (recurrence Recurrence, ok bool, err error) {
+	value, ok := t.task.AdditionalTags[TaskFieldRecurrence]
+	if !ok {
+		return Recurrence{}, false, nil
+	}
+	recurrence, err = ParseRecurrence(value)
+	return recurrence, true, err
+}
+
+// NextOccurrence returns the task to create when this recurring task is
+// completed on completedAt, or nil if the task has no rec: tag.
+//
+// The due date advances by the interval from the completion date, or from
+// the previous due date for a strict recurrence. A t: date keeps its distance
+// to the due date. A task with neither date gets a due date one interval
+// after completion.
+func (t *Task) NextOccurrence(completedAt time.Time) (*todotxt.Task, error) {
+	recurrence, ok, err := t.Recurrence()
+	if !ok || err != nil {
+		return nil, err
+	}
+
+	completedOn := startOfDay(completedAt)
+	next := *t.task
+	next.ID = 0
+	next.Original = ""
+	next.Completed = false
+	next.CompletedDate = time.Time{}
+	next.Contexts = append([]string(nil), t.task.Contexts...)
+	next.Projects = append([]string(nil), t.task.Projects...)
+	next.AdditionalTags = make(map[string]string, len(t.task.AdditionalTags))
+	for key, value := range t.task.AdditionalTags {
+		next.AdditionalTags[key] = value
+	}
+	if t.task.HasCreatedDate() {
+		next.CreatedDate = completedOn
+	}
+
+	threshold, hasThreshold := t.GetThresholdDate()
+	switch {
+	case t.task.HasDueDate():
+		base := completedOn
+		if recurrence.Strict {
+			base = startOfDay(t.task.DueDate)
+		}
+		next.DueDate = recurrence.Advance(base)
+		if hasThreshold {
+			// Keep the threshold as many days before the due date as it was
+			days := daysBetween(threshold, t.task.DueDate)
+			next.AdditionalTags[TaskFieldThreshold] = next.DueDate.AddDate(0, 0, -days).Format(DateFormat)
+		}
+	case hasThreshold:
+		base := completedOn
+		if recurrence.Strict {
+			base = threshold
+		}
+		next.AdditionalTags[TaskFieldThreshold] = recurrence.Advance(base).Format(DateFormat)
+	default:
+		next.DueDate = recurrence.Advance(completedOn)
+	}
+	return &next, nil
+}
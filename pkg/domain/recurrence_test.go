@@ -0,0 +1,172 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	todotxt "github.com/1set/todotxt"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+}
+
+func TestParseRecurrence(t *testing.T) {
+	tests := []struct {
+		value     string
+		expected  Recurrence
+		expectErr bool
+	}{
+		{value: "1w", expected: Recurrence{Amount: 1, Unit: RecurWeeks}},
+		{value: "+1m", expected: Recurrence{Amount: 1, Unit: RecurMonths, Strict: true}},
+		{value: "3d", expected: Recurrence{Amount: 3, Unit: RecurDays}},
+		{value: "+2y", expected: Recurrence{Amount: 2, Unit: RecurYears, Strict: true}},
+		{value: "5b", expected: Recurrence{Amount: 5, Unit: RecurBusinessDays}},
+		{value: "0d", expectErr: true},
+		{value: "1x", expectErr: true},
+		{value: "w", expectErr: true},
+		{value: "-1d", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseRecurrence(tt.value)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("ParseRecurrence(%q) error = %v, expectErr %v", tt.value, err, tt.expectErr)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseRecurrence(%q) = %+v, expected %+v", tt.value, got, tt.expected)
+			}
+			if !tt.expectErr && got.String() != tt.value {
+				t.Errorf("String() = %q, expected %q", got.String(), tt.value)
+			}
+		})
+	}
+}
+
+func TestRecurrence_Advance(t *testing.T) {
+	tests := []struct {
+		name       string
+		recurrence string
+		from       time.Time
+		expected   time.Time
+	}{
+		{name: "days", recurrence: "3d", from: date(2025, 1, 30), expected: date(2025, 2, 2)},
+		{name: "weeks", recurrence: "1w", from: date(2025, 12, 29), expected: date(2026, 1, 5)},
+		{name: "month end clamps to February", recurrence: "1m", from: date(2025, 1, 31), expected: date(2025, 2, 28)},
+		{name: "month end clamps to leap day", recurrence: "1m", from: date(2024, 1, 31), expected: date(2024, 2, 29)},
+		{name: "31st clamps to 30-day month", recurrence: "1m", from: date(2025, 3, 31), expected: date(2025, 4, 30)},
+		{name: "months across year end", recurrence: "2m", from: date(2025, 12, 31), expected: date(2026, 2, 28)},
+		{name: "thirteen months", recurrence: "13m", from: date(2025, 1, 31), expected: date(2026, 2, 28)},
+		{name: "leap day plus a year", recurrence: "1y", from: date(2024, 2, 29), expected: date(2025, 2, 28)},
+		{name: "mid month is unchanged", recurrence: "1m", from: date(2025, 1, 15), expected: date(2025, 2, 15)},
+		{name: "business days skip the weekend", recurrence: "1b", from: date(2025, 1, 17), expected: date(2025, 1, 20)},
+		{name: "several business days", recurrence: "5b", from: date(2025, 1, 15), expected: date(2025, 1, 22)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recurrence, err := ParseRecurrence(tt.recurrence)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := recurrence.Advance(tt.from); !got.Equal(tt.expected) {
+				t.Errorf("Advance(%s) = %s, expected %s", tt.from.Format(DateFormat), got.Format(DateFormat), tt.expected.Format(DateFormat))
+			}
+		})
+	}
+}
+
+func TestTask_NextOccurrence(t *testing.T) {
+	completedAt := time.Date(2025, 2, 3, 18, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		name     string
+		task     string
+		expected string
+	}{
+		{
+			name:     "due date advances from the completion date",
+			task:     "Water plants due:2025-01-31 rec:1w",
+			expected: "Water plants rec:1w due:2025-02-10",
+		},
+		{
+			name:     "strict recurrence advances from the due date",
+			task:     "Pay rent due:2025-01-31 rec:+1m",
+			expected: "Pay rent rec:+1m due:2025-02-28",
+		},
+		{
+			name:     "strict recurrence skips forward by exactly one interval",
+			task:     "Report due:2024-12-31 rec:+2m",
+			expected: "Report rec:+2m due:2025-02-28",
+		},
+		{
+			name:     "threshold keeps its distance to the due date",
+			task:     "Renew passport due:2025-01-31 t:2025-01-24 rec:+1m",
+			expected: "Renew passport rec:+1m t:2025-02-21 due:2025-02-28",
+		},
+		{
+			name:     "threshold without due date advances from completion",
+			task:     "Review notes t:2025-01-01 rec:3d",
+			expected: "Review notes rec:3d t:2025-02-06",
+		},
+		{
+			name:     "strict threshold without due date advances from the threshold",
+			task:     "Review notes t:2025-01-31 rec:+1m",
+			expected: "Review notes rec:+1m t:2025-02-28",
+		},
+		{
+			name:     "task without dates gets a due date",
+			task:     "Clean desk rec:1m",
+			expected: "Clean desk rec:1m due:2025-03-03",
+		},
+		{
+			name:     "creation date is the completion date",
+			task:     "(A) 2025-01-01 Backup +home @pc due:2025-01-31 rec:+1m",
+			expected: "(A) 2025-02-03 Backup @pc +home rec:+1m due:2025-02-28",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todoTask, err := todotxt.ParseTask(tt.task)
+			if err != nil {
+				t.Fatal(err)
+			}
+			task, _ := NewTask(todoTask)
+			task.ToggleCompletion()
+
+			next, err := task.NextOccurrence(completedAt)
+			if err != nil {
+				t.Fatalf("NextOccurrence failed: %v", err)
+			}
+			if next == nil {
+				t.Fatal("NextOccurrence returned nil for a recurring task")
+			}
+			if next.String() != tt.expected {
+				t.Errorf("NextOccurrence() = %q, expected %q", next.String(), tt.expected)
+			}
+			if next.Completed || next.ID != 0 {
+				t.Errorf("next occurrence should be a new, incomplete task: %+v", next)
+			}
+			if !task.IsCompleted() {
+				t.Error("completed task was modified")
+			}
+		})
+	}
+}
+
+func TestTask_NextOccurrence_NotRecurring(t *testing.T) {
+	todoTask, _ := todotxt.ParseTask("Plain task due:2025-01-31")
+	task, _ := NewTask(todoTask)
+	next, err := task.NextOccurrence(time.Now())
+	if next != nil || err != nil {
+		t.Errorf("NextOccurrence() = %v, %v, expected nil for a task without rec:", next, err)
+	}
+
+	todoTask, _ = todotxt.ParseTask("Broken task rec:often")
+	task, _ = NewTask(todoTask)
+	if next, err := task.NextOccurrence(time.Now()); next != nil || err == nil {
+		t.Errorf("NextOccurrence() = %v, %v, expected an error for an invalid rec:", next, err)
+	}
+}
@@ -0,0 +1,205 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidSort is returned for a sort order that cannot be understood
+var ErrInvalidSort = errors.New("invalid sort order")
+
+// SortKey is a property tasks can be sorted by
+type SortKey string
+
+// Sort keys
+const (
+	SortPriority     SortKey = "priority"     // (A) first, tasks without a priority last
+	SortDue          SortKey = "due"          // Earliest due date first, tasks without one last
+	SortCreated      SortKey = "created"      // Oldest first, tasks without a creation date last
+	SortProject      SortKey = "project"      // By first project name, tasks without a project last
+	SortAlphabetical SortKey = "alphabetical" // By description, ignoring case
+	SortUrgency      SortKey = "urgency"      // Most urgent first
+)
+
+// 降順を表す接頭辞（例: -created）
+const sortDescendingPrefix = "-"
+
+// sortKeys lists the sort keys and the names they can be written as
+var sortKeys = map[string]SortKey{
+	"priority":     SortPriority,
+	"pri":          SortPriority,
+	"due":          SortDue,
+	"created":      SortCreated,
+	"project":      SortProject,
+	"alphabetical": SortAlphabetical,
+	"alpha":        SortAlphabetical,
+	"text":         SortAlphabetical,
+	"urgency":      SortUrgency,
+}
+
+// SortField is one key of a sort order
+type SortField struct {
+	Key        SortKey
+	Descending bool // Reverse the natural order of the key; tasks missing the value stay last
+}
+
+// SortOrder is a list of keys tasks are sorted by; later keys break ties of earlier ones.
+// An empty order keeps the order of the file.
+type SortOrder []SortField
+
+// ParseSortOrder parses a comma separated list of sort keys such as
+// "due,priority" or "-created". A "-" in front of a key reverses it.
+func ParseSortOrder(spec string) (SortOrder, error) {
+	var order SortOrder
+	for _, part := range strings.Split(spec, ",") {
+		name := strings.ToLower(strings.TrimSpace(part))
+		if name == "" {
+			continue
+		}
+		descending := strings.HasPrefix(name, sortDescendingPrefix)
+		key, ok := sortKeys[strings.TrimPrefix(name, sortDescendingPrefix)]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidSort, strings.TrimSpace(part))
+		}
+		order = append(order, SortField{Key: key, Descending: descending})
+	}
+	return order, nil
+}
+
+// String returns the order in the form ParseSortOrder accepts
+func (o SortOrder) String() string {
+	parts := make([]string, len(o))
+	for i, field := range o {
+		parts[i] = string(field.Key)
+		if field.Descending {
+			parts[i] = sortDescendingPrefix + parts[i]
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// UsesUrgency reports whether the order needs urgency scores
+func (o SortOrder) UsesUrgency() bool {
+	for _, field := range o {
+		if field.Key == SortUrgency {
+			return true
+		}
+	}
+	return false
+}
+
+// Sort sorts tasks by order. As with SortByCompletionStatus, open tasks come
+// before completed and deleted ones, and tasks that compare equal keep the
+// order of the file. urgency scores tasks for SortUrgency and may be nil if
+// the order does not use it.
+// Returns a new Tasks instance without modifying the original.
+func (t Tasks) Sort(order SortOrder, urgency func(*Task) float64) Tasks {
+	// 緊急度は比較のたびに計算せず、先にまとめて求めておく
+	var scores []float64
+	if urgency != nil && order.UsesUrgency() {
+		scores = make([]float64, len(t))
+		for i := range t {
+			scores[i] = urgency(&t[i])
+		}
+	}
+
+	indexes := make([]int, len(t))
+	for i := range indexes {
+		indexes[i] = i
+	}
+
+	sort.SliceStable(indexes, func(i, j int) bool {
+		a, b := indexes[i], indexes[j]
+		taskA, taskB := &t[a], &t[b]
+
+		// 削除されたタスクは完了タスクと同様に扱う
+		closedA := taskA.IsCompleted() || taskA.IsDeleted()
+		closedB := taskB.IsCompleted() || taskB.IsDeleted()
+		if closedA != closedB {
+			return !closedA
+		}
+
+		for _, field := range order {
+			var cmp int
+			if field.Key == SortUrgency {
+				if scores != nil {
+					cmp = compareUrgency(scores[a], scores[b])
+				}
+			} else {
+				cmp = compareTasks(field.Key, taskA, taskB)
+			}
+			if cmp == 0 {
+				continue
+			}
+			// 値のないタスクは降順でも最後に置く
+			if field.Descending && !missingSortValue(field.Key, taskA) && !missingSortValue(field.Key, taskB) {
+				cmp = -cmp
+			}
+			return cmp < 0
+		}
+		return false
+	})
+
+	sorted := make(Tasks, len(t))
+	for i, index := range indexes {
+		sorted[i] = t[index]
+	}
+	return sorted
+}
+
+// compareUrgency orders higher scores first
+func compareUrgency(a, b float64) int {
+	switch {
+	case a > b:
+		return -1
+	case a < b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compareTasks compares a and b by key in its natural order, putting tasks
+// that lack the value last. It returns a negative number if a comes first.
+func compareTasks(key SortKey, a, b *Task) int {
+	missingA, missingB := missingSortValue(key, a), missingSortValue(key, b)
+	switch {
+	case missingA && missingB:
+		return 0
+	case missingA:
+		return 1
+	case missingB:
+		return -1
+	}
+
+	switch key {
+	case SortPriority:
+		return strings.Compare(a.task.Priority, b.task.Priority)
+	case SortDue:
+		return a.task.DueDate.Compare(b.task.DueDate)
+	case SortCreated:
+		return a.task.CreatedDate.Compare(b.task.CreatedDate)
+	case SortProject:
+		return strings.Compare(strings.ToLower(a.task.Projects[0]), strings.ToLower(b.task.Projects[0]))
+	case SortAlphabetical:
+		return strings.Compare(strings.ToLower(a.task.Todo), strings.ToLower(b.task.Todo))
+	}
+	return 0
+}
+
+// missingSortValue reports whether task has no value to sort by key
+func missingSortValue(key SortKey, task *Task) bool {
+	switch key {
+	case SortPriority:
+		return !task.task.HasPriority()
+	case SortDue:
+		return !task.task.HasDueDate()
+	case SortCreated:
+		return !task.task.HasCreatedDate()
+	case SortProject:
+		return len(task.task.Projects) == 0
+	}
+	return false
+}
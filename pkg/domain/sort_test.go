@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	todotxt "github.com/1set/todotxt"
+)
+
+// sortTestTasks is the task list the sort tests are run against, in file order
+var sortTestTasks = []string{
+	"2025-01-10 Write report +work due:2025-01-20",
+	"(B) 2025-01-03 buy milk @store",
+	"x 2025-01-14 (A) Send invoice +work due:2025-01-13",
+	"(A) 2025-01-05 Call plumber +home due:2025-01-25",
+	"Answer email +Admin due:2025-01-20",
+	"(C) Review budget +work",
+}
+
+func newSortTestTasks(t *testing.T) Tasks {
+	t.Helper()
+	var tasks Tasks
+	for _, line := range sortTestTasks {
+		todoTask, err := todotxt.ParseTask(line)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", line, err)
+		}
+		task, _ := NewTask(todoTask)
+		tasks = append(tasks, *task)
+	}
+	return tasks
+}
+
+func TestTasks_Sort(t *testing.T) {
+	tasks := newSortTestTasks(t)
+
+	tests := []struct {
+		order    string
+		expected string
+	}{
+		// 完了したタスクは常に最後、同じ値のタスクはファイルの順序を保つ
+		{order: "", expected: "Write report,buy milk,Call plumber,Answer email,Review budget,Send invoice"},
+		{order: "priority", expected: "Call plumber,buy milk,Review budget,Write report,Answer email,Send invoice"},
+		{order: "-priority", expected: "Review budget,buy milk,Call plumber,Write report,Answer email,Send invoice"},
+		{order: "due", expected: "Write report,Answer email,Call plumber,buy milk,Review budget,Send invoice"},
+		{order: "due,priority", expected: "Write report,Answer email,Call plumber,buy milk,Review budget,Send invoice"},
+		{order: "due,-alpha", expected: "Write report,Answer email,Call plumber,Review budget,buy milk,Send invoice"},
+		{order: "due,alpha", expected: "Answer email,Write report,Call plumber,buy milk,Review budget,Send invoice"},
+		{order: "created", expected: "buy milk,Call plumber,Write report,Answer email,Review budget,Send invoice"},
+		{order: "-created", expected: "Write report,Call plumber,buy milk,Answer email,Review budget,Send invoice"},
+		{order: "project", expected: "Answer email,Call plumber,Write report,Review budget,buy milk,Send invoice"},
+		{order: "project,priority", expected: "Answer email,Call plumber,Review budget,Write report,buy milk,Send invoice"},
+		{order: "alphabetical", expected: "Answer email,buy milk,Call plumber,Review budget,Write report,Send invoice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.order, func(t *testing.T) {
+			order, err := ParseSortOrder(tt.order)
+			if err != nil {
+				t.Fatalf("ParseSortOrder(%q) failed: %v", tt.order, err)
+			}
+			var todos []string
+			for _, task := range tasks.Sort(order, nil).ToTaskList() {
+				todos = append(todos, task.Todo)
+			}
+			if got := strings.Join(todos, ","); got != tt.expected {
+				t.Errorf("Sort(%q) = %s\nexpected  %s", tt.order, got, tt.expected)
+			}
+		})
+	}
+
+	// 元の並びは変更されない
+	if tasks[0].ToTodoTxtTask().Todo != "Write report" {
+		t.Error("Sort should not modify the original tasks")
+	}
+}
+
+func TestTasks_SortByUrgency(t *testing.T) {
+	tasks := newSortTestTasks(t)
+	now := time.Date(2025, 1, 15, 9, 0, 0, 0, time.Local)
+	coefficients := DefaultUrgencyCoefficients()
+	calendar := NewCalendar(time.Sunday)
+	urgency := func(task *Task) float64 {
+		return task.Urgency(coefficients, calendar, now)
+	}
+
+	order, _ := ParseSortOrder("urgency")
+	var todos []string
+	for _, task := range tasks.Sort(order, urgency).ToTaskList() {
+		todos = append(todos, task.Todo)
+	}
+	expected := "Call plumber,Write report,Answer email,buy milk,Review budget,Send invoice"
+	if got := strings.Join(todos, ","); got != expected {
+		t.Errorf("Sort(urgency) = %s\nexpected        %s", got, expected)
+	}
+}
+
+func TestParseSortOrder(t *testing.T) {
+	order, err := ParseSortOrder(" Due , -PRI,alpha ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := order.String(); got != "due,-priority,alphabetical" {
+		t.Errorf("String() = %q", got)
+	}
+	if order.UsesUrgency() {
+		t.Error("order should not use urgency")
+	}
+
+	for _, spec := range []string{"size", "due,,bogus", "--due"} {
+		if _, parseErr := ParseSortOrder(spec); !errors.Is(parseErr, ErrInvalidSort) {
+			t.Errorf("ParseSortOrder(%q) error = %v, expected ErrInvalidSort", spec, parseErr)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package domain
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -15,14 +18,17 @@ import (
 
 // Task field names
 const (
-	TaskFieldDeleted = "deleted_at"
-	TaskFieldDue     = "due"
+	TaskFieldDeleted    = "deleted_at"
+	TaskFieldDue        = "due"
+	TaskFieldThreshold  = "t"
+	TaskFieldRecurrence = "rec"
 )
 
 // Task field prefixes (with colon)
 const (
-	TaskFieldDeletedPrefix = TaskFieldDeleted + ":"
-	TaskFieldDuePrefix     = TaskFieldDue + ":"
+	TaskFieldDeletedPrefix   = TaskFieldDeleted + ":"
+	TaskFieldDuePrefix       = TaskFieldDue + ":"
+	TaskFieldThresholdPrefix = TaskFieldThreshold + ":"
 )
 
 // ===============================
@@ -34,11 +40,26 @@ const (
 	DateFormat = "2006-01-02"
 )
 
+// ErrInvalidPriority is returned for a priority that is not a letter from A to Z
+var ErrInvalidPriority = errors.New("invalid priority")
+
 // Task represents the domain logic for a task
 type Task struct {
 	task *todotxt.Task
 }
 
+// TaskID identifies a task independently of its current content.
+// Line is the line the task was read from (0 for tasks not saved yet) and
+// Fingerprint is a hash of that line as read, so identical lines are told
+// apart by position and a task is still found after lines above it moved.
+type TaskID struct {
+	Line        int
+	Fingerprint string
+}
+
+// フィンガープリントに使うハッシュの桁数（16進）
+const fingerprintLength = 16
+
 // NewTask creates a new Task domain instance
 func NewTask(task *todotxt.Task) (*Task, error) {
 	if task == nil {
@@ -49,6 +70,37 @@ func NewTask(task *todotxt.Task) (*Task, error) {
 	}, nil
 }
 
+// ID returns the identity of the task.
+// The identity is kept when the task is modified through Task methods.
+func (t *Task) ID() TaskID {
+	original := t.task.Original
+	if original == "" {
+		original = t.task.String()
+	}
+	sum := sha256.Sum256([]byte(strings.TrimSpace(original)))
+	return TaskID{
+		Line:        t.task.ID,
+		Fingerprint: hex.EncodeToString(sum[:])[:fingerprintLength],
+	}
+}
+
+// replace overwrites the task with newTask while keeping its identity
+func (t *Task) replace(newTask *todotxt.Task) {
+	line, original := t.task.ID, t.task.Original
+	*t.task = *newTask
+	t.task.ID, t.task.Original = line, original
+}
+
+// SetText replaces the task with the task parsed from text, keeping its identity
+func (t *Task) SetText(text string) error {
+	newTask, err := todotxt.ParseTask(text)
+	if err != nil {
+		return err
+	}
+	t.replace(newTask)
+	return nil
+}
+
 // ToggleCompletion toggles the completion status of the task
 // Returns true if the task is now completed, false if it's now incomplete
 func (t *Task) ToggleCompletion() bool {
@@ -87,9 +139,8 @@ func (t *Task) IsOverdue(now time.Time) bool {
 		return false
 	}
 
-	today := now.Format("2006-01-02")
-	taskDateStr := t.task.DueDate.Format("2006-01-02")
-	return taskDateStr < today
+	calendar := DefaultCalendar()
+	return calendar.StartOfDay(t.task.DueDate).Before(calendar.StartOfDay(now))
 }
 
 // IsDueToday checks if a task is due today
@@ -98,29 +149,63 @@ func (t *Task) IsDueToday(now time.Time) bool {
 		return false
 	}
 
-	today := now.Format("2006-01-02")
-	taskDateStr := t.task.DueDate.Format("2006-01-02")
-	return taskDateStr == today
+	return DefaultCalendar().IsSameDay(t.task.DueDate, now)
 }
 
-// IsThisWeek checks if a task is due this week
+// IsThisWeek checks if a task is due this week, with weeks starting on DefaultWeekStart
 func (t *Task) IsThisWeek(now time.Time) bool {
-	if !t.task.HasDueDate() || t.IsDeleted() || t.task.Completed {
+	return t.IsDueThisWeek(DefaultCalendar(), now)
+}
+
+// GetThresholdDate returns the date given by the task's t: tag.
+// The second result is false if the task has no valid threshold date.
+func (t *Task) GetThresholdDate() (time.Time, bool) {
+	value, ok := t.task.AdditionalTags[TaskFieldThreshold]
+	if !ok {
+		return time.Time{}, false
+	}
+	threshold, err := time.ParseInLocation(DateFormat, value, DefaultCalendar().location())
+	if err != nil {
+		return time.Time{}, false
+	}
+	return threshold, true
+}
+
+// IsUpcoming checks if the task's threshold date has not arrived yet.
+// Such tasks are not actionable and are hidden from the regular lists.
+func (t *Task) IsUpcoming(now time.Time) bool {
+	if t.IsDeleted() || t.task.Completed {
+		return false
+	}
+	threshold, ok := t.GetThresholdDate()
+	if !ok {
 		return false
 	}
+	return threshold.After(startOfDay(now))
+}
 
-	// 週の開始を日曜日として計算（Goでは日曜日が0）
-	weekStart := now.AddDate(0, 0, -int(now.Weekday()))
-	// 週の終了を土曜日の翌日（日曜日）として計算
-	weekEnd := weekStart.AddDate(0, 0, 7)
+// Defer moves the task's threshold date days into the future, counted from
+// its current threshold date if that is still ahead, otherwise from today
+func (t *Task) Defer(days int, now time.Time) error {
+	base := startOfDay(now)
+	if threshold, ok := t.GetThresholdDate(); ok && threshold.After(base) {
+		base = threshold
+	}
+	threshold := base.AddDate(0, 0, days).Format(DateFormat)
 
-	// 日付レベルでの比較
-	taskDate := t.task.DueDate.Format("2006-01-02")
-	weekStartStr := weekStart.Format("2006-01-02")
-	weekEndStr := weekEnd.Format("2006-01-02")
+	// Replace any existing t: field with the new date
+	parts := strings.Fields(t.task.String())
+	newParts := lo.Filter(parts, func(part string, _ int) bool {
+		return !strings.HasPrefix(part, TaskFieldThresholdPrefix)
+	})
+	newTaskString := strings.Join(newParts, " ") + " " + TaskFieldThresholdPrefix + threshold
 
-	// タスクの期限日が週の範囲内かチェック（開始日含む、終了日除く）
-	return taskDate >= weekStartStr && taskDate < weekEndStr
+	newTask, err := todotxt.ParseTask(newTaskString)
+	if err != nil {
+		return err
+	}
+	t.replace(newTask)
+	return nil
 }
 
 // containsDeletedPrefix checks if a task string contains the deleted_at field
@@ -139,27 +224,34 @@ func (t *Task) Contexts() []string {
 	return t.task.Contexts
 }
 
+// SearchText returns the text searched by the fuzzy search: the description
+// followed by the projects and contexts, in the order the task list shows them
+func (t *Task) SearchText() string {
+	text := t.task.Todo
+	var tags []string
+	for _, project := range t.task.Projects {
+		tags = append(tags, "+"+project)
+	}
+	for _, context := range t.task.Contexts {
+		tags = append(tags, "@"+context)
+	}
+	if len(tags) > 0 {
+		text += " " + strings.Join(tags, " ")
+	}
+	return text
+}
+
 // HasDueDate returns true if the task has a due date
 func (t *Task) HasDueDate() bool {
 	return t.task.HasDueDate()
 }
 
-// IsDueThisWeek returns true if the task is due this week
-func (t *Task) IsDueThisWeek(now time.Time) bool {
-	if !t.HasDueDate() {
+// IsDueThisWeek checks if an open task is due in the week of now as given by calendar
+func (t *Task) IsDueThisWeek(calendar Calendar, now time.Time) bool {
+	if !t.task.HasDueDate() || t.IsDeleted() || t.task.Completed {
 		return false
 	}
-
-	// Get the start of this week (Monday)
-	weekday := int(now.Weekday())
-	if weekday == 0 { // Sunday
-		weekday = 7
-	}
-	startOfWeek := now.AddDate(0, 0, -(weekday - 1)).Truncate(24 * time.Hour)
-	endOfWeek := startOfWeek.AddDate(0, 0, 7)
-
-	dueDate := t.task.DueDate.Truncate(24 * time.Hour)
-	return !dueDate.Before(startOfWeek) && dueDate.Before(endOfWeek)
+	return calendar.IsSameWeek(t.task.DueDate, now)
 }
 
 // String returns the string representation of the task
@@ -248,10 +340,70 @@ func (t *Task) ToggleDueToday(now time.Time) error {
 	if err != nil {
 		return err
 	}
-	*t.task = *newTask
+	t.replace(newTask)
+	return nil
+}
+
+// SetDueDate sets the due date of the task, or removes it if due is the zero time
+func (t *Task) SetDueDate(due time.Time) error {
+	parts := lo.Filter(strings.Fields(t.task.String()), func(part string, _ int) bool {
+		return !strings.HasPrefix(part, TaskFieldDuePrefix)
+	})
+	if !due.IsZero() {
+		parts = append(parts, TaskFieldDuePrefix+due.Format(DateFormat))
+	}
+
+	newTask, err := todotxt.ParseTask(strings.Join(parts, " "))
+	if err != nil {
+		return err
+	}
+	t.replace(newTask)
+	return nil
+}
+
+// AddTag adds a project ("+name") or context ("@name") to the task.
+// Nothing changes if the task already has it.
+func (t *Task) AddTag(tag string) error {
+	if !isProjectOrContext(tag) {
+		return fmt.Errorf("not a project or context: %q", tag)
+	}
+	if t.hasTag(tag) {
+		return nil
+	}
+
+	newTask, err := todotxt.ParseTask(t.task.String() + " " + tag)
+	if err != nil {
+		return err
+	}
+	t.replace(newTask)
+	return nil
+}
+
+// RemoveTag removes a project ("+name") or context ("@name") from the task, ignoring case
+func (t *Task) RemoveTag(tag string) error {
+	if !isProjectOrContext(tag) {
+		return fmt.Errorf("not a project or context: %q", tag)
+	}
+	if !t.hasTag(tag) {
+		return nil
+	}
+
+	parts := lo.Filter(strings.Fields(t.task.String()), func(part string, _ int) bool {
+		return !strings.EqualFold(part, tag)
+	})
+	newTask, err := todotxt.ParseTask(strings.Join(parts, " "))
+	if err != nil {
+		return err
+	}
+	t.replace(newTask)
 	return nil
 }
 
+// isProjectOrContext reports whether tag is a project or context such as "+work" or "@home"
+func isProjectOrContext(tag string) bool {
+	return len(tag) > 1 && (tag[0] == '+' || tag[0] == '@') && !strings.ContainsAny(tag, " \t")
+}
+
 // SoftDelete marks the task as deleted by adding a deleted_at field
 func (t *Task) SoftDelete(now time.Time) error {
 	taskString := t.task.String()
@@ -270,7 +422,7 @@ func (t *Task) SoftDelete(now time.Time) error {
 	if err != nil {
 		return err
 	}
-	*t.task = *newTask
+	t.replace(newTask)
 	return nil
 }
 
@@ -295,7 +447,18 @@ func (t *Task) RestoreFromDeleted() error {
 	if err != nil {
 		return err
 	}
-	*t.task = *newTask
+	t.replace(newTask)
+	return nil
+}
+
+// SetPriority sets the priority of the task to a letter from A to Z,
+// or removes it if priority is empty
+func (t *Task) SetPriority(priority string) error {
+	priority = strings.ToUpper(priority)
+	if priority != "" && !isPriorityLetter(priority) {
+		return fmt.Errorf("%w: %q", ErrInvalidPriority, priority)
+	}
+	t.task.Priority = priority
 	return nil
 }
 
@@ -312,6 +475,39 @@ func (t *Task) HasPriority() bool {
 	return t.task.HasPriority()
 }
 
+// GetDeletedDate returns the date the task was soft deleted.
+// The second result is false if the task is not deleted or the date is invalid.
+func (t *Task) GetDeletedDate() (time.Time, bool) {
+	value, ok := t.task.AdditionalTags[TaskFieldDeleted]
+	if !ok {
+		return time.Time{}, false
+	}
+	deletedAt, err := time.ParseInLocation(DateFormat, value, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return deletedAt, true
+}
+
+// IsArchivable reports whether the task should be moved to done.txt.
+// Completed tasks are always archivable. Deleted tasks are archivable once
+// they were deleted more than purgeDeletedAfterDays days ago; 0 keeps them.
+func (t *Task) IsArchivable(now time.Time, purgeDeletedAfterDays int) bool {
+	if t.task.Completed {
+		return true
+	}
+	if purgeDeletedAfterDays <= 0 {
+		return false
+	}
+
+	deletedAt, ok := t.GetDeletedDate()
+	if !ok {
+		return false
+	}
+	cutoff := now.AddDate(0, 0, -purgeDeletedAfterDays).Format(DateFormat)
+	return deletedAt.Format(DateFormat) < cutoff
+}
+
 // GetDueDate returns the due date of the task
 func (t *Task) GetDueDate() time.Time {
 	return t.task.DueDate
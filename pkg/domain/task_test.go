@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -416,6 +417,40 @@ func TestTask_CyclePriority(t *testing.T) {
 	}
 }
 
+func TestTask_SetPriority(t *testing.T) {
+	tests := []struct {
+		task     string
+		priority string
+		expected string
+	}{
+		{task: "Test task", priority: "A", expected: "(A) Test task"},
+		{task: "(A) Test task", priority: "c", expected: "(C) Test task"},
+		{task: "(B) Test task", priority: "", expected: "Test task"},
+	}
+
+	for _, tt := range tests {
+		todoTask, _ := todotxt.ParseTask(tt.task)
+		task, _ := NewTask(todoTask)
+		if err := task.SetPriority(tt.priority); err != nil {
+			t.Fatalf("SetPriority(%q) failed: %v", tt.priority, err)
+		}
+		if task.String() != tt.expected {
+			t.Errorf("SetPriority(%q) on %q = %q, expected %q", tt.priority, tt.task, task.String(), tt.expected)
+		}
+	}
+
+	for _, priority := range []string{"AB", "1", "-"} {
+		todoTask, _ := todotxt.ParseTask("(A) Test task")
+		task, _ := NewTask(todoTask)
+		if err := task.SetPriority(priority); !errors.Is(err, ErrInvalidPriority) {
+			t.Errorf("SetPriority(%q) error = %v, expected ErrInvalidPriority", priority, err)
+		}
+		if task.GetPriority() != "A" {
+			t.Errorf("an invalid priority should leave the task unchanged")
+		}
+	}
+}
+
 func TestTask_ToggleDueToday(t *testing.T) {
 	testTime := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
 
@@ -770,6 +805,114 @@ func TestTask_GetDueDate(t *testing.T) {
 }
 
 // Integration test for soft delete and restore workflow
+func TestTask_IsArchivable(t *testing.T) {
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name      string
+		taskText  string
+		purgeDays int
+		expected  bool
+	}{
+		{
+			name:      "completed task",
+			taskText:  "x 2025-01-14 Done task",
+			purgeDays: 0,
+			expected:  true,
+		},
+		{
+			name:      "active task",
+			taskText:  "Active task",
+			purgeDays: 7,
+			expected:  false,
+		},
+		{
+			name:      "deleted task kept when purge is disabled",
+			taskText:  "Old task deleted_at:2024-01-01",
+			purgeDays: 0,
+			expected:  false,
+		},
+		{
+			name:      "deleted task older than purge days",
+			taskText:  "Old task deleted_at:2025-01-07",
+			purgeDays: 7,
+			expected:  true,
+		},
+		{
+			name:      "deleted task exactly purge days ago",
+			taskText:  "Recent task deleted_at:2025-01-08",
+			purgeDays: 7,
+			expected:  false,
+		},
+		{
+			name:      "deleted task with invalid date",
+			taskText:  "Broken task deleted_at:yesterday",
+			purgeDays: 7,
+			expected:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todoTask, err := todotxt.ParseTask(tt.taskText)
+			if err != nil {
+				t.Fatalf("Failed to parse task: %v", err)
+			}
+
+			task, err := NewTask(todoTask)
+			if err != nil {
+				t.Fatalf("Failed to create domain task: %v", err)
+			}
+
+			if actual := task.IsArchivable(now, tt.purgeDays); actual != tt.expected {
+				t.Errorf("IsArchivable() = %v, expected %v", actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTask_IDSurvivesMutation(t *testing.T) {
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		mutate func(*Task) error
+	}{
+		{name: "soft delete", mutate: func(task *Task) error { return task.SoftDelete(now) }},
+		{name: "toggle due today", mutate: func(task *Task) error { return task.ToggleDueToday(now) }},
+		{name: "cycle priority", mutate: func(task *Task) error { return task.CyclePriority([]string{"", "A"}) }},
+		{name: "set text", mutate: func(task *Task) error { return task.SetText("Completely different +task") }},
+		{name: "toggle completion", mutate: func(task *Task) error {
+			task.ToggleCompletion()
+			return nil
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todoTask, err := todotxt.ParseTask("Test task +project")
+			if err != nil {
+				t.Fatalf("Failed to parse task: %v", err)
+			}
+			todoTask.ID = 3
+
+			task, err := NewTask(todoTask)
+			if err != nil {
+				t.Fatalf("Failed to create domain task: %v", err)
+			}
+			before := task.ID()
+
+			if err := tt.mutate(task); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if after := task.ID(); after != before {
+				t.Errorf("ID changed from %v to %v", before, after)
+			}
+		})
+	}
+}
+
 func TestTask_SoftDeleteAndRestore_Integration(t *testing.T) {
 	testTime := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
 	original := "(A) Important task +project @context due:2025-01-20"
@@ -821,3 +964,65 @@ func TestTask_SoftDeleteAndRestore_Integration(t *testing.T) {
 		t.Errorf("Expected %q, got %q", expectedRestored, task.String())
 	}
 }
+
+func TestTask_IsUpcoming(t *testing.T) {
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name     string
+		task     string
+		expected bool
+	}{
+		{name: "threshold in the future", task: "Plan trip t:2025-01-16", expected: true},
+		{name: "threshold today", task: "Plan trip t:2025-01-15", expected: false},
+		{name: "threshold in the past", task: "Plan trip t:2025-01-01", expected: false},
+		{name: "no threshold", task: "Plan trip", expected: false},
+		{name: "invalid threshold", task: "Plan trip t:someday", expected: false},
+		{name: "completed task", task: "x 2025-01-14 Plan trip t:2025-02-01", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todoTask, err := todotxt.ParseTask(tt.task)
+			if err != nil {
+				t.Fatal(err)
+			}
+			task, _ := NewTask(todoTask)
+			if got := task.IsUpcoming(now); got != tt.expected {
+				t.Errorf("IsUpcoming() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTask_Defer(t *testing.T) {
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name     string
+		task     string
+		days     int
+		expected string
+	}{
+		{name: "without threshold defers from today", task: "Plan trip +travel", days: 3, expected: "Plan trip +travel t:2025-01-18"},
+		{name: "future threshold is pushed further", task: "Plan trip t:2025-01-20", days: 7, expected: "Plan trip t:2025-01-27"},
+		{name: "past threshold defers from today", task: "Plan trip t:2025-01-01", days: 1, expected: "Plan trip t:2025-01-16"},
+		{name: "due date is kept", task: "Plan trip due:2025-02-01", days: 1, expected: "Plan trip t:2025-01-16 due:2025-02-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todoTask, err := todotxt.ParseTask(tt.task)
+			if err != nil {
+				t.Fatal(err)
+			}
+			task, _ := NewTask(todoTask)
+			if err := task.Defer(tt.days, now); err != nil {
+				t.Fatalf("Defer failed: %v", err)
+			}
+			if task.String() != tt.expected {
+				t.Errorf("Defer() = %q, expected %q", task.String(), tt.expected)
+			}
+		})
+	}
+}
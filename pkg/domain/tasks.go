@@ -58,6 +58,64 @@ func (t Tasks) SafeGet(index int) (Task, bool) {
 	return t[index], true
 }
 
+// IndexOf returns the index of the task with the given identity.
+// A task on the same line with the same fingerprint is preferred; otherwise
+// the task with the same fingerprint closest to the original line is used,
+// which finds a task whose line moved because lines above it changed.
+func (t Tasks) IndexOf(id TaskID) (int, bool) {
+	best := -1
+	bestDistance := 0
+	for i := range t {
+		candidate := t[i].ID()
+		if candidate.Fingerprint != id.Fingerprint {
+			continue
+		}
+		if candidate.Line == id.Line {
+			return i, true
+		}
+		distance := candidate.Line - id.Line
+		if distance < 0 {
+			distance = -distance
+		}
+		if best < 0 || distance < bestDistance {
+			best, bestDistance = i, distance
+		}
+	}
+	return best, best >= 0
+}
+
+// IndexOfText returns the index of the task whose text is text, preferring
+// the one closest to line. Line 0 stands for a task that was not saved yet;
+// such tasks are appended, so the last match is used.
+func (t Tasks) IndexOfText(text string, line int) (int, bool) {
+	best := -1
+	bestDistance := 0
+	for i := range t {
+		if t[i].String() != text {
+			continue
+		}
+		if line <= 0 {
+			best = i
+			continue
+		}
+		distance := t[i].task.ID - line
+		if distance < 0 {
+			distance = -distance
+		}
+		if best < 0 || distance < bestDistance {
+			best, bestDistance = i, distance
+		}
+	}
+	return best, best >= 0
+}
+
+// Remove returns a new Tasks instance without the task at index
+func (t Tasks) Remove(index int) Tasks {
+	removed := make(Tasks, 0, len(t))
+	removed = append(removed, t[:index]...)
+	return append(removed, t[index+1:]...)
+}
+
 // Filter applies a filter function and returns a new Tasks instance
 func (t Tasks) Filter(filterFn func(Task, int) bool) Tasks {
 	var filtered Tasks
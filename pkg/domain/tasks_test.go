@@ -518,3 +518,107 @@ func TestSortTasksByCompletionStatus_StableSort(t *testing.T) {
 		}
 	}
 }
+
+// TestTasks_IndexOf tests finding tasks by identity
+func TestTasks_IndexOf(t *testing.T) {
+	newLoadedTask := func(text string, line int) todotxt.Task {
+		task := createTestTask(text, false)
+		task.ID = line
+		return task
+	}
+
+	tasks := NewTasks(todotxt.TaskList{
+		newLoadedTask("Task A", 1),
+		newLoadedTask("Task B", 2),
+		newLoadedTask("Task A", 4),
+	})
+	secondA := tasks.Get(2)
+
+	tests := []struct {
+		name          string
+		id            TaskID
+		expectedIndex int
+		expectedFound bool
+	}{
+		{
+			name:          "duplicate line is matched by line number",
+			id:            secondA.ID(),
+			expectedIndex: 2,
+			expectedFound: true,
+		},
+		{
+			name:          "moved line is matched by nearest fingerprint",
+			id:            TaskID{Line: 5, Fingerprint: secondA.ID().Fingerprint},
+			expectedIndex: 2,
+			expectedFound: true,
+		},
+		{
+			name:          "unknown fingerprint is not found",
+			id:            TaskID{Line: 1, Fingerprint: "0000000000000000"},
+			expectedIndex: -1,
+			expectedFound: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index, found := tasks.IndexOf(tt.id)
+			if index != tt.expectedIndex || found != tt.expectedFound {
+				t.Errorf("IndexOf(%v) = (%d, %v), expected (%d, %v)", tt.id, index, found, tt.expectedIndex, tt.expectedFound)
+			}
+		})
+	}
+}
+
+func TestTasks_IndexOfText(t *testing.T) {
+	newTaskOnLine := func(text string, line int) todotxt.Task {
+		task := createTestTask(text, false)
+		task.ID = line
+		return task
+	}
+
+	tasks := NewTasks(todotxt.TaskList{
+		newTaskOnLine("Task A", 1),
+		newTaskOnLine("Task B", 2),
+		newTaskOnLine("Task A", 4),
+		newTaskOnLine("Task A", 0), // 未保存のタスク
+	})
+
+	tests := []struct {
+		name          string
+		text          string
+		line          int
+		expectedIndex int
+		expectedFound bool
+	}{
+		{name: "closest to the first line", text: "Task A", line: 1, expectedIndex: 0, expectedFound: true},
+		{name: "closest to a later line", text: "Task A", line: 3, expectedIndex: 2, expectedFound: true},
+		{name: "unsaved task prefers the last match", text: "Task A", line: 0, expectedIndex: 3, expectedFound: true},
+		{name: "unknown text is not found", text: "Task C", line: 2, expectedIndex: -1, expectedFound: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			index, found := tasks.IndexOfText(tt.text, tt.line)
+			if index != tt.expectedIndex || found != tt.expectedFound {
+				t.Errorf("IndexOfText(%q, %d) = (%d, %v), expected (%d, %v)", tt.text, tt.line, index, found, tt.expectedIndex, tt.expectedFound)
+			}
+		})
+	}
+}
+
+func TestTasks_Remove(t *testing.T) {
+	tasks := NewTasks(todotxt.TaskList{
+		createTestTask("Task A", false),
+		createTestTask("Task B", false),
+		createTestTask("Task C", false),
+	})
+
+	removed := tasks.Remove(1)
+	if removed.Len() != 2 || removed[0].String() != "Task A" || removed[1].String() != "Task C" {
+		t.Errorf("Remove(1) = %v", removed.ToTaskList())
+	}
+	if tasks.Len() != 3 || tasks[1].String() != "Task B" {
+		t.Errorf("Remove modified the original tasks: %v", tasks.ToTaskList())
+	}
+}
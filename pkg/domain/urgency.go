@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"time"
+)
+
+// UrgencyCoefficients weigh the parts of a task's urgency score.
+// The defaults follow Taskwarrior's, so scores feel familiar to its users.
+type UrgencyCoefficients struct {
+	PriorityA float64 // Weight of priority (A)
+	PriorityB float64 // Weight of priority (B)
+	PriorityC float64 // Weight of priority (C)
+	Due       float64 // Weight of a task a week or more overdue; later due dates count for less
+	Age       float64 // Weight of a task created a year or more ago
+	Project   float64 // Weight of having a project
+	Upcoming  float64 // Weight of a task hidden until its t: date, usually negative
+
+	// Weights of tags, matched ignoring case: "+project" and "@context" for a
+	// project or context, and a bare key such as "waiting" for a key:value tag
+	Tags map[string]float64
+}
+
+// Taskwarrior に合わせた期限の減衰範囲（日数）
+const (
+	urgencyOverdueDays  = 7  // これ以上期限を過ぎると最大
+	urgencyDueRangeDays = 14 // これより先の期限は最小
+	urgencyMinDueFactor = 0.2
+	urgencyAgeDays      = 365 // これ以上古いと最大
+)
+
+// DefaultUrgencyCoefficients returns the coefficients used unless configured otherwise
+func DefaultUrgencyCoefficients() UrgencyCoefficients {
+	return UrgencyCoefficients{
+		PriorityA: 6.0,
+		PriorityB: 3.9,
+		PriorityC: 1.8,
+		Due:       12.0,
+		Age:       2.0,
+		Project:   1.0,
+		Upcoming:  -3.0,
+	}
+}
+
+// Urgency returns how urgent the task is: the higher, the sooner it should be done.
+// The score adds up the weighted priority, how close the due date is, how old
+// the task is, whether it has a project and the weights of its tags, and is
+// lowered while the task is upcoming.
+func (t *Task) Urgency(coefficients UrgencyCoefficients, calendar Calendar, now time.Time) float64 {
+	var score float64
+
+	switch t.task.Priority {
+	case "A":
+		score += coefficients.PriorityA
+	case "B":
+		score += coefficients.PriorityB
+	case "C":
+		score += coefficients.PriorityC
+	}
+
+	if t.task.HasDueDate() {
+		score += coefficients.Due * dueFactor(calendar.DaysBetween(t.task.DueDate, now))
+	}
+
+	if t.task.HasCreatedDate() {
+		age := float64(calendar.DaysBetween(t.task.CreatedDate, now))
+		score += coefficients.Age * math.Max(0, math.Min(age/urgencyAgeDays, 1))
+	}
+
+	if len(t.task.Projects) > 0 {
+		score += coefficients.Project
+	}
+
+	if t.IsUpcoming(now) {
+		score += coefficients.Upcoming
+	}
+
+	for tag, weight := range coefficients.Tags {
+		if t.hasTag(tag) {
+			score += weight
+		}
+	}
+
+	return score
+}
+
+// hasTag reports whether the task has the project ("+name"), the context
+// ("@name") or the key:value tag ("key") given by tag, ignoring case
+func (t *Task) hasTag(tag string) bool {
+	if name, ok := strings.CutPrefix(tag, "+"); ok {
+		return slices.ContainsFunc(t.task.Projects, func(project string) bool {
+			return strings.EqualFold(project, name)
+		})
+	}
+	if name, ok := strings.CutPrefix(tag, "@"); ok {
+		return slices.ContainsFunc(t.task.Contexts, func(context string) bool {
+			return strings.EqualFold(context, name)
+		})
+	}
+	for key := range t.task.AdditionalTags {
+		if strings.EqualFold(key, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// dueFactor scales the due coefficient by the number of days past due (negative
+// before the due date): 1.0 a week or more overdue, down to 0.2 two weeks or more ahead
+func dueFactor(daysOverdue int) float64 {
+	switch {
+	case daysOverdue >= urgencyOverdueDays:
+		return 1.0
+	case daysOverdue >= -urgencyDueRangeDays:
+		span := float64(urgencyOverdueDays + urgencyDueRangeDays)
+		return float64(daysOverdue+urgencyDueRangeDays)*(1.0-urgencyMinDueFactor)/span + urgencyMinDueFactor
+	default:
+		return urgencyMinDueFactor
+	}
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	todotxt "github.com/1set/todotxt"
+)
+
+func TestTask_Urgency(t *testing.T) {
+	// 基準日時: 2025年1月15日
+	now := time.Date(2025, 1, 15, 9, 0, 0, 0, time.Local)
+	calendar := NewCalendar(time.Sunday)
+	coefficients := DefaultUrgencyCoefficients()
+
+	tests := []struct {
+		name     string
+		line     string
+		expected float64
+	}{
+		{name: "no attributes", line: "Plain task", expected: 0},
+		{name: "priority A", line: "(A) Task", expected: 6.0},
+		{name: "priority D has no weight", line: "(D) Task", expected: 0},
+		{name: "project", line: "Task +work", expected: 1.0},
+		{name: "a week overdue", line: "Task due:2025-01-08", expected: 12.0},
+		{name: "due today", line: "Task due:2025-01-15", expected: 12.0 * (14*0.8/21 + 0.2)},
+		{name: "due in two weeks or later", line: "Task due:2025-03-01", expected: 12.0 * 0.2},
+		{name: "a year old", line: "2024-01-01 Task", expected: 2.0},
+		{name: "half a year old", line: "2024-07-18 Task", expected: 2.0 * 181 / 365},
+		{name: "upcoming", line: "Task t:2025-01-20", expected: -3.0},
+		{name: "combined", line: "(B) 2024-01-01 Task +work due:2025-01-01", expected: 3.9 + 12.0 + 2.0 + 1.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todoTask, err := todotxt.ParseTask(tt.line)
+			if err != nil {
+				t.Fatal(err)
+			}
+			task, _ := NewTask(todoTask)
+			if got := task.Urgency(coefficients, calendar, now); math.Abs(got-tt.expected) > 1e-9 {
+				t.Errorf("Urgency(%q) = %v, expected %v", tt.line, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTask_UrgencyTags(t *testing.T) {
+	now := time.Date(2025, 1, 15, 9, 0, 0, 0, time.Local)
+	calendar := NewCalendar(time.Sunday)
+	coefficients := UrgencyCoefficients{
+		Tags: map[string]float64{"+work": 2.0, "@phone": 0.5, "waiting": -4.0, "+home": 10.0},
+	}
+
+	tests := []struct {
+		line     string
+		expected float64
+	}{
+		{line: "Task +Work", expected: 2.0},
+		{line: "Task +work @phone", expected: 2.5},
+		{line: "Task +work waiting:bob", expected: -2.0},
+		{line: "Task +homework @phones", expected: 0},
+	}
+
+	for _, tt := range tests {
+		todoTask, _ := todotxt.ParseTask(tt.line)
+		task, _ := NewTask(todoTask)
+		if got := task.Urgency(coefficients, calendar, now); math.Abs(got-tt.expected) > 1e-9 {
+			t.Errorf("Urgency(%q) = %v, expected %v", tt.line, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+// Package export writes tasks in structured formats for other tools,
+// such as JSON for jq, CSV or TSV for spreadsheets and iCalendar for
+// calendar apps (see WriteICS).
+//
+// JSON, CSV and TSV have the same fields, one record per task:
+//
+//	id              Line of the task in the todo file
+//	raw             The task line as written in the file
+//	completed       Whether the task is done
+//	completed_date  Completion date (YYYY-MM-DD), empty if none
+//	created_date    Creation date (YYYY-MM-DD), empty if none
+//	priority        Priority letter, empty if none
+//	text            Description without projects, contexts and tags
+//	projects        Projects without the leading +
+//	contexts        Contexts without the leading @
+//	due             Due date (YYYY-MM-DD), empty if none
+//	tags            All other key:value tags, such as t, rec and deleted_at
+//	deleted         Whether the task is deleted (has deleted_at)
+//
+// In JSON, empty dates and the priority are null, projects and contexts are
+// arrays and tags is an object. In CSV and TSV, the first row is the header,
+// projects and contexts are separated by spaces and tags are written as
+// space-separated key:value pairs sorted by key.
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	todotxt "github.com/1set/todotxt"
+	"github.com/yuucu/todotui/pkg/domain"
+)
+
+// Format is an output format for tasks
+type Format string
+
+// Output formats
+const (
+	FormatJSON Format = "json"
+	FormatCSV  Format = "csv"
+	FormatTSV  Format = "tsv"
+	FormatICS  Format = "ics"
+)
+
+// Formats lists the formats accepted by ParseFormat
+var Formats = []Format{FormatJSON, FormatCSV, FormatTSV, FormatICS}
+
+// ErrUnknownFormat is returned by ParseFormat for an unsupported format
+var ErrUnknownFormat = errors.New("unknown format")
+
+// Columns are the fields of a record, in the order of the CSV and TSV columns
+var Columns = []string{
+	"id", "raw", "completed", "completed_date", "created_date", "priority",
+	"text", "projects", "contexts", "due", "tags", "deleted",
+}
+
+// Record is a task as written by Write. See the package documentation for the fields.
+type Record struct {
+	ID            int               `json:"id"`
+	Raw           string            `json:"raw"`
+	Completed     bool              `json:"completed"`
+	CompletedDate *string           `json:"completed_date"`
+	CreatedDate   *string           `json:"created_date"`
+	Priority      *string           `json:"priority"`
+	Text          string            `json:"text"`
+	Projects      []string          `json:"projects"`
+	Contexts      []string          `json:"contexts"`
+	Due           *string           `json:"due"`
+	Tags          map[string]string `json:"tags"`
+	Deleted       bool              `json:"deleted"`
+}
+
+// ParseFormat returns the format with the given name
+func ParseFormat(name string) (Format, error) {
+	format := Format(strings.ToLower(name))
+	if !slices.Contains(Formats, format) {
+		return "", fmt.Errorf("%w %q (expected json, csv, tsv or ics)", ErrUnknownFormat, name)
+	}
+	return format, nil
+}
+
+// NewRecord returns the record of a task
+func NewRecord(task *domain.Task) Record {
+	t := task.ToTodoTxtTask()
+
+	// Original is kept when a task is modified in memory,
+	// so use it only while it still describes the task
+	raw := t.String()
+	if original, err := todotxt.ParseTask(t.Original); err == nil && original.String() == raw {
+		raw = strings.TrimSpace(t.Original)
+	}
+
+	record := Record{
+		ID:        task.ID().Line,
+		Raw:       raw,
+		Completed: t.Completed,
+		Text:      t.Todo,
+		Projects:  nonNil(t.Projects),
+		Contexts:  nonNil(t.Contexts),
+		Tags:      make(map[string]string, len(t.AdditionalTags)),
+		Deleted:   task.IsDeleted(),
+	}
+	if t.HasCompletedDate() {
+		record.CompletedDate = formatDate(t.CompletedDate)
+	}
+	if t.HasCreatedDate() {
+		record.CreatedDate = formatDate(t.CreatedDate)
+	}
+	if t.HasPriority() {
+		priority := t.Priority
+		record.Priority = &priority
+	}
+	if t.HasDueDate() {
+		record.Due = formatDate(t.DueDate)
+	}
+	for key, value := range t.AdditionalTags {
+		record.Tags[key] = value
+	}
+	return record
+}
+
+// NewRecords returns the records of tasks
+func NewRecords(tasks domain.Tasks) []Record {
+	records := make([]Record, 0, len(tasks))
+	for i := range tasks {
+		records = append(records, NewRecord(&tasks[i]))
+	}
+	return records
+}
+
+// Write writes tasks to w in the given format
+func Write(w io.Writer, format Format, tasks domain.Tasks) error {
+	records := NewRecords(tasks)
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(records)
+	case FormatCSV:
+		return writeCSV(w, records)
+	case FormatTSV:
+		return writeTSV(w, records)
+	case FormatICS:
+		return WriteICS(w, tasks, time.Now())
+	default:
+		return fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+}
+
+// writeCSV writes records as RFC 4180 CSV with a header row
+func writeCSV(w io.Writer, records []Record) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(Columns); err != nil {
+		return err
+	}
+	for _, record := range records {
+		if err := writer.Write(record.fields()); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeTSV writes records as tab-separated values with a header row.
+// Fields are not quoted; tabs in a task become spaces.
+func writeTSV(w io.Writer, records []Record) error {
+	rows := [][]string{slices.Clone(Columns)}
+	for _, record := range records {
+		rows = append(rows, record.fields())
+	}
+	for _, row := range rows {
+		for i, field := range row {
+			row[i] = strings.ReplaceAll(field, "\t", " ")
+		}
+		if _, err := io.WriteString(w, strings.Join(row, "\t")+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fields returns the CSV and TSV columns of the record
+func (r Record) fields() []string {
+	keys := make([]string, 0, len(r.Tags))
+	for key := range r.Tags {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	tags := make([]string, 0, len(keys))
+	for _, key := range keys {
+		tags = append(tags, key+":"+r.Tags[key])
+	}
+
+	return []string{
+		strconv.Itoa(r.ID),
+		r.Raw,
+		strconv.FormatBool(r.Completed),
+		deref(r.CompletedDate),
+		deref(r.CreatedDate),
+		deref(r.Priority),
+		r.Text,
+		strings.Join(r.Projects, " "),
+		strings.Join(r.Contexts, " "),
+		deref(r.Due),
+		strings.Join(tags, " "),
+		strconv.FormatBool(r.Deleted),
+	}
+}
+
+// formatDate returns the date as YYYY-MM-DD
+func formatDate(date time.Time) *string {
+	formatted := date.Format(domain.DateFormat)
+	return &formatted
+}
+
+// deref returns the string s points to, or "" for nil
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// nonNil returns names, or an empty slice so JSON has [] rather than null
+func nonNil(names []string) []string {
+	if names == nil {
+		return []string{}
+	}
+	return slices.Clone(names)
+}
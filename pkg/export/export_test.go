@@ -0,0 +1,75 @@
+package export
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yuucu/todotui/pkg/domain"
+	"github.com/yuucu/todotui/pkg/todo"
+)
+
+// go test ./pkg/export -update で golden ファイルを書き直す
+var update = flag.Bool("update", false, "update golden files")
+
+// loadTestTasks loads testdata/todo.txt like the todo file, so IDs are line numbers
+func loadTestTasks(t *testing.T) domain.Tasks {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "todo.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "todo.txt")
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	list, err := todo.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return domain.NewTasks(list)
+}
+
+func TestWrite(t *testing.T) {
+	tasks := loadTestTasks(t)
+
+	for _, format := range []Format{FormatJSON, FormatCSV, FormatTSV} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, format, tasks); err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+			compareGolden(t, "todo."+string(format), buf.String())
+		})
+	}
+}
+
+// compareGolden compares got with testdata/name, or writes it there with -update
+func compareGolden(t *testing.T, name, got string) {
+	t.Helper()
+	golden := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(golden, []byte(got), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(expected) {
+		t.Errorf("output differs from %s:\n%s", golden, got)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if format, err := ParseFormat("JSON"); err != nil || format != FormatJSON {
+		t.Errorf("ParseFormat(JSON) = %q, %v", format, err)
+	}
+	if _, err := ParseFormat("xml"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("ParseFormat(xml) error = %v, expected ErrUnknownFormat", err)
+	}
+}
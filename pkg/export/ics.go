@@ -0,0 +1,177 @@
+package export
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/yuucu/todotui/pkg/domain"
+)
+
+// iCalendar の定数
+const (
+	icsProductID  = "-//todotui//todotui//EN"
+	icsDateFormat = "20060102"
+	icsTimeFormat = "20060102T150405Z"
+	icsLineLength = 75 // Octets per line before folding (RFC 5545 3.1)
+)
+
+// Tag whose value is used as the UID of a task
+const icsUIDTag = "uid"
+
+// Hex digits of the hash in generated UIDs
+const icsUIDLength = 16
+
+// WriteICS writes tasks as an RFC 5545 calendar with a VTODO for every task
+// that is not deleted. now is written as the DTSTAMP of every VTODO.
+//
+// A VTODO has the description as SUMMARY and the task line as DESCRIPTION,
+// DUE from due:, DTSTART from t:, PRIORITY 1, 3, 5 and 7 for (A) to (D),
+// CATEGORIES from projects and contexts, and STATUS COMPLETED with the
+// completion date for done tasks.
+//
+// The UID is the value of a uid: tag, or is made from the description,
+// projects, contexts and creation date, so it stays the same when the
+// priority, dates or other tags change, when the task is completed and when
+// it moves in the file. Generated UIDs are not stable in every case: the due
+// date is part of the UID of a recurring task, and identical tasks are
+// numbered in file order, so adding or removing one renumbers those below it.
+// A uid: tag keeps the UID in every case.
+func WriteICS(w io.Writer, tasks domain.Tasks, now time.Time) error {
+	var b strings.Builder
+	writeICSLine(&b, "BEGIN:VCALENDAR")
+	writeICSLine(&b, "VERSION:2.0")
+	writeICSLine(&b, "PRODID:"+icsProductID)
+	writeICSLine(&b, "CALSCALE:GREGORIAN")
+
+	uids := make(map[string]int)
+	for i := range tasks {
+		task := &tasks[i]
+		if task.IsDeleted() {
+			continue
+		}
+		writeVTODO(&b, task, icsUID(task, uids), now)
+	}
+
+	writeICSLine(&b, "END:VCALENDAR")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeVTODO writes the VTODO of a task
+func writeVTODO(b *strings.Builder, task *domain.Task, uid string, now time.Time) {
+	t := task.ToTodoTxtTask()
+
+	writeICSLine(b, "BEGIN:VTODO")
+	writeICSLine(b, "UID:"+escapeICSText(uid))
+	writeICSLine(b, "DTSTAMP:"+now.UTC().Format(icsTimeFormat))
+	if t.HasCreatedDate() {
+		writeICSLine(b, "CREATED:"+t.CreatedDate.UTC().Format(icsTimeFormat))
+	}
+	writeICSLine(b, "SUMMARY:"+escapeICSText(t.Todo))
+	writeICSLine(b, "DESCRIPTION:"+escapeICSText(t.String()))
+
+	// DTSTART は DUE より前でなければならない
+	threshold, hasThreshold := task.GetThresholdDate()
+	if hasThreshold && (!t.HasDueDate() || threshold.Before(t.DueDate)) {
+		writeICSLine(b, "DTSTART;VALUE=DATE:"+threshold.Format(icsDateFormat))
+	}
+	if t.HasDueDate() {
+		writeICSLine(b, "DUE;VALUE=DATE:"+t.DueDate.Format(icsDateFormat))
+	}
+	if priority := icsPriority(t.Priority); priority != 0 {
+		writeICSLine(b, fmt.Sprintf("PRIORITY:%d", priority))
+	}
+
+	categories := make([]string, 0, len(t.Projects)+len(t.Contexts))
+	for _, name := range append(slices.Clone(t.Projects), t.Contexts...) {
+		if escaped := escapeICSText(name); !slices.Contains(categories, escaped) {
+			categories = append(categories, escaped)
+		}
+	}
+	if len(categories) > 0 {
+		writeICSLine(b, "CATEGORIES:"+strings.Join(categories, ","))
+	}
+
+	if t.Completed {
+		writeICSLine(b, "STATUS:COMPLETED")
+		if t.HasCompletedDate() {
+			writeICSLine(b, "COMPLETED:"+t.CompletedDate.UTC().Format(icsTimeFormat))
+		}
+	} else {
+		writeICSLine(b, "STATUS:NEEDS-ACTION")
+	}
+	writeICSLine(b, "END:VTODO")
+}
+
+// icsUID returns the UID of a task. Tasks with the same description, projects,
+// contexts and creation date are numbered in file order, counted in seen.
+// Every occurrence of a recurring task is a task of its own, so the due date
+// is part of their UID.
+func icsUID(task *domain.Task, seen map[string]int) string {
+	t := task.ToTodoTxtTask()
+	if uid := strings.TrimSpace(t.AdditionalTags[icsUIDTag]); uid != "" {
+		return uid
+	}
+
+	fields := []string{strings.ToLower(strings.Join(strings.Fields(t.Todo), " "))}
+	for _, project := range t.Projects {
+		fields = append(fields, "+"+strings.ToLower(project))
+	}
+	for _, context := range t.Contexts {
+		fields = append(fields, "@"+strings.ToLower(context))
+	}
+	slices.Sort(fields[1:])
+	if t.HasCreatedDate() {
+		fields = append(fields, t.CreatedDate.Format(domain.DateFormat))
+	}
+	if _, recurring := t.AdditionalTags[domain.TaskFieldRecurrence]; recurring && t.HasDueDate() {
+		fields = append(fields, domain.TaskFieldDuePrefix+t.DueDate.Format(domain.DateFormat))
+	}
+
+	key := strings.Join(fields, " ")
+	if n := seen[key]; n > 0 {
+		seen[key]++
+		key = fmt.Sprintf("%s #%d", key, n)
+	} else {
+		seen[key] = 1
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])[:icsUIDLength] + "@todotui"
+}
+
+// icsPriority returns the iCalendar priority of (A) to (D): 1 to 4 is high
+// and 5 medium, so (A) is 1, (B) 3, (C) 5 and (D) 7. Others are 0 (undefined).
+func icsPriority(priority string) int {
+	if len(priority) != 1 || priority[0] < 'A' || priority[0] > 'D' {
+		return 0
+	}
+	return 1 + 2*int(priority[0]-'A')
+}
+
+// escapeICSText escapes a TEXT value (RFC 5545 3.3.11)
+func escapeICSText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`, "\r", "").Replace(text)
+}
+
+// writeICSLine writes a content line with CRLF, folded into lines of at most
+// 75 octets without splitting a UTF-8 character (RFC 5545 3.1)
+func writeICSLine(b *strings.Builder, line string) {
+	limit := icsLineLength
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// 続きの行は先頭の空白の分だけ短くなる
+		limit = icsLineLength - 1
+	}
+	b.WriteString(line + "\r\n")
+}
@@ -0,0 +1,136 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	todotxt "github.com/1set/todotxt"
+	"github.com/yuucu/todotui/pkg/domain"
+)
+
+func TestWriteICS(t *testing.T) {
+	tasks := loadTestTasks(t)
+	now := time.Date(2025, 1, 15, 9, 30, 0, 0, time.UTC)
+
+	var buf strings.Builder
+	if err := WriteICS(&buf, tasks, now); err != nil {
+		t.Fatalf("WriteICS failed: %v", err)
+	}
+	compareGolden(t, "todo.ics", buf.String())
+}
+
+// parseTasks returns tasks parsed from lines, numbered from line 1
+func parseTasks(t *testing.T, lines ...string) domain.Tasks {
+	t.Helper()
+	var list todotxt.TaskList
+	for i, line := range lines {
+		task, err := todotxt.ParseTask(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		task.ID = i + 1
+		list = append(list, *task)
+	}
+	return domain.NewTasks(list)
+}
+
+func TestICSUID(t *testing.T) {
+	uid := func(tasks domain.Tasks, index int) string {
+		seen := make(map[string]int)
+		var result string
+		for i := range tasks {
+			if got := icsUID(&tasks[i], seen); i == index {
+				result = got
+			}
+		}
+		return result
+	}
+
+	original := uid(parseTasks(t, "2025-01-10 Write report +work due:2025-01-17"), 0)
+
+	// 優先度や期日を変えたり、完了・移動したりしても UID は変わらない
+	for _, tasks := range []domain.Tasks{
+		parseTasks(t, "(A) 2025-01-10 Write report +work due:2025-01-20"),
+		parseTasks(t, "x 2025-01-16 2025-01-10 Write report +work due:2025-01-17"),
+		parseTasks(t, "Other task", "2025-01-10 Write report +work"),
+	} {
+		index := len(tasks) - 1
+		if got := uid(tasks, index); got != original {
+			t.Errorf("UID of %q = %q, expected %q", tasks[index].String(), got, original)
+		}
+	}
+
+	// 同じ内容のタスクや繰り返しの各回は別の UID になる
+	same := parseTasks(t, "Call mom", "Call mom")
+	if uid(same, 0) == uid(same, 1) {
+		t.Errorf("identical tasks should have different UIDs")
+	}
+	recurring := parseTasks(t, "x 2025-01-14 Water plants rec:1w due:2025-01-14", "Water plants rec:1w due:2025-01-21")
+	if uid(recurring, 0) == uid(recurring, 1) {
+		t.Errorf("occurrences of a recurring task should have different UIDs")
+	}
+
+	if got := uid(parseTasks(t, "Write report uid:abc-123"), 0); got != "abc-123" {
+		t.Errorf("UID = %q, expected the uid: tag", got)
+	}
+
+	// 繰り返しタスクの期日を変えると UID も変わる
+	moved := parseTasks(t, "Water plants rec:1w due:2025-01-22")
+	if uid(moved, 0) == uid(recurring, 1) {
+		t.Errorf("changing the due date of a recurring task should change its UID")
+	}
+	tagged := []domain.Tasks{
+		parseTasks(t, "Water plants rec:1w due:2025-01-21 uid:plants"),
+		parseTasks(t, "Water plants rec:1w due:2025-01-22 uid:plants"),
+	}
+	if uid(tagged[0], 0) != uid(tagged[1], 0) {
+		t.Errorf("uid: tag should keep the UID of a recurring task when its due date changes")
+	}
+
+	// 同じ内容のタスクは上から順に番号が振られるので、前のものを消すと UID が変わる
+	remaining := parseTasks(t, "Call mom")
+	if uid(remaining, 0) == uid(same, 1) {
+		t.Errorf("removing an identical task above should renumber the tasks below it")
+	}
+	if uid(remaining, 0) != uid(same, 0) {
+		t.Errorf("the remaining task should take the UID of the first identical task")
+	}
+	tagged = []domain.Tasks{
+		parseTasks(t, "Call mom uid:mom-1", "Call mom uid:mom-2"),
+		parseTasks(t, "Call mom uid:mom-2"),
+	}
+	if uid(tagged[0], 1) != uid(tagged[1], 0) {
+		t.Errorf("uid: tag should keep the UID when an identical task above is removed")
+	}
+}
+
+func TestWriteICSLine(t *testing.T) {
+	var b strings.Builder
+	writeICSLine(&b, "SUMMARY:"+escapeICSText("Buy milk, eggs; and\\or bread"))
+	if got := b.String(); got != `SUMMARY:Buy milk\, eggs\; and\\or bread`+"\r\n" {
+		t.Errorf("escaped line = %q", got)
+	}
+
+	// 75 オクテットで折り返し、マルチバイト文字は分割しない
+	b.Reset()
+	line := "SUMMARY:" + strings.Repeat("あ", 40)
+	writeICSLine(&b, line)
+	folded := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	if len(folded) < 2 {
+		t.Fatalf("line should be folded: %q", b.String())
+	}
+	var unfolded string
+	for i, part := range folded {
+		if len(part) > 75 {
+			t.Errorf("line %d has %d octets", i, len(part))
+		}
+		if i > 0 {
+			part = strings.TrimPrefix(part, " ")
+		}
+		unfolded += part
+	}
+	if unfolded != line {
+		t.Errorf("unfolded line = %q, expected %q", unfolded, line)
+	}
+}
@@ -0,0 +1,220 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	todotxt "github.com/1set/todotxt"
+	"github.com/yuucu/todotui/pkg/domain"
+)
+
+// Column names accepted for each field, after normalizeColumn
+var csvColumns = map[string][]string{
+	"raw":            {"raw", "line", "todo_txt"},
+	"text":           {"text", "description", "title", "task", "name", "summary", "subject", "content"},
+	"priority":       {"priority", "pri"},
+	"projects":       {"projects", "project", "list"},
+	"contexts":       {"contexts", "context", "tags", "labels"},
+	"due":            {"due", "due_date", "deadline"},
+	"completed":      {"completed", "done", "status"},
+	"completed_date": {"completed_date", "completed_at", "completion_date", "done_date"},
+	"created_date":   {"created_date", "created_at", "created", "creation_date", "entry"},
+}
+
+// Date layouts accepted in CSV files, tried in order
+var csvDateLayouts = []string{
+	domain.DateFormat, time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006/01/02", "2006/1/2",
+}
+
+// Priority names and the todo.txt priorities they become.
+// Single letters are todo.txt priorities, so H, M and L are not names here.
+var csvPriorities = map[string]string{
+	"high": "A", "p1": "A",
+	"medium": "B", "med": "B", "normal": "B", "p2": "B",
+	"low": "C", "p3": "C",
+	"p4": "D",
+}
+
+// Values of the completed column that mean the task is done
+var csvDoneValues = []string{"true", "yes", "y", "1", "x", "done", "completed", "complete", "closed"}
+
+// CSVReader reads CSV files with a header row.
+//
+// Columns are matched by name, ignoring case: a "raw" column is read as a
+// todo.txt line, as written by "todotui export --format csv". Otherwise the
+// task is made from text (or description, title, ...), priority (a letter or
+// high, medium and low), projects, contexts (or tags), due, completed,
+// completed_date and created_date. Projects, contexts and tags may be
+// separated by spaces or commas. Other columns are ignored.
+type CSVReader struct{}
+
+// Read implements Reader
+func (r *CSVReader) Read(in io.Reader) ([]*todotxt.Task, error) {
+	reader := csv.NewReader(in)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+
+	// フィールド名 → 列番号
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = normalizeColumn(name)
+		for field, aliases := range csvColumns {
+			if _, found := columns[field]; !found && slices.Contains(aliases, name) {
+				columns[field] = i
+			}
+		}
+	}
+	_, hasRaw := columns["raw"]
+	_, hasText := columns["text"]
+	if !hasRaw && !hasText {
+		return nil, errors.New("invalid CSV: no raw, text or description column")
+	}
+
+	var tasks []*todotxt.Task
+	for row := 2; ; row++ {
+		record, readErr := reader.Read()
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", readErr)
+		}
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		line := field("raw")
+		if line == "" {
+			if line, err = csvTaskLine(field); err != nil {
+				return nil, fmt.Errorf("row %d: %w", row, err)
+			}
+		}
+		if line == "" {
+			continue
+		}
+		task, parseErr := todotxt.ParseTask(line)
+		if parseErr != nil {
+			return nil, fmt.Errorf("row %d: %w", row, parseErr)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// csvTaskLine builds a todo.txt line from the fields of a CSV row,
+// or returns "" for a row without text
+func csvTaskLine(field func(string) string) (string, error) {
+	text := strings.Join(strings.Fields(field("text")), " ")
+	if text == "" {
+		return "", nil
+	}
+
+	dates := make(map[string]string)
+	for _, name := range []string{"due", "completed_date", "created_date"} {
+		date, err := csvDate(field(name))
+		if err != nil {
+			return "", err
+		}
+		dates[name] = date
+	}
+
+	var parts []string
+	if slices.Contains(csvDoneValues, strings.ToLower(field("completed"))) || dates["completed_date"] != "" {
+		parts = append(parts, "x")
+		if dates["completed_date"] != "" {
+			parts = append(parts, dates["completed_date"])
+		}
+	}
+	priority, err := csvPriority(field("priority"))
+	if err != nil {
+		return "", err
+	}
+	if priority != "" {
+		parts = append(parts, "("+priority+")")
+	}
+	if dates["created_date"] != "" {
+		parts = append(parts, dates["created_date"])
+	}
+	parts = append(parts, text)
+
+	for _, project := range splitNames(field("projects"), "+") {
+		parts = append(parts, "+"+project)
+	}
+	for _, context := range splitNames(field("contexts"), "@") {
+		parts = append(parts, "@"+context)
+	}
+	if dates["due"] != "" {
+		parts = append(parts, domain.TaskFieldDuePrefix+dates["due"])
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// csvDate converts a date in one of csvDateLayouts to a todo.txt date
+func csvDate(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	for _, layout := range csvDateLayouts {
+		if date, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return date.Format(domain.DateFormat), nil
+		}
+	}
+	return "", fmt.Errorf("invalid date %q", value)
+}
+
+// csvPriority converts a priority such as "A", "high" or "1" to a todo.txt priority
+func csvPriority(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	if priority, ok := csvPriorities[strings.ToLower(value)]; ok {
+		return priority, nil
+	}
+	if upper := strings.ToUpper(value); len(upper) == 1 && upper[0] >= 'A' && upper[0] <= 'Z' {
+		return upper, nil
+	}
+	if n, err := strconv.Atoi(value); err == nil && n >= 1 && n <= 26 {
+		return string(rune('A' + n - 1)), nil
+	}
+	return "", fmt.Errorf("invalid priority %q", value)
+}
+
+// splitNames splits a list of names separated by spaces or commas,
+// removing the prefix (+ or @) if they have it
+func splitNames(value, prefix string) []string {
+	var names []string
+	for _, name := range strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == ';'
+	}) {
+		if name = strings.TrimPrefix(name, prefix); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// normalizeColumn returns a column name in lower case with _ between words,
+// without the byte order mark some spreadsheets put before the first one
+func normalizeColumn(name string) string {
+	name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+	return strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return r == ' ' || r == '-' || r == '_'
+	}), "_")
+}
@@ -0,0 +1,53 @@
+package importer
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCSVReader_Read(t *testing.T) {
+	input := "\ufeffTitle,Priority,Project,Tags,Due Date,Status,Created At,Notes\n" +
+		"Write report,high,work,\"office, email\",2025-01-17,pending,2025-01-10,ignored\n" +
+		"Buy milk,,home,,,done,,\n" +
+		",,,,,,,\n" +
+		"Call mom,B,,@phone,2025/1/20,,,\n"
+	expected := []string{
+		"(A) 2025-01-10 Write report @email @office +work due:2025-01-17",
+		"x Buy milk +home",
+		"(B) Call mom @phone due:2025-01-20",
+	}
+	if got := taskLines(t, &CSVReader{}, input); !slices.Equal(got, expected) {
+		t.Errorf("Read() = %q, expected %q", got, expected)
+	}
+}
+
+func TestCSVReader_ReadExport(t *testing.T) {
+	// todotui export --format csv の出力はそのまま読み戻せる
+	input := "id,raw,completed,text\n" +
+		"1,\"(A) 2025-01-10 Write \"\"weekly report\"\" +work due:2025-01-17\",false,ignored\n" +
+		"3,x 2025-01-14 Buy milk +home,true,ignored\n"
+	expected := []string{
+		`(A) 2025-01-10 Write "weekly report" +work due:2025-01-17`,
+		"x 2025-01-14 Buy milk +home",
+	}
+	if got := taskLines(t, &CSVReader{}, input); !slices.Equal(got, expected) {
+		t.Errorf("Read() = %q, expected %q", got, expected)
+	}
+}
+
+func TestCSVReader_ReadErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "no text column", input: "priority,due\nA,2025-01-01\n"},
+		{name: "invalid date", input: "text,due\nTask,someday\n"},
+		{name: "invalid priority", input: "text,priority\nTask,urgent\n"},
+	}
+	for _, tt := range tests {
+		if _, err := (&CSVReader{}).Read(strings.NewReader(tt.input)); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Package importer reads tasks exported by other tools, such as Taskwarrior,
+// Markdown checklists and CSV files, as todo.txt tasks.
+//
+// Each format has a Reader. Formats are looked up by name or detected from the
+// file extension, and more can be added with Register.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	todotxt "github.com/1set/todotxt"
+	"github.com/yuucu/todotui/pkg/domain"
+)
+
+// ErrUnknownFormat is returned for a format that has no reader
+var ErrUnknownFormat = errors.New("unknown import format")
+
+// Reader reads tasks in the format of another tool
+type Reader interface {
+	// Read returns the tasks in r as todo.txt tasks, in the order they appear
+	Read(r io.Reader) ([]*todotxt.Task, error)
+}
+
+// Format is a format that can be imported
+type Format struct {
+	Name       string   // Name given to "todotui import --from"
+	Extensions []string // File extensions detected as this format, with the dot
+	Reader     Reader
+}
+
+// formats are the registered formats, in the order they are listed
+var formats = []Format{
+	{Name: "taskwarrior", Extensions: []string{".json"}, Reader: &TaskwarriorReader{}},
+	{Name: "markdown", Extensions: []string{".md", ".markdown"}, Reader: &MarkdownReader{}},
+	{Name: "csv", Extensions: []string{".csv"}, Reader: &CSVReader{}},
+}
+
+// Register adds a format, replacing any format with the same name
+func Register(format Format) {
+	formats = slices.DeleteFunc(formats, func(f Format) bool {
+		return f.Name == format.Name
+	})
+	formats = append(formats, format)
+}
+
+// Names returns the names of the registered formats
+func Names() []string {
+	names := make([]string, 0, len(formats))
+	for _, format := range formats {
+		names = append(names, format.Name)
+	}
+	return names
+}
+
+// Lookup returns the format with the given name
+func Lookup(name string) (Format, error) {
+	for _, format := range formats {
+		if strings.EqualFold(format.Name, name) {
+			return format, nil
+		}
+	}
+	return Format{}, fmt.Errorf("%w %q (expected %s)", ErrUnknownFormat, name, strings.Join(Names(), ", "))
+}
+
+// Detect returns the format of a file from its extension
+func Detect(path string) (Format, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, format := range formats {
+		if slices.Contains(format.Extensions, ext) {
+			return format, nil
+		}
+	}
+	return Format{}, fmt.Errorf("%w: cannot tell the format of %s from its extension", ErrUnknownFormat, path)
+}
+
+// Duplicate is an imported task that is already in the todo file
+type Duplicate struct {
+	Task *todotxt.Task
+	Line int // Line of the matching task in the todo file
+}
+
+// Plan is what an import adds to the todo file
+type Plan struct {
+	Add        []*todotxt.Task
+	Duplicates []Duplicate
+}
+
+// NewPlan matches imported tasks against the tasks in the todo file.
+// A task is a duplicate if a task with the same description, projects and
+// contexts exists, whether it is done or not, so importing the same
+// export twice adds nothing the second time. Each task in the file matches
+// one imported task at most, so repeated tasks such as the occurrences of a
+// recurring task are all kept.
+func NewPlan(existing domain.Tasks, imported []*todotxt.Task) Plan {
+	lines := make(map[string][]int, len(existing))
+	for i := range existing {
+		key := dedupKey(existing[i].ToTodoTxtTask())
+		lines[key] = append(lines[key], existing[i].ID().Line)
+	}
+
+	var plan Plan
+	for _, task := range imported {
+		key := dedupKey(task)
+		if matches := lines[key]; len(matches) > 0 {
+			plan.Duplicates = append(plan.Duplicates, Duplicate{Task: task, Line: matches[0]})
+			lines[key] = matches[1:]
+			continue
+		}
+		plan.Add = append(plan.Add, task)
+	}
+	return plan
+}
+
+// dedupKey returns what two tasks have in common when they are the same task:
+// the description, projects and contexts, ignoring case and spacing
+func dedupKey(task *todotxt.Task) string {
+	fields := []string{strings.ToLower(strings.Join(strings.Fields(task.Todo), " "))}
+	for _, project := range task.Projects {
+		fields = append(fields, "+"+strings.ToLower(project))
+	}
+	for _, context := range task.Contexts {
+		fields = append(fields, "@"+strings.ToLower(context))
+	}
+	slices.Sort(fields[1:])
+	return strings.Join(fields, " ")
+}
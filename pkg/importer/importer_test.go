@@ -0,0 +1,98 @@
+package importer
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"testing"
+
+	todotxt "github.com/1set/todotxt"
+	"github.com/yuucu/todotui/pkg/domain"
+)
+
+func TestLookupAndDetect(t *testing.T) {
+	if format, err := Lookup("Markdown"); err != nil || format.Name != "markdown" {
+		t.Errorf("Lookup(Markdown) = %q, %v", format.Name, err)
+	}
+	if _, err := Lookup("org"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Lookup(org) error = %v, expected ErrUnknownFormat", err)
+	}
+
+	tests := map[string]string{
+		"export.json":   "taskwarrior",
+		"NOTES.MD":      "markdown",
+		"tasks.csv":     "csv",
+		"list.markdown": "markdown",
+	}
+	for path, expected := range tests {
+		if format, err := Detect(path); err != nil || format.Name != expected {
+			t.Errorf("Detect(%q) = %q, %v, expected %q", path, format.Name, err, expected)
+		}
+	}
+	if _, err := Detect("tasks.txt"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Detect(tasks.txt) error = %v, expected ErrUnknownFormat", err)
+	}
+}
+
+// stubReader reads nothing; used to check that formats can be registered
+type stubReader struct{}
+
+func (stubReader) Read(io.Reader) ([]*todotxt.Task, error) { return nil, nil }
+
+func TestRegister(t *testing.T) {
+	saved := formats
+	t.Cleanup(func() { formats = saved })
+
+	Register(Format{Name: "org", Extensions: []string{".org"}, Reader: stubReader{}})
+	if format, err := Detect("notes.org"); err != nil || format.Name != "org" {
+		t.Errorf("Detect(notes.org) = %q, %v", format.Name, err)
+	}
+
+	// 同じ名前で登録すると置き換わる
+	Register(Format{Name: "csv", Extensions: []string{".tsv"}, Reader: stubReader{}})
+	if _, err := Detect("tasks.csv"); err == nil {
+		t.Errorf("the replaced csv format should no longer be detected")
+	}
+}
+
+func TestNewPlan(t *testing.T) {
+	var existing todotxt.TaskList
+	for i, line := range []string{
+		"Write report +work @office",
+		"x 2025-01-14 Water plants rec:1w",
+		"Water plants rec:1w due:2025-01-21",
+	} {
+		task, _ := todotxt.ParseTask(line)
+		task.ID = i + 1
+		existing = append(existing, *task)
+	}
+
+	var imported []*todotxt.Task
+	for _, line := range []string{
+		"(A) write   REPORT @office +work due:2025-01-17", // 1行目と同じタスク
+		"Write report",   // プロジェクトが違うので別のタスク
+		"x Water plants", // 2行目と同じタスク
+		"Water plants",   // 3行目と同じタスク
+		"water plants",   // 既存のタスクは一度しか対応しない
+	} {
+		task, _ := todotxt.ParseTask(line)
+		imported = append(imported, task)
+	}
+
+	plan := NewPlan(domain.NewTasks(existing), imported)
+	var added []string
+	for _, task := range plan.Add {
+		added = append(added, task.String())
+	}
+	if !slices.Equal(added, []string{"Write report", "water plants"}) {
+		t.Errorf("Add = %q", added)
+	}
+
+	var lines []int
+	for _, duplicate := range plan.Duplicates {
+		lines = append(lines, duplicate.Line)
+	}
+	if !slices.Equal(lines, []int{1, 2, 3}) {
+		t.Errorf("duplicate lines = %v, expected [1 2 3]", lines)
+	}
+}
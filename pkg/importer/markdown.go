@@ -0,0 +1,47 @@
+package importer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	todotxt "github.com/1set/todotxt"
+)
+
+// GitHub 形式のタスクリスト項目（例: "- [ ] Task", "  * [x] Done", "1. [ ] Step"）
+var markdownTaskRx = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+\[([ xX])\]\s+(.*\S)\s*$`)
+
+// MarkdownReader reads GitHub-style Markdown checklists.
+//
+// Every "- [ ]" item becomes an open task and every "- [x]" item a
+// completed one, at any nesting level; other lines are ignored. The item
+// text is read as todo.txt, so +projects, @contexts and due: dates in it
+// are kept.
+type MarkdownReader struct{}
+
+// Read implements Reader
+func (r *MarkdownReader) Read(in io.Reader) ([]*todotxt.Task, error) {
+	var tasks []*todotxt.Task
+	scanner := bufio.NewScanner(in)
+	for line := 1; scanner.Scan(); line++ {
+		match := markdownTaskRx.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		task, err := todotxt.ParseTask(match[2])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if strings.EqualFold(match[1], "x") {
+			// 完了日は分からないので付けない
+			task.Completed = true
+		}
+		tasks = append(tasks, task)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
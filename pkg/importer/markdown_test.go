@@ -0,0 +1,31 @@
+package importer
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMarkdownReader_Read(t *testing.T) {
+	input := `# Release checklist
+
+Some notes that are not tasks.
+
+- [ ] Write changelog +release due:2025-01-20
+- [x] Tag the release +release
+  - [ ] (A) Announce it @chat
+* [X] Update docs
+1. [ ] Numbered step
+- [] Not a task
+- Plain item
+`
+	expected := []string{
+		"Write changelog +release due:2025-01-20",
+		"x Tag the release +release",
+		"(A) Announce it @chat",
+		"x Update docs",
+		"Numbered step",
+	}
+	if got := taskLines(t, &MarkdownReader{}, input); !slices.Equal(got, expected) {
+		t.Errorf("Read() = %q, expected %q", got, expected)
+	}
+}
@@ -0,0 +1,210 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	todotxt "github.com/1set/todotxt"
+	"github.com/yuucu/todotui/pkg/domain"
+)
+
+// Date format of Taskwarrior exports, always in UTC
+const taskwarriorDateFormat = "20060102T150405Z"
+
+// Taskwarrior priorities and the todo.txt priorities they become
+var taskwarriorPriorities = map[string]string{"H": "A", "M": "B", "L": "C"}
+
+// Named Taskwarrior recurrences and the rec: values they become
+var taskwarriorRecurrences = map[string]string{
+	"daily": "1d", "day": "1d",
+	"weekdays": "1b",
+	"weekly":   "1w", "week": "1w",
+	"biweekly": "2w", "fortnight": "2w",
+	"monthly": "1m", "month": "1m",
+	"quarterly": "3m",
+	"yearly":    "1y", "year": "1y", "annual": "1y",
+}
+
+// Taskwarrior recurrences such as "2weeks" or "3d"
+var taskwarriorRecurrenceRx = regexp.MustCompile(`^(\d+)\s*(d|days?|w|wks?|weeks?|mo|mths?|months?|y|yrs?|years?)$`)
+
+// taskwarriorTask is a task in "task export" output
+type taskwarriorTask struct {
+	Description string   `json:"description"`
+	Status      string   `json:"status"`
+	Entry       string   `json:"entry"`
+	End         string   `json:"end"`
+	Due         string   `json:"due"`
+	Wait        string   `json:"wait"`
+	Scheduled   string   `json:"scheduled"`
+	Project     string   `json:"project"`
+	Tags        []string `json:"tags"`
+	Priority    string   `json:"priority"`
+	Recur       string   `json:"recur"`
+}
+
+// TaskwarriorReader reads the JSON written by "task export".
+//
+// Projects become +projects, tags become @contexts and the priorities H, M
+// and L become A, B and C. wait or scheduled becomes the threshold date t:,
+// and recur becomes rec: when it is an interval such as weekly or 2weeks.
+// Deleted tasks are marked with deleted_at, and recurring templates are
+// skipped since their instances are exported too.
+type TaskwarriorReader struct {
+	// Location dates are converted to, time.Local if nil
+	Location *time.Location
+}
+
+// Read implements Reader
+func (r *TaskwarriorReader) Read(in io.Reader) ([]*todotxt.Task, error) {
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return nil, err
+	}
+
+	// Taskwarrior 2.4 以降は配列、それ以前は1行に1タスクを書き出す
+	var exported []taskwarriorTask
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("[")) {
+		if jsonErr := json.Unmarshal(data, &exported); jsonErr != nil {
+			return nil, fmt.Errorf("invalid Taskwarrior export: %w", jsonErr)
+		}
+	} else {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		for {
+			var task taskwarriorTask
+			decodeErr := decoder.Decode(&task)
+			if errors.Is(decodeErr, io.EOF) {
+				break
+			}
+			if decodeErr != nil {
+				return nil, fmt.Errorf("invalid Taskwarrior export: %w", decodeErr)
+			}
+			exported = append(exported, task)
+		}
+	}
+
+	var tasks []*todotxt.Task
+	for i, exportedTask := range exported {
+		if exportedTask.Status == "recurring" {
+			continue
+		}
+		task, convertErr := r.convert(exportedTask)
+		if convertErr != nil {
+			return nil, fmt.Errorf("task %d: %w", i+1, convertErr)
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// convert returns a Taskwarrior task as a todo.txt task
+func (r *TaskwarriorReader) convert(exported taskwarriorTask) (*todotxt.Task, error) {
+	description := strings.Join(strings.Fields(exported.Description), " ")
+	if description == "" {
+		return nil, errors.New("no description")
+	}
+
+	threshold := exported.Wait
+	if threshold == "" {
+		threshold = exported.Scheduled
+	}
+	dates := make(map[string]string)
+	for name, value := range map[string]string{
+		"entry": exported.Entry, "end": exported.End, "due": exported.Due, "threshold": threshold,
+	} {
+		date, err := r.date(value)
+		if err != nil {
+			return nil, err
+		}
+		dates[name] = date
+	}
+
+	var parts []string
+	if exported.Status == "completed" {
+		parts = append(parts, "x")
+		if dates["end"] != "" {
+			parts = append(parts, dates["end"])
+		}
+	}
+	if priority, ok := taskwarriorPriorities[strings.ToUpper(exported.Priority)]; ok {
+		parts = append(parts, "("+priority+")")
+	}
+	if dates["entry"] != "" {
+		parts = append(parts, dates["entry"])
+	}
+	parts = append(parts, description)
+
+	if project := strings.Join(strings.Fields(exported.Project), "-"); project != "" {
+		parts = append(parts, "+"+project)
+	}
+	for _, tag := range exported.Tags {
+		parts = append(parts, "@"+tag)
+	}
+	if dates["due"] != "" {
+		parts = append(parts, domain.TaskFieldDuePrefix+dates["due"])
+	}
+	if dates["threshold"] != "" {
+		parts = append(parts, domain.TaskFieldThresholdPrefix+dates["threshold"])
+	}
+	if recurrence := taskwarriorRecurrence(exported.Recur); recurrence != "" {
+		parts = append(parts, domain.TaskFieldRecurrence+":"+recurrence)
+	}
+	if exported.Status == "deleted" {
+		deleted := dates["end"]
+		if deleted == "" {
+			deleted = time.Now().In(r.location()).Format(domain.DateFormat)
+		}
+		parts = append(parts, domain.TaskFieldDeletedPrefix+deleted)
+	}
+
+	return todotxt.ParseTask(strings.Join(parts, " "))
+}
+
+// date converts a Taskwarrior date to a todo.txt date, or "" if value is empty
+func (r *TaskwarriorReader) date(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	date, err := time.Parse(taskwarriorDateFormat, value)
+	if err != nil {
+		return "", fmt.Errorf("invalid date %q", value)
+	}
+	return date.In(r.location()).Format(domain.DateFormat), nil
+}
+
+// location returns the time zone dates are converted to
+func (r *TaskwarriorReader) location() *time.Location {
+	if r.Location == nil {
+		return time.Local
+	}
+	return r.Location
+}
+
+// taskwarriorRecurrence returns the rec: value of a Taskwarrior recurrence,
+// or "" if it has none. Taskwarrior repeats from the due date, like a strict
+// recurrence ("rec:+1w") in todo.txt.
+func taskwarriorRecurrence(recur string) string {
+	recur = strings.ToLower(strings.TrimSpace(recur))
+	if recur == "" {
+		return ""
+	}
+	if value, ok := taskwarriorRecurrences[recur]; ok {
+		return "+" + value
+	}
+	match := taskwarriorRecurrenceRx.FindStringSubmatch(recur)
+	if match == nil {
+		return ""
+	}
+	unit := match[2][:1]
+	if strings.HasPrefix(match[2], "mo") || strings.HasPrefix(match[2], "mth") {
+		unit = "m"
+	}
+	return "+" + match[1] + unit
+}
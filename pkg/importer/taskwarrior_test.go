@@ -0,0 +1,78 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// taskLines returns the todo.txt lines of tasks read by reader from input
+func taskLines(t *testing.T, reader Reader, input string) []string {
+	t.Helper()
+	tasks, err := reader.Read(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	lines := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		lines = append(lines, task.String())
+	}
+	return lines
+}
+
+func TestTaskwarriorReader_Read(t *testing.T) {
+	reader := &TaskwarriorReader{Location: time.UTC}
+	input := `[
+{"id":1,"description":"Write report","status":"pending","entry":"20250110T093000Z","due":"20250117T230000Z","project":"work","tags":["office","email"],"priority":"H","uuid":"a"},
+{"id":0,"description":"Buy milk","status":"completed","entry":"20250102T080000Z","end":"20250114T180000Z","project":"home","uuid":"b"},
+{"id":0,"description":"Old idea","status":"deleted","entry":"20250101T080000Z","end":"20250113T080000Z","uuid":"c"},
+{"id":2,"description":"Water   plants","status":"waiting","entry":"20250101T080000Z","wait":"20250120T000000Z","due":"20250121T000000Z","recur":"weekly","uuid":"d"},
+{"id":0,"description":"Water plants","status":"recurring","entry":"20250101T080000Z","recur":"weekly","uuid":"e"},
+{"id":3,"description":"Pay rent","status":"pending","recur":"2weeks","priority":"L","uuid":"f"}
+]`
+
+	expected := []string{
+		"(A) 2025-01-10 Write report @email @office +work due:2025-01-17",
+		"x 2025-01-14 2025-01-02 Buy milk +home",
+		"2025-01-01 Old idea deleted_at:2025-01-13",
+		"2025-01-01 Water plants rec:+1w t:2025-01-20 due:2025-01-21",
+		"(C) Pay rent rec:+2w",
+	}
+	got := taskLines(t, reader, input)
+	if strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Read() =\n%s\nexpected\n%s", strings.Join(got, "\n"), strings.Join(expected, "\n"))
+	}
+
+	// 古い形式（1行に1タスク）も読める
+	got = taskLines(t, reader, `{"description":"One","status":"pending"}
+{"description":"Two","status":"pending"}`)
+	if strings.Join(got, ",") != "One,Two" {
+		t.Errorf("Read() of one task per line = %q", got)
+	}
+
+	if _, err := reader.Read(strings.NewReader(`[{"description":"","status":"pending"}]`)); err == nil {
+		t.Errorf("a task without description should be an error")
+	}
+	if _, err := reader.Read(strings.NewReader(`[{"description":"X","due":"tomorrow"}]`)); err == nil {
+		t.Errorf("an invalid date should be an error")
+	}
+}
+
+func TestTaskwarriorRecurrence(t *testing.T) {
+	tests := map[string]string{
+		"":         "",
+		"daily":    "+1d",
+		"Monthly":  "+1m",
+		"3d":       "+3d",
+		"2weeks":   "+2w",
+		"6months":  "+6m",
+		"1yr":      "+1y",
+		"weekdays": "+1b",
+		"P1W":      "",
+	}
+	for recur, expected := range tests {
+		if got := taskwarriorRecurrence(recur); got != expected {
+			t.Errorf("taskwarriorRecurrence(%q) = %q, expected %q", recur, got, expected)
+		}
+	}
+}
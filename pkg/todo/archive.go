@@ -0,0 +1,108 @@
+package todo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	todotxt "github.com/1set/todotxt"
+)
+
+// todo.txtの慣習に従ったアーカイブファイル名
+const doneFileName = "done.txt"
+
+// ArchiveOptions controls which tasks Archive moves and where to
+type ArchiveOptions struct {
+	DoneFile string                   // Empty means DefaultDoneFile of the todo file
+	Select   func(*todotxt.Task) bool // Reports whether a task should be archived
+}
+
+// DefaultDoneFile returns the done.txt next to the todo file
+func DefaultDoneFile(todoPath string) string {
+	return filepath.Join(filepath.Dir(todoPath), doneFileName)
+}
+
+// doneFile returns the archive file for todoPath
+func (o ArchiveOptions) doneFile(todoPath string) string {
+	if o.DoneFile != "" {
+		return o.DoneFile
+	}
+	return DefaultDoneFile(todoPath)
+}
+
+// LoadDone reads the archived tasks of todoPath.
+// A missing done.txt is treated as an empty archive.
+func LoadDone(todoPath string, opts ArchiveOptions) (todotxt.TaskList, error) {
+	data, err := os.ReadFile(opts.doneFile(todoPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return todotxt.NewTaskList(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseTaskList(data), nil
+}
+
+// Archive moves the tasks selected by opts from the todo file to done.txt.
+// It works on the file as it is on disk, so unsaved changes must be saved
+// first. done.txt is written before the todo file, so an interruption
+// leaves a task in both files rather than in neither.
+// It returns the tasks left in the todo file and the tasks archived.
+func (s *Store) Archive(opts ArchiveOptions) (remaining, archived todotxt.TaskList, err error) {
+	err = withLock(s.path, s.lockTimeout, func() error {
+		current, _, err := readFile(s.path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		doc := parseDocument(current)
+		list := doc.tasks
+
+		var keep todotxt.TaskList
+		var archivedLines []string
+		for i := range list {
+			if opts.Select != nil && opts.Select(&list[i]) {
+				archived = append(archived, list[i])
+				archivedLines = append(archivedLines, doc.lines[list[i].ID-1])
+			} else {
+				keep = append(keep, list[i])
+			}
+		}
+		if len(archived) == 0 {
+			remaining = list
+			return nil
+		}
+
+		if err := appendDone(opts.doneFile(s.path), archivedLines); err != nil {
+			return err
+		}
+
+		// Archived lines are removed; everything else stays as it was
+		remaining, err = s.replace(current, doc.content(doc.render(keep)))
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return remaining, archived, nil
+}
+
+// appendDone appends task lines to the archive file, creating it if necessary.
+// The archive is shared by every instance working on the todo file, whose
+// lock the caller holds.
+func appendDone(doneFile string, taskLines []string) error {
+	existing, err := os.ReadFile(doneFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(doneFile), defaultDirMode); err != nil {
+		return err
+	}
+
+	lines := splitLines(string(existing))
+	lines = append(lines, taskLines...)
+	return writeFileAtomic(doneFile, []byte(joinLines(lines)))
+}
@@ -0,0 +1,128 @@
+package todo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	todotxt "github.com/1set/todotxt"
+	"github.com/yuucu/todotui/internal/testutil"
+)
+
+// selectCompleted archives completed tasks only
+func selectCompleted(task *todotxt.Task) bool {
+	return task.Completed
+}
+
+func TestStore_Archive(t *testing.T) {
+	tests := []struct {
+		name             string
+		todo             string
+		done             string
+		expectedTodo     string
+		expectedDone     string
+		expectedArchived int
+		description      string
+	}{
+		{
+			name:             "moves_completed",
+			todo:             "Task A\nx 2025-01-14 Task B\nTask C\n",
+			expectedTodo:     "Task A\nTask C\n",
+			expectedDone:     "x 2025-01-14 Task B\n",
+			expectedArchived: 1,
+			description:      "完了タスクをdone.txtへ移動する",
+		},
+		{
+			name:             "appends_to_existing_done",
+			todo:             "x 2025-01-14 Task B\nTask C\n",
+			done:             "x 2025-01-01 Old task",
+			expectedTodo:     "Task C\n",
+			expectedDone:     "x 2025-01-01 Old task\nx 2025-01-14 Task B\n",
+			expectedArchived: 1,
+			description:      "既存のdone.txtの末尾に追記する",
+		},
+		{
+			name:             "nothing_to_archive",
+			todo:             "Task A\n",
+			done:             "x 2025-01-01 Old task\n",
+			expectedTodo:     "Task A\n",
+			expectedDone:     "x 2025-01-01 Old task\n",
+			expectedArchived: 0,
+			description:      "対象がなければどちらのファイルも変更しない",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			todoPath := filepath.Join(dir, "todo.txt")
+			testutil.WriteExternally(t, todoPath, tt.todo)
+			if tt.done != "" {
+				testutil.WriteExternally(t, DefaultDoneFile(todoPath), tt.done)
+			}
+
+			store := NewStore(todoPath)
+			if _, err := store.Load(); err != nil {
+				t.Fatal(err)
+			}
+
+			remaining, archived, err := store.Archive(ArchiveOptions{Select: selectCompleted})
+			if err != nil {
+				t.Fatalf("Archive failed: %v", err)
+			}
+			if len(archived) != tt.expectedArchived {
+				t.Errorf("archived %d tasks, expected %d for %s", len(archived), tt.expectedArchived, tt.description)
+			}
+			if got := testutil.ReadFile(t, todoPath); got != tt.expectedTodo {
+				t.Errorf("todo.txt = %q, expected %q for %s", got, tt.expectedTodo, tt.description)
+			}
+			if got := remaining.String(); got != tt.expectedTodo {
+				t.Errorf("remaining = %q, expected %q", got, tt.expectedTodo)
+			}
+
+			doneContent, err := os.ReadFile(filepath.Join(dir, "done.txt"))
+			if err != nil && !os.IsNotExist(err) {
+				t.Fatal(err)
+			}
+			if string(doneContent) != tt.expectedDone {
+				t.Errorf("done.txt = %q, expected %q for %s", doneContent, tt.expectedDone, tt.description)
+			}
+
+			// アーカイブ後の保存が外部変更として扱われないこと
+			if _, err := store.Save(remaining); err != nil {
+				t.Errorf("Save after Archive failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestStore_ArchiveCustomDoneFile(t *testing.T) {
+	dir := t.TempDir()
+	todoPath := filepath.Join(dir, "todo.txt")
+	doneFile := filepath.Join(dir, "archive", "2025.txt")
+	testutil.WriteExternally(t, todoPath, "x Task A\nTask B\n")
+
+	opts := ArchiveOptions{DoneFile: doneFile, Select: selectCompleted}
+	if _, _, err := NewStore(todoPath).Archive(opts); err != nil {
+		t.Fatalf("Archive failed: %v", err)
+	}
+
+	done, err := LoadDone(todoPath, opts)
+	if err != nil {
+		t.Fatalf("LoadDone failed: %v", err)
+	}
+	if len(done) != 1 || done[0].Todo != "Task A" {
+		t.Errorf("LoadDone() = %v, expected the archived task", done)
+	}
+}
+
+func TestLoadDone_MissingFile(t *testing.T) {
+	todoPath := filepath.Join(t.TempDir(), "todo.txt")
+	done, err := LoadDone(todoPath, ArchiveOptions{})
+	if err != nil {
+		t.Fatalf("LoadDone failed: %v", err)
+	}
+	if len(done) != 0 {
+		t.Errorf("LoadDone() = %v, expected empty list", done)
+	}
+}
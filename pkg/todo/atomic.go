@@ -0,0 +1,113 @@
+package todo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// 新規作成するtodo.txtのデフォルトパーミッション（todotxtライブラリと同じ）
+const defaultFileMode = 0640
+
+// シンボリックリンクを辿る最大回数
+const maxSymlinkHops = 40
+
+// Indirections over the filesystem calls made by writeFileAtomic.
+// Tests replace these to simulate a crash or a full disk part-way through a save.
+var (
+	writeTempFile = func(f *os.File, data []byte) error {
+		_, err := f.Write(data)
+		return err
+	}
+	syncFile   = func(f *os.File) error { return f.Sync() }
+	renameFile = os.Rename
+)
+
+// resolveSymlinks follows path through any chain of symlinks and returns the
+// final target, even if that target does not exist yet.
+// Writing to the resolved path keeps the symlink itself intact.
+func resolveSymlinks(path string) (string, error) {
+	current := path
+	for range maxSymlinkHops {
+		info, err := os.Lstat(current)
+		if os.IsNotExist(err) {
+			return current, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			return current, nil
+		}
+
+		link, err := os.Readlink(current)
+		if err != nil {
+			return "", err
+		}
+		if !filepath.IsAbs(link) {
+			link = filepath.Join(filepath.Dir(current), link)
+		}
+		current = link
+	}
+	return "", fmt.Errorf("too many levels of symbolic links: %s", path)
+}
+
+// writeFileAtomic replaces the contents of path with data.
+// The data is written to a temporary file in the same directory, fsynced and
+// then renamed over the original, so readers see either the old or the new
+// contents but never a truncated file.
+// The permission bits of an existing file are preserved.
+func writeFileAtomic(path string, data []byte) (err error) {
+	target, err := resolveSymlinks(path)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(target)
+	if err = os.MkdirAll(dir, defaultDirMode); err != nil {
+		return err
+	}
+
+	mode := os.FileMode(defaultFileMode)
+	if info, statErr := os.Stat(target); statErr == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(target)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	// Remove the temporary file on any failure so that no debris is left behind
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if err = writeTempFile(tmp, data); err != nil {
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err = tmp.Chmod(mode); err != nil {
+		return fmt.Errorf("failed to set file mode: %w", err)
+	}
+	if err = syncFile(tmp); err != nil {
+		return fmt.Errorf("failed to sync temporary file: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file: %w", err)
+	}
+	if err = renameFile(tmpPath, target); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", target, err)
+	}
+
+	// Persist the rename itself; not every platform supports syncing a directory
+	if d, openErr := os.Open(dir); openErr == nil {
+		_ = d.Sync()
+		d.Close()
+	}
+
+	return nil
+}
@@ -0,0 +1,189 @@
+package todo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	todotxt "github.com/1set/todotxt"
+)
+
+// mustParseList builds a TaskList from task strings
+func mustParseList(t *testing.T, lines ...string) todotxt.TaskList {
+	t.Helper()
+	list := todotxt.NewTaskList()
+	for _, line := range lines {
+		task, err := todotxt.ParseTask(line)
+		if err != nil {
+			t.Fatalf("Failed to parse task %q: %v", line, err)
+		}
+		list.AddTask(task)
+	}
+	return list
+}
+
+// listTempFiles returns leftover temporary files in dir
+func listTempFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var temps []string
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), ".tmp-") {
+			temps = append(temps, entry.Name())
+		}
+	}
+	return temps
+}
+
+func TestSave_FailureKeepsOriginal(t *testing.T) {
+	injected := errors.New("injected failure")
+
+	tests := []struct {
+		name        string
+		inject      func()
+		description string
+	}{
+		{
+			name: "write_failure",
+			inject: func() {
+				writeTempFile = func(f *os.File, data []byte) error {
+					// 途中まで書き込んでから失敗させる
+					_, _ = f.Write(data[:len(data)/2])
+					return injected
+				}
+			},
+			description: "書き込み途中で失敗しても元のファイルは残る",
+		},
+		{
+			name: "sync_failure",
+			inject: func() {
+				syncFile = func(*os.File) error { return injected }
+			},
+			description: "fsyncに失敗しても元のファイルは残る",
+		},
+		{
+			name: "rename_failure",
+			inject: func() {
+				renameFile = func(string, string) error { return injected }
+			},
+			description: "renameに失敗しても元のファイルは残る",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			origWrite, origSync, origRename := writeTempFile, syncFile, renameFile
+			defer func() {
+				writeTempFile, syncFile, renameFile = origWrite, origSync, origRename
+			}()
+
+			dir := t.TempDir()
+			todoPath := filepath.Join(dir, "todo.txt")
+			original := "(A) Original task +keep\n"
+			if err := os.WriteFile(todoPath, []byte(original), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			tt.inject()
+
+			err := Save(mustParseList(t, "Replacement task", "Another replacement"), todoPath)
+			if !errors.Is(err, injected) {
+				t.Fatalf("Save() error = %v, expected injected failure for %s", err, tt.description)
+			}
+
+			content, err := os.ReadFile(todoPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != original {
+				t.Errorf("file content = %q, expected %q for %s", content, original, tt.description)
+			}
+
+			if temps := listTempFiles(t, dir); len(temps) != 0 {
+				t.Errorf("temporary files left behind: %v for %s", temps, tt.description)
+			}
+		})
+	}
+}
+
+func TestSave_PreservesFileMode(t *testing.T) {
+	dir := t.TempDir()
+	todoPath := filepath.Join(dir, "todo.txt")
+	if err := os.WriteFile(todoPath, []byte("Old task\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Save(mustParseList(t, "New task"), todoPath); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	info, err := os.Stat(todoPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("file mode = %v, expected %v", info.Mode().Perm(), os.FileMode(0600))
+	}
+}
+
+func TestSave_PreservesSymlink(t *testing.T) {
+	dir := t.TempDir()
+	realDir := filepath.Join(dir, "sync")
+	if err := os.MkdirAll(realDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	realPath := filepath.Join(realDir, "todo.txt")
+	if err := os.WriteFile(realPath, []byte("Old task\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	linkPath := filepath.Join(dir, "todo.txt")
+	if err := os.Symlink(filepath.Join("sync", "todo.txt"), linkPath); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	if err := Save(mustParseList(t, "New task"), linkPath); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	info, err := os.Lstat(linkPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		t.Error("symlink was replaced by a regular file")
+	}
+
+	content, err := os.ReadFile(realPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "New task\n" {
+		t.Errorf("symlink target content = %q, expected %q", content, "New task\n")
+	}
+
+	if temps := listTempFiles(t, realDir); len(temps) != 0 {
+		t.Errorf("temporary files left behind: %v", temps)
+	}
+}
+
+func TestSave_CreatesNewFile(t *testing.T) {
+	dir := t.TempDir()
+	todoPath := filepath.Join(dir, "nested", "todo.txt")
+
+	if err := Save(mustParseList(t, "First task"), todoPath); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	info, err := os.Stat(todoPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != defaultFileMode {
+		t.Errorf("file mode = %v, expected %v", info.Mode().Perm(), os.FileMode(defaultFileMode))
+	}
+}
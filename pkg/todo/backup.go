@@ -0,0 +1,239 @@
+package todo
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// バックアップIDに使用する時刻フォーマット（ファイル名として安全な形式）
+const backupIDFormat = "20060102-150405.000"
+
+// バックアップファイルの拡張子
+const backupExt = ".bak"
+
+// ErrBackupNotFound is returned when no backup matches the requested ID
+var ErrBackupNotFound = errors.New("backup not found")
+
+// BackupOptions controls the snapshots taken before each save
+type BackupOptions struct {
+	Enabled  bool
+	Dir      string        // Empty means DefaultBackupDir of the todo file
+	MaxCount int           // Number of backups to keep; 0 keeps all
+	MaxAge   time.Duration // Backups older than this are removed; 0 keeps all
+}
+
+// Backup describes a snapshot of a todo file
+type Backup struct {
+	ID   string
+	Path string
+	Time time.Time
+	Size int64
+}
+
+// DefaultBackupDir returns the backup directory used when none is configured
+func DefaultBackupDir(todoPath string) string {
+	return filepath.Join(filepath.Dir(todoPath), ".todotui", "backups")
+}
+
+// backupDir returns the directory holding backups of todoPath
+func (o BackupOptions) backupDir(todoPath string) string {
+	if o.Dir != "" {
+		return o.Dir
+	}
+	return DefaultBackupDir(todoPath)
+}
+
+// backupPrefix returns the file name prefix of backups of todoPath.
+// Including the file name lets several todo files share one backup directory.
+func backupPrefix(todoPath string) string {
+	return filepath.Base(todoPath) + "."
+}
+
+// createBackup stores data as a new backup of todoPath and applies retention
+func createBackup(todoPath string, data []byte, opts BackupOptions, now time.Time) error {
+	dir := opts.backupDir(todoPath)
+	if err := os.MkdirAll(dir, defaultDirMode); err != nil {
+		return err
+	}
+
+	id, err := nextBackupID(dir, backupPrefix(todoPath), now)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, backupPrefix(todoPath)+id+backupExt)
+
+	if err := writeFileAtomic(path, data); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	return pruneBackups(todoPath, opts, now)
+}
+
+// nextBackupID returns the ID of a backup taken at now. Several saves within
+// the same millisecond get a numbered suffix, counting on from the highest
+// one in dir so the new backup sorts as the newest even after pruning.
+func nextBackupID(dir, prefix string, now time.Time) (string, error) {
+	id := now.Format(backupIDFormat)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	next := -1
+	for _, entry := range entries {
+		name := strings.TrimSuffix(strings.TrimPrefix(entry.Name(), prefix), backupExt)
+		if name == id || strings.HasPrefix(name, id+"-") {
+			next = max(next, backupSequence(name)+1)
+		}
+	}
+	if next <= 0 {
+		return id, nil
+	}
+	return fmt.Sprintf("%s-%d", id, next), nil
+}
+
+// ListBackups returns the backups of todoPath, newest first
+func ListBackups(todoPath string, opts BackupOptions) ([]Backup, error) {
+	dir := opts.backupDir(todoPath)
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := backupPrefix(todoPath)
+	var backups []Backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, backupExt) {
+			continue
+		}
+
+		id := strings.TrimSuffix(strings.TrimPrefix(name, prefix), backupExt)
+		// The timestamp is the ID without any same-millisecond suffix
+		created, err := time.ParseInLocation(backupIDFormat, id[:min(len(id), len(backupIDFormat))], time.Local)
+		if err != nil {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		backups = append(backups, Backup{
+			ID:   id,
+			Path: filepath.Join(dir, name),
+			Time: created,
+			Size: info.Size(),
+		})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		if !backups[i].Time.Equal(backups[j].Time) {
+			return backups[i].Time.After(backups[j].Time)
+		}
+		return backupSequence(backups[i].ID) > backupSequence(backups[j].ID)
+	})
+	return backups, nil
+}
+
+// backupSequence returns the number of a backup among those taken within the
+// same millisecond: 0 for the first, then the number of its -N suffix
+func backupSequence(id string) int {
+	suffix := strings.TrimPrefix(id[min(len(id), len(backupIDFormat)):], "-")
+	n, err := strconv.Atoi(suffix)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// FindBackup returns the backup whose ID equals id or, failing that, the
+// only backup whose ID starts with id
+func FindBackup(todoPath string, opts BackupOptions, id string) (Backup, error) {
+	backups, err := ListBackups(todoPath, opts)
+	if err != nil {
+		return Backup{}, err
+	}
+
+	var matches []Backup
+	for _, backup := range backups {
+		if backup.ID == id {
+			return backup, nil
+		}
+		if strings.HasPrefix(backup.ID, id) {
+			matches = append(matches, backup)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return Backup{}, fmt.Errorf("%w: %s", ErrBackupNotFound, id)
+	case 1:
+		return matches[0], nil
+	default:
+		return Backup{}, fmt.Errorf("backup ID %q is ambiguous: %d backups match", id, len(matches))
+	}
+}
+
+// RestoreBackup replaces the todo file with the contents of the backup id.
+// The current contents are backed up first, so a restore can be undone.
+func RestoreBackup(todoPath string, opts BackupOptions, id string) (Backup, error) {
+	backup, err := FindBackup(todoPath, opts, id)
+	if err != nil {
+		return Backup{}, err
+	}
+
+	data, err := os.ReadFile(backup.Path)
+	if err != nil {
+		return Backup{}, err
+	}
+
+	err = withLock(todoPath, DefaultLockTimeout, func() error {
+		current, readErr := os.ReadFile(todoPath)
+		if readErr == nil && len(current) > 0 && !bytes.Equal(current, data) {
+			if err := createBackup(todoPath, current, opts, time.Now()); err != nil {
+				return err
+			}
+		}
+		return writeFileAtomic(todoPath, data)
+	})
+	if err != nil {
+		return Backup{}, err
+	}
+	return backup, nil
+}
+
+// pruneBackups removes backups beyond the configured count and age.
+// The newest backup is always kept.
+func pruneBackups(todoPath string, opts BackupOptions, now time.Time) error {
+	backups, err := ListBackups(todoPath, opts)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i, backup := range backups {
+		if i == 0 {
+			continue
+		}
+		tooMany := opts.MaxCount > 0 && i >= opts.MaxCount
+		tooOld := opts.MaxAge > 0 && now.Sub(backup.Time) > opts.MaxAge
+		if tooMany || tooOld {
+			if err := os.Remove(backup.Path); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,255 @@
+package todo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/yuucu/todotui/internal/testutil"
+)
+
+func TestCreateBackup_Retention(t *testing.T) {
+	base := time.Date(2025, 1, 10, 9, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name        string
+		opts        BackupOptions
+		ages        []time.Duration
+		expected    int
+		description string
+	}{
+		{
+			name:        "max_count",
+			opts:        BackupOptions{Enabled: true, MaxCount: 3},
+			ages:        []time.Duration{5 * time.Minute, 4 * time.Minute, 3 * time.Minute, 2 * time.Minute, time.Minute},
+			expected:    3,
+			description: "件数上限を超えた古いバックアップを削除する",
+		},
+		{
+			name:        "max_age",
+			opts:        BackupOptions{Enabled: true, MaxAge: 24 * time.Hour},
+			ages:        []time.Duration{72 * time.Hour, 48 * time.Hour, time.Hour, 0},
+			expected:    2,
+			description: "保持期間を過ぎたバックアップを削除する",
+		},
+		{
+			name:        "keeps_newest",
+			opts:        BackupOptions{Enabled: true, MaxAge: time.Hour},
+			ages:        []time.Duration{72 * time.Hour},
+			expected:    1,
+			description: "最新のバックアップは期限切れでも残す",
+		},
+		{
+			name:        "unlimited",
+			opts:        BackupOptions{Enabled: true},
+			ages:        []time.Duration{72 * time.Hour, 48 * time.Hour, time.Hour},
+			expected:    3,
+			description: "上限なしなら全て残す",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todoPath := filepath.Join(t.TempDir(), "todo.txt")
+			for _, age := range tt.ages {
+				if err := createBackup(todoPath, []byte("Task A\n"), tt.opts, base.Add(-age)); err != nil {
+					t.Fatalf("createBackup failed: %v", err)
+				}
+			}
+			// Retention is relative to the time of the latest save
+			if err := pruneBackups(todoPath, tt.opts, base); err != nil {
+				t.Fatalf("pruneBackups failed: %v", err)
+			}
+
+			backups, err := ListBackups(todoPath, tt.opts)
+			if err != nil {
+				t.Fatalf("ListBackups failed: %v", err)
+			}
+			if len(backups) != tt.expected {
+				t.Errorf("got %d backups, expected %d for %s", len(backups), tt.expected, tt.description)
+			}
+			for i := 1; i < len(backups); i++ {
+				if !backups[i-1].Time.After(backups[i].Time) {
+					t.Errorf("backups are not sorted newest first: %v", backups)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateBackup_SameTimestamp(t *testing.T) {
+	todoPath := filepath.Join(t.TempDir(), "todo.txt")
+	now := time.Date(2025, 1, 10, 9, 0, 0, 0, time.Local)
+	opts := BackupOptions{Enabled: true}
+
+	for _, content := range []string{"first\n", "second\n"} {
+		if err := createBackup(todoPath, []byte(content), opts, now); err != nil {
+			t.Fatalf("createBackup failed: %v", err)
+		}
+	}
+
+	backups, err := ListBackups(todoPath, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("got %d backups, expected 2", len(backups))
+	}
+	if got := testutil.ReadFile(t, backups[0].Path); got != "second\n" {
+		t.Errorf("newest backup content = %q, expected %q", got, "second\n")
+	}
+}
+
+func TestCreateBackup_ManySameTimestamp(t *testing.T) {
+	todoPath := filepath.Join(t.TempDir(), "todo.txt")
+	now := time.Date(2025, 1, 10, 9, 0, 0, 0, time.Local)
+	opts := BackupOptions{Enabled: true, MaxCount: 3}
+
+	// 同じミリ秒の -10 以降も番号順に並び、古いものから削除される
+	for i := range 12 {
+		if err := createBackup(todoPath, []byte(fmt.Sprintf("%d\n", i)), opts, now); err != nil {
+			t.Fatalf("createBackup failed: %v", err)
+		}
+	}
+
+	backups, err := ListBackups(todoPath, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var contents []string
+	for _, backup := range backups {
+		contents = append(contents, testutil.ReadFile(t, backup.Path))
+	}
+	if expected := []string{"11\n", "10\n", "9\n"}; !slices.Equal(contents, expected) {
+		t.Errorf("backup contents = %q, expected %q newest first", contents, expected)
+	}
+}
+
+func TestFindBackup(t *testing.T) {
+	todoPath := filepath.Join(t.TempDir(), "todo.txt")
+	opts := BackupOptions{Enabled: true}
+	times := []time.Time{
+		time.Date(2025, 1, 10, 9, 0, 0, 0, time.Local),
+		time.Date(2025, 1, 10, 9, 30, 0, 0, time.Local),
+		time.Date(2025, 1, 11, 9, 0, 0, 0, time.Local),
+	}
+	for _, created := range times {
+		if err := createBackup(todoPath, []byte("Task A\n"), opts, created); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		id       string
+		expected string
+		wantErr  error
+	}{
+		{name: "exact", id: "20250110-090000.000", expected: "20250110-090000.000"},
+		{name: "unique_prefix", id: "20250111", expected: "20250111-090000.000"},
+		{name: "ambiguous_prefix", id: "20250110"},
+		{name: "not_found", id: "20240101", wantErr: ErrBackupNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backup, err := FindBackup(todoPath, opts, tt.id)
+			if tt.expected == "" {
+				if err == nil {
+					t.Fatalf("FindBackup(%q) expected error, got %v", tt.id, backup)
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Errorf("FindBackup(%q) error = %v, expected %v", tt.id, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FindBackup(%q) failed: %v", tt.id, err)
+			}
+			if backup.ID != tt.expected {
+				t.Errorf("FindBackup(%q) = %s, expected %s", tt.id, backup.ID, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRestoreBackup(t *testing.T) {
+	todoPath := filepath.Join(t.TempDir(), "todo.txt")
+	opts := BackupOptions{Enabled: true}
+	if err := createBackup(todoPath, []byte("Task A\nTask B\n"), opts, time.Now().Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	testutil.WriteExternally(t, todoPath, "Task B\n")
+
+	backups, err := ListBackups(todoPath, opts)
+	if err != nil || len(backups) != 1 {
+		t.Fatalf("ListBackups() = %v, %v", backups, err)
+	}
+
+	restored, err := RestoreBackup(todoPath, opts, backups[0].ID)
+	if err != nil {
+		t.Fatalf("RestoreBackup failed: %v", err)
+	}
+	if restored.ID != backups[0].ID {
+		t.Errorf("restored %s, expected %s", restored.ID, backups[0].ID)
+	}
+	if got := testutil.ReadFile(t, todoPath); got != "Task A\nTask B\n" {
+		t.Errorf("file content = %q", got)
+	}
+
+	// 復元前の内容もバックアップされ、復元を取り消せる
+	backups, err = ListBackups(todoPath, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("got %d backups after restore, expected 2", len(backups))
+	}
+	if got := testutil.ReadFile(t, backups[0].Path); got != "Task B\n" {
+		t.Errorf("pre-restore backup content = %q, expected %q", got, "Task B\n")
+	}
+}
+
+func TestStore_SaveCreatesBackup(t *testing.T) {
+	dir := t.TempDir()
+	todoPath := filepath.Join(dir, "todo.txt")
+	testutil.WriteExternally(t, todoPath, "Task A\n")
+
+	store := NewStore(todoPath)
+	store.SetBackup(BackupOptions{Enabled: true, MaxCount: 5})
+	list, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// 内容が変わらない保存ではバックアップを作らない
+	if _, err := store.Save(list); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if _, err := os.Stat(DefaultBackupDir(todoPath)); !os.IsNotExist(err) {
+		t.Errorf("backup directory should not exist after a no-op save: %v", err)
+	}
+
+	list[0].Completed = true
+	if _, err := store.Save(list); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	backups, err := ListBackups(todoPath, BackupOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 1 {
+		t.Fatalf("got %d backups, expected 1", len(backups))
+	}
+	if filepath.Dir(backups[0].Path) != filepath.Join(dir, ".todotui", "backups") {
+		t.Errorf("backup stored in %s", filepath.Dir(backups[0].Path))
+	}
+	if got := testutil.ReadFile(t, backups[0].Path); got != "Task A\n" {
+		t.Errorf("backup content = %q, expected previous content", got)
+	}
+}
@@ -0,0 +1,47 @@
+package todo
+
+// LineChange is a difference between two versions of a list of lines.
+// Old and New are line indexes in the old and new version; a changed line
+// has both, a removed line has New -1 and an added line has Old -1.
+type LineChange struct {
+	Old int
+	New int
+}
+
+// DiffLines returns the changes that turn a into b.
+// Lines that are not part of the longest common subsequence are paired up in
+// order as changed lines; the lines left over are removed or added.
+func DiffLines(a, b []string) []LineChange {
+	matches := matchLines(a, b)
+
+	var changes []LineChange
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		if i < len(a) && matches[i] == j {
+			i++
+			j++
+			continue
+		}
+
+		// The region up to the next unchanged line
+		nextA := i
+		for nextA < len(a) && matches[nextA] < 0 {
+			nextA++
+		}
+		nextB := len(b)
+		if nextA < len(a) {
+			nextB = matches[nextA]
+		}
+
+		for ; i < nextA && j < nextB; i, j = i+1, j+1 {
+			changes = append(changes, LineChange{Old: i, New: j})
+		}
+		for ; i < nextA; i++ {
+			changes = append(changes, LineChange{Old: i, New: -1})
+		}
+		for ; j < nextB; j++ {
+			changes = append(changes, LineChange{Old: -1, New: j})
+		}
+	}
+	return changes
+}
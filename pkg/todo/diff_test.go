@@ -0,0 +1,61 @@
+package todo
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	base := []string{"Task A", "Task B", "Task C", "Task D"}
+
+	tests := []struct {
+		name     string
+		edited   []string
+		expected []LineChange
+	}{
+		{
+			name:     "no_changes",
+			edited:   base,
+			expected: nil,
+		},
+		{
+			name:     "changed_line",
+			edited:   []string{"Task A", "x Task B", "Task C", "Task D"},
+			expected: []LineChange{{Old: 1, New: 1}},
+		},
+		{
+			name:     "removed_lines",
+			edited:   []string{"Task B", "Task D"},
+			expected: []LineChange{{Old: 0, New: -1}, {Old: 2, New: -1}},
+		},
+		{
+			name:     "added_lines",
+			edited:   []string{"Task A", "Task B", "Task C", "Task D", "Task E"},
+			expected: []LineChange{{Old: -1, New: 4}},
+		},
+		{
+			// 変更と削除が同じ領域にある場合は先頭から順に対応させる
+			name:     "changed_and_removed",
+			edited:   []string{"Task A", "(A) Task B", "Task D"},
+			expected: []LineChange{{Old: 1, New: 1}, {Old: 2, New: -1}},
+		},
+		{
+			name:     "changed_and_added",
+			edited:   []string{"(A) Task A", "Task X", "Task B", "Task C", "Task D"},
+			expected: []LineChange{{Old: 0, New: 0}, {Old: -1, New: 1}},
+		},
+		{
+			name:     "everything_replaced",
+			edited:   []string{"One", "Two"},
+			expected: []LineChange{{Old: 0, New: 0}, {Old: 1, New: 1}, {Old: 2, New: -1}, {Old: 3, New: -1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffLines(base, tt.edited); !slices.Equal(got, tt.expected) {
+				t.Errorf("DiffLines() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
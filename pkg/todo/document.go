@@ -0,0 +1,110 @@
+package todo
+
+import (
+	"strings"
+
+	todotxt "github.com/1set/todotxt"
+	"github.com/yuucu/todotui/pkg/logger"
+)
+
+// document is a todo.txt file kept as its physical lines.
+// Only the lines of tasks that were modified are rewritten on save; blank
+// lines, comments, lines that do not parse as a task and untouched tasks are
+// written back byte-for-byte, in their original order.
+type document struct {
+	lines     []string         // Physical lines without line endings
+	tasks     todotxt.TaskList // Tasks parsed from the lines; ID is the 1-based line
+	formatted map[int]string   // Line -> task as the library formats it
+	newline   string           // Line ending used by the file
+	finalEOL  bool             // Whether the last line ends with a line ending
+}
+
+// parseDocument splits data into lines and parses the tasks among them
+func parseDocument(data []byte) *document {
+	doc := &document{
+		lines:     splitLines(string(data)),
+		tasks:     todotxt.NewTaskList(),
+		formatted: make(map[int]string),
+		newline:   "\n",
+	}
+	if strings.Contains(string(data), "\r\n") {
+		doc.newline = "\r\n"
+	}
+	// New files end with a line ending, as todo.txt tools expect
+	doc.finalEOL = len(data) == 0 || strings.HasSuffix(string(data), "\n")
+
+	for i, line := range doc.lines {
+		text := strings.TrimSpace(line)
+		if text == "" || (todotxt.IgnoreComments && strings.HasPrefix(text, "#")) {
+			continue
+		}
+
+		task, err := todotxt.ParseTask(text)
+		if err != nil {
+			// 解析できない行はタスクとして扱わず、そのまま保持する
+			logger.Debug("Keeping unparsable line as is", "line", i+1, "error", err)
+			continue
+		}
+		task.ID = i + 1
+		doc.tasks = append(doc.tasks, *task)
+		doc.formatted[task.ID] = task.String()
+	}
+	return doc
+}
+
+// parseTaskList parses todo.txt content, giving each task the 1-based line it was read from as its ID
+func parseTaskList(data []byte) todotxt.TaskList {
+	return parseDocument(data).tasks
+}
+
+// render returns the lines of the document with list in place of its tasks.
+// A task is matched to its line by ID: unchanged tasks keep their original
+// text, modified tasks are reformatted in place, tasks missing from list are
+// removed, and tasks that do not belong to a line are appended at the end.
+func (doc *document) render(list todotxt.TaskList) []string {
+	byLine := make(map[int]*todotxt.Task, len(list))
+	var added []*todotxt.Task
+	for i := range list {
+		task := &list[i]
+		if _, ok := doc.formatted[task.ID]; ok && byLine[task.ID] == nil {
+			byLine[task.ID] = task
+		} else {
+			added = append(added, task)
+		}
+	}
+
+	lines := make([]string, 0, len(doc.lines)+len(added))
+	for i, line := range doc.lines {
+		formatted, isTask := doc.formatted[i+1]
+		if !isTask {
+			lines = append(lines, line)
+			continue
+		}
+		task, ok := byLine[i+1]
+		if !ok {
+			continue
+		}
+		if text := task.String(); text != formatted {
+			lines = append(lines, text)
+		} else {
+			lines = append(lines, line)
+		}
+	}
+	for _, task := range added {
+		lines = append(lines, task.String())
+	}
+	return lines
+}
+
+// content joins lines into file content using the document's line ending.
+// The last line ends with one only if it did in the original file.
+func (doc *document) content(lines []string) []byte {
+	if len(lines) == 0 {
+		return nil
+	}
+	content := strings.Join(lines, doc.newline)
+	if doc.finalEOL {
+		content += doc.newline
+	}
+	return []byte(content)
+}
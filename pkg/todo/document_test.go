@@ -0,0 +1,137 @@
+package todo
+
+import (
+	"path/filepath"
+	"testing"
+
+	todotxt "github.com/1set/todotxt"
+	"github.com/yuucu/todotui/internal/testutil"
+)
+
+const handOrdered = `# Work
+(B) Call mom @phone +family
+x 2024-01-02 Old task
+
+# Broken
+Fix bike due:2024-13-45
+   Water plants   @home
+`
+
+func TestDocument_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		modify   func(list todotxt.TaskList) todotxt.TaskList
+		expected string
+	}{
+		{
+			name:     "untouched file is written back byte-for-byte",
+			content:  handOrdered,
+			modify:   func(list todotxt.TaskList) todotxt.TaskList { return list },
+			expected: handOrdered,
+		},
+		{
+			name:    "only the modified task is reformatted",
+			content: handOrdered,
+			modify: func(list todotxt.TaskList) todotxt.TaskList {
+				list[2].Priority = "A"
+				return list
+			},
+			expected: "# Work\n(B) Call mom @phone +family\nx 2024-01-02 Old task\n\n# Broken\nFix bike due:2024-13-45\n(A) Water plants @home\n",
+		},
+		{
+			name:    "removed task drops only its own line",
+			content: handOrdered,
+			modify: func(list todotxt.TaskList) todotxt.TaskList {
+				return append(list[:1:1], list[2:]...)
+			},
+			expected: "# Work\n(B) Call mom @phone +family\n\n# Broken\nFix bike due:2024-13-45\n   Water plants   @home\n",
+		},
+		{
+			name:    "new task is appended",
+			content: handOrdered,
+			modify: func(list todotxt.TaskList) todotxt.TaskList {
+				task, err := todotxt.ParseTask("New task +home")
+				if err != nil {
+					t.Fatal(err)
+				}
+				return append(list, *task)
+			},
+			expected: handOrdered + "New task +home\n",
+		},
+		{
+			name:    "CRLF line endings are kept",
+			content: "# Notes\r\nTask A\r\nTask B\r\n",
+			modify: func(list todotxt.TaskList) todotxt.TaskList {
+				list[1].Completed = true
+				return list
+			},
+			expected: "# Notes\r\nTask A\r\nx Task B\r\n",
+		},
+		{
+			name:     "missing final newline is not added",
+			content:  "Task A\nTask B",
+			modify:   func(list todotxt.TaskList) todotxt.TaskList { return list },
+			expected: "Task A\nTask B",
+		},
+		{
+			name:    "missing final newline stays missing after changes",
+			content: "Task A\r\nTask B",
+			modify: func(list todotxt.TaskList) todotxt.TaskList {
+				list[1].Completed = true
+				task, err := todotxt.ParseTask("Task C")
+				if err != nil {
+					t.Fatal(err)
+				}
+				return append(list, *task)
+			},
+			expected: "Task A\r\nx Task B\r\nTask C",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := parseDocument([]byte(tt.content))
+			list := tt.modify(append(todotxt.TaskList(nil), doc.tasks...))
+			if got := string(doc.content(doc.render(list))); got != tt.expected {
+				t.Errorf("render() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseDocument_SkipsNonTaskLines(t *testing.T) {
+	doc := parseDocument([]byte(handOrdered))
+
+	var lines []int
+	for _, task := range doc.tasks {
+		lines = append(lines, task.ID)
+	}
+	if expected := []int{2, 3, 7}; len(lines) != len(expected) ||
+		lines[0] != expected[0] || lines[1] != expected[1] || lines[2] != expected[2] {
+		t.Errorf("task lines = %v, expected %v", lines, expected)
+	}
+}
+
+func TestStore_SaveKeepsNonTaskLines(t *testing.T) {
+	todoPath := filepath.Join(t.TempDir(), "todo.txt")
+	testutil.WriteExternally(t, todoPath, handOrdered)
+
+	store := NewStore(todoPath)
+	list, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// 別のプログラムが末尾にコメントを追加している間にタスクを完了する
+	testutil.WriteExternally(t, todoPath, handOrdered+"# Added elsewhere\n")
+	list[0].Completed = true
+	if _, err := store.Save(list); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	expected := "# Work\nx (B) Call mom @phone +family\nx 2024-01-02 Old task\n\n# Broken\nFix bike due:2024-13-45\n   Water plants   @home\n# Added elsewhere\n"
+	if got := testutil.ReadFile(t, todoPath); got != expected {
+		t.Errorf("file content = %q, expected %q", got, expected)
+	}
+}
@@ -0,0 +1,262 @@
+package todo
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	todotxt "github.com/1set/todotxt"
+	"github.com/yuucu/todotui/pkg/logger"
+)
+
+// ジャーナルファイルのデフォルト名
+const journalFileName = "journal.jsonl"
+
+// ジャーナルファイルのパーミッション
+const journalFileMode = 0644
+
+// ErrJournalEntryNotFound is returned when no journal entry matches the requested ID
+var ErrJournalEntryNotFound = errors.New("journal entry not found")
+
+// ErrCannotRevert is returned when the task changed by a journal entry is no longer in the file
+var ErrCannotRevert = errors.New("task is no longer in the file")
+
+// JournalKindArchive is the kind of the entries recording tasks moved to done.txt
+const JournalKindArchive = "Archive"
+
+// JournalOptions controls the journal of changes made to a todo file
+type JournalOptions struct {
+	Enabled bool
+	File    string // Empty means DefaultJournalFile of the todo file
+}
+
+// JournalEntry records one change made to a task.
+// An empty Before means the task was added; an empty After means it was removed.
+type JournalEntry struct {
+	ID     int       `json:"-"` // 1-based position in the journal
+	Time   time.Time `json:"time"`
+	User   string    `json:"user"`
+	Host   string    `json:"host"`
+	File   string    `json:"file"`
+	Kind   string    `json:"kind"`
+	Line   int       `json:"line,omitempty"` // Line of the task when the change was made, 0 if unknown
+	Before string    `json:"before,omitempty"`
+	After  string    `json:"after,omitempty"`
+}
+
+// NewJournalEntry creates an entry for a change to todoPath made now by the current user
+func NewJournalEntry(todoPath, kind string, line int, before, after string) JournalEntry {
+	host, _ := os.Hostname()
+	return JournalEntry{
+		Time:   time.Now(),
+		User:   currentUser(),
+		Host:   host,
+		File:   journalPath(todoPath),
+		Kind:   kind,
+		Line:   line,
+		Before: before,
+		After:  after,
+	}
+}
+
+// NewArchiveEntries creates the entries recording tasks moved from todoPath to done.txt
+func NewArchiveEntries(todoPath string, archived todotxt.TaskList) []JournalEntry {
+	entries := make([]JournalEntry, 0, len(archived))
+	for i := range archived {
+		entries = append(entries, NewJournalEntry(todoPath, JournalKindArchive, archived[i].ID, archived[i].String(), ""))
+	}
+	return entries
+}
+
+// journalPath returns todoPath as recorded in the File of journal entries
+func journalPath(todoPath string) string {
+	if absPath, err := filepath.Abs(todoPath); err == nil {
+		return absPath
+	}
+	return todoPath
+}
+
+// currentUser returns the name of the user running the program
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// DefaultJournalFile returns the journal file used when none is configured
+func DefaultJournalFile(todoPath string) string {
+	return filepath.Join(filepath.Dir(todoPath), ".todotui", journalFileName)
+}
+
+// journalFile returns the journal file of todoPath
+func (o JournalOptions) journalFile(todoPath string) string {
+	if o.File != "" {
+		return o.File
+	}
+	return DefaultJournalFile(todoPath)
+}
+
+// AppendJournal appends entries to the journal of todoPath.
+// The journal is append-only; each entry is written as one JSON line with a
+// single write, so entries from several instances do not interleave.
+func AppendJournal(todoPath string, opts JournalOptions, entries ...JournalEntry) error {
+	if !opts.Enabled || len(entries) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	path := opts.journalFile(todoPath)
+	if err := os.MkdirAll(filepath.Dir(path), defaultDirMode); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, journalFileMode)
+	if err != nil {
+		return err
+	}
+	if _, writeErr := file.Write(buf.Bytes()); writeErr != nil {
+		file.Close()
+		return writeErr
+	}
+	return file.Close()
+}
+
+// ReadJournal returns the entries for todoPath in its journal, oldest first.
+// A journal may be shared by several todo files, so entries for other files
+// are left out; IDs are still positions in the whole journal.
+// A missing journal has no entries.
+func ReadJournal(todoPath string, opts JournalOptions) ([]JournalEntry, error) {
+	data, err := os.ReadFile(opts.journalFile(todoPath))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	file := journalPath(todoPath)
+	var entries []JournalEntry
+	lineNumber := 0
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		// IDs are line numbers, so they stay the same as the journal grows
+		lineNumber++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var entry JournalEntry
+		if jsonErr := json.Unmarshal(scanner.Bytes(), &entry); jsonErr != nil {
+			logger.Warn("Skipping malformed journal entry", "line", lineNumber, "error", jsonErr)
+			continue
+		}
+		if entry.File != file {
+			continue
+		}
+		entry.ID = lineNumber
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// FindJournalEntry returns the entry with the given ID
+func FindJournalEntry(entries []JournalEntry, id string) (JournalEntry, error) {
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return JournalEntry{}, fmt.Errorf("invalid journal entry ID %q", id)
+	}
+	for _, entry := range entries {
+		if entry.ID == n {
+			return entry, nil
+		}
+	}
+	return JournalEntry{}, fmt.Errorf("%w: %s", ErrJournalEntryNotFound, id)
+}
+
+// Revert applies the inverse of entry to the file: the task it changed is
+// put back as it was before. The task is looked up by its text, preferring
+// the one closest to the line recorded in the entry.
+// ErrCannotRevert is returned for entries of other todo files sharing the
+// journal, and for archived tasks, since adding them back would leave them
+// in done.txt as well.
+// It returns the entry describing the revert itself.
+func (s *Store) Revert(entry JournalEntry) (JournalEntry, error) {
+	if entry.File != "" && entry.File != journalPath(s.path) {
+		return JournalEntry{}, fmt.Errorf("%w: the entry is for %s", ErrCannotRevert, entry.File)
+	}
+	if entry.Kind == JournalKindArchive {
+		return JournalEntry{}, fmt.Errorf("%w: %q was archived to done.txt; move it back from there instead", ErrCannotRevert, entry.Before)
+	}
+
+	list, err := s.Load()
+	if err != nil {
+		return JournalEntry{}, err
+	}
+
+	line := entry.Line
+	if entry.After == "" {
+		// The task was removed; add it back
+		task, parseErr := todotxt.ParseTask(entry.Before)
+		if parseErr != nil {
+			return JournalEntry{}, parseErr
+		}
+		list = append(list, *task)
+		line = 0
+	} else {
+		index := findTaskText(list, entry.After, entry.Line)
+		if index < 0 {
+			return JournalEntry{}, fmt.Errorf("%w: %q", ErrCannotRevert, entry.After)
+		}
+		line = list[index].ID
+		if entry.Before == "" {
+			// The task was added; remove it
+			list = append(list[:index:index], list[index+1:]...)
+		} else {
+			task, parseErr := todotxt.ParseTask(entry.Before)
+			if parseErr != nil {
+				return JournalEntry{}, parseErr
+			}
+			task.ID = line
+			list[index] = *task
+		}
+	}
+
+	if _, err := s.Save(list); err != nil {
+		return JournalEntry{}, err
+	}
+	return NewJournalEntry(s.path, "Revert", line, entry.After, entry.Before), nil
+}
+
+// findTaskText returns the index of the task whose text is text closest to line, or -1
+func findTaskText(list todotxt.TaskList, text string, line int) int {
+	best, bestDistance := -1, 0
+	for i := range list {
+		if list[i].String() != text {
+			continue
+		}
+		distance := list[i].ID - line
+		if distance < 0 {
+			distance = -distance
+		}
+		if best < 0 || distance < bestDistance {
+			best, bestDistance = i, distance
+		}
+	}
+	return best
+}
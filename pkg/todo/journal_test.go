@@ -0,0 +1,168 @@
+package todo
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/yuucu/todotui/internal/testutil"
+)
+
+func TestJournal_AppendAndRead(t *testing.T) {
+	todoPath := filepath.Join(t.TempDir(), "todo.txt")
+	opts := JournalOptions{Enabled: true}
+
+	first := NewJournalEntry(todoPath, "Complete", 1, "Task A", "x Task A")
+	second := NewJournalEntry(todoPath, "Add", 0, "", "Task B")
+	if err := AppendJournal(todoPath, opts, first); err != nil {
+		t.Fatalf("AppendJournal failed: %v", err)
+	}
+	if err := AppendJournal(todoPath, opts, second); err != nil {
+		t.Fatalf("AppendJournal failed: %v", err)
+	}
+
+	entries, err := ReadJournal(todoPath, opts)
+	if err != nil {
+		t.Fatalf("ReadJournal failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("ReadJournal returned %d entries, expected 2", len(entries))
+	}
+	if entries[0].ID != 1 || entries[0].Kind != "Complete" || entries[0].Before != "Task A" || entries[0].After != "x Task A" {
+		t.Errorf("first entry = %+v", entries[0])
+	}
+	if entries[1].ID != 2 || entries[1].Before != "" || entries[1].After != "Task B" {
+		t.Errorf("second entry = %+v", entries[1])
+	}
+	if !filepath.IsAbs(entries[0].File) {
+		t.Errorf("entry file = %q, expected an absolute path", entries[0].File)
+	}
+
+	found, err := FindJournalEntry(entries, "2")
+	if err != nil || found.After != "Task B" {
+		t.Errorf("FindJournalEntry(2) = %+v, %v", found, err)
+	}
+	if _, err := FindJournalEntry(entries, "3"); !errors.Is(err, ErrJournalEntryNotFound) {
+		t.Errorf("FindJournalEntry(3) error = %v, expected ErrJournalEntryNotFound", err)
+	}
+}
+
+func TestJournal_SharedByTodoFiles(t *testing.T) {
+	dir := t.TempDir()
+	opts := JournalOptions{Enabled: true, File: filepath.Join(dir, "journal.jsonl")}
+	work := filepath.Join(dir, "work.txt")
+	home := filepath.Join(dir, "home.txt")
+	testutil.WriteExternally(t, work, "Task A\n")
+	testutil.WriteExternally(t, home, "Task B\n")
+
+	if err := AppendJournal(work, opts, NewJournalEntry(work, "Add", 1, "", "Task A")); err != nil {
+		t.Fatal(err)
+	}
+	if err := AppendJournal(home, opts, NewJournalEntry(home, "Archive", 2, "x Task C", "")); err != nil {
+		t.Fatal(err)
+	}
+	if err := AppendJournal(home, opts, NewJournalEntry(home, "Revert", 2, "Task C", "")); err != nil {
+		t.Fatal(err)
+	}
+
+	// 共有されたジャーナルからは自分のファイルのエントリだけを読む
+	entries, err := ReadJournal(home, opts)
+	if err != nil {
+		t.Fatalf("ReadJournal failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != 2 || entries[1].ID != 3 {
+		t.Fatalf("ReadJournal(home) = %+v, expected entries 2 and 3", entries)
+	}
+	if _, err := FindJournalEntry(entries, "1"); !errors.Is(err, ErrJournalEntryNotFound) {
+		t.Errorf("FindJournalEntry(1) error = %v, expected the entry of the other file to be left out", err)
+	}
+
+	// 別のファイルのエントリは元に戻さない
+	if _, err := NewStore(work).Revert(entries[1]); !errors.Is(err, ErrCannotRevert) {
+		t.Errorf("Revert() error = %v, expected ErrCannotRevert", err)
+	}
+	if got := testutil.ReadFile(t, work); got != "Task A\n" {
+		t.Errorf("file content = %q, expected the other todo file to be left alone", got)
+	}
+}
+
+func TestJournal_Disabled(t *testing.T) {
+	todoPath := filepath.Join(t.TempDir(), "todo.txt")
+	entry := NewJournalEntry(todoPath, "Add", 0, "", "Task A")
+
+	if err := AppendJournal(todoPath, JournalOptions{}, entry); err != nil {
+		t.Fatalf("AppendJournal failed: %v", err)
+	}
+	if _, err := os.Stat(DefaultJournalFile(todoPath)); !os.IsNotExist(err) {
+		t.Errorf("journal file was written while disabled: %v", err)
+	}
+}
+
+func TestStore_Revert(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		entry    JournalEntry
+		expected string
+		err      error
+	}{
+		{
+			name:     "modified task is put back",
+			content:  "Task A\nx Task B\n",
+			entry:    JournalEntry{Kind: "Complete", Line: 2, Before: "Task B", After: "x Task B"},
+			expected: "Task A\nTask B\n",
+		},
+		{
+			name:     "added task is removed",
+			content:  "Task A\nTask B\n",
+			entry:    JournalEntry{Kind: "Add", Before: "", After: "Task B"},
+			expected: "Task A\n",
+		},
+		{
+			name:     "removed task is added back",
+			content:  "Task A\n",
+			entry:    JournalEntry{Kind: "Revert", Line: 2, Before: "Task B", After: ""},
+			expected: "Task A\nTask B\n",
+		},
+		{
+			// done.txt にも残ってしまうので、アーカイブは元に戻さない
+			name:     "archived task cannot be reverted",
+			content:  "Task A\n",
+			entry:    JournalEntry{Kind: JournalKindArchive, Line: 2, Before: "x Task B", After: ""},
+			expected: "Task A\n",
+			err:      ErrCannotRevert,
+		},
+		{
+			name:     "duplicate closest to the recorded line is reverted",
+			content:  "# header\n(A) Task A\nTask B\n(A) Task A\n",
+			entry:    JournalEntry{Kind: "Change priority", Line: 4, Before: "Task A", After: "(A) Task A"},
+			expected: "# header\n(A) Task A\nTask B\nTask A\n",
+		},
+		{
+			name:     "task changed since cannot be reverted",
+			content:  "Task A changed\n",
+			entry:    JournalEntry{Kind: "Edit", Line: 1, Before: "Task A", After: "Task A edited"},
+			expected: "Task A changed\n",
+			err:      ErrCannotRevert,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			todoPath := filepath.Join(t.TempDir(), "todo.txt")
+			testutil.WriteExternally(t, todoPath, tt.content)
+
+			reverted, err := NewStore(todoPath).Revert(tt.entry)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Revert() error = %v, expected %v", err, tt.err)
+			}
+			if got := testutil.ReadFile(t, todoPath); got != tt.expected {
+				t.Errorf("file content = %q, expected %q", got, tt.expected)
+			}
+			if tt.err == nil && (reverted.Before != tt.entry.After || reverted.After != tt.entry.Before) {
+				t.Errorf("revert entry = %+v, expected the inverse of %+v", reverted, tt.entry)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package todo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/yuucu/todotui/pkg/logger"
+)
+
+// DefaultLockTimeout is how long Load and Save wait for another process to
+// release the lock before giving up
+const DefaultLockTimeout = 2 * time.Second
+
+// ロック取得を再試行する間隔
+const lockRetryInterval = 50 * time.Millisecond
+
+// ロックファイルのパーミッション
+const lockFileMode = 0644
+
+// ErrLocked is wrapped by *LockError when another process holds the lock
+var ErrLocked = errors.New("todo file is locked by another process")
+
+// LockHolder identifies the process holding the lock on a todo file
+type LockHolder struct {
+	PID   int       `json:"pid"`
+	Host  string    `json:"host"`
+	Since time.Time `json:"since"`
+}
+
+// String returns a short human readable description of the holder
+func (h *LockHolder) String() string {
+	return fmt.Sprintf("pid %d on %s since %s", h.PID, h.Host, h.Since.Format(time.TimeOnly))
+}
+
+// isCurrentProcess reports whether the holder is this process
+func (h *LockHolder) isCurrentProcess() bool {
+	host, _ := os.Hostname()
+	return h.PID == os.Getpid() && h.Host == host
+}
+
+// LockError is returned when the lock could not be acquired in time
+type LockError struct {
+	Path   string
+	Holder *LockHolder // nil if the holder could not be determined
+}
+
+func (e *LockError) Error() string {
+	if e.Holder == nil {
+		return fmt.Sprintf("%s is locked by another process", filepath.Base(e.Path))
+	}
+	return fmt.Sprintf("%s is locked by %s", filepath.Base(e.Path), e.Holder)
+}
+
+func (e *LockError) Unwrap() error {
+	return ErrLocked
+}
+
+// lockFilePath returns the path of the lock file guarding path
+func lockFilePath(path string) string {
+	return filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".lock")
+}
+
+// withLock runs fn while holding the advisory lock for the todo file at path
+func withLock(path string, timeout time.Duration, fn func() error) error {
+	lock, err := acquireLock(path, timeout)
+	if err != nil {
+		return err
+	}
+	defer lock.release()
+
+	return fn()
+}
+
+// acquireLock acquires the advisory lock for path, polling until timeout.
+// The lock is keyed on the symlink target so that every way of reaching the
+// same file shares one lock.
+func acquireLock(path string, timeout time.Duration) (*fileLock, error) {
+	target, err := resolveSymlinks(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), defaultDirMode); err != nil {
+		return nil, err
+	}
+	lockPath := lockFilePath(target)
+
+	deadline := time.Now().Add(timeout)
+	for {
+		lock, err := tryLock(lockPath)
+		if err == nil {
+			lock.writeHolder()
+			return lock, nil
+		}
+		if !errors.Is(err, ErrLocked) {
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			return nil, &LockError{Path: path, Holder: readLockHolder(lockPath)}
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
+
+// writeHolder records this process as the lock holder.
+// A leftover holder from a process that died while holding the lock is
+// reported and replaced.
+func (l *fileLock) writeHolder() {
+	if previous := readLockHolder(l.path); previous != nil && !previous.isCurrentProcess() {
+		logger.Warn("Recovered stale lock", "lock_file", l.path, "holder", previous.String())
+	}
+
+	host, _ := os.Hostname()
+	holder := LockHolder{PID: os.Getpid(), Host: host, Since: time.Now()}
+	data, err := json.Marshal(holder)
+	if err != nil {
+		return
+	}
+	if err := os.WriteFile(l.path, data, lockFileMode); err != nil {
+		logger.Debug("Failed to write lock holder", "lock_file", l.path, "error", err)
+	}
+}
+
+// readLockHolder reads the holder recorded in the lock file, if any
+func readLockHolder(lockPath string) *LockHolder {
+	data, err := os.ReadFile(lockPath)
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+	var holder LockHolder
+	if err := json.Unmarshal(data, &holder); err != nil {
+		return nil
+	}
+	return &holder
+}
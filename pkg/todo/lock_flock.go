@@ -0,0 +1,44 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package todo
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// fileLock is an flock(2) based advisory lock on a lock file
+type fileLock struct {
+	file *os.File
+	path string
+}
+
+// tryLock attempts to take the lock without blocking.
+// The kernel drops an flock when its holder exits, so a crashed instance can
+// never leave the file locked; only its holder record is left behind.
+func tryLock(lockPath string) (*fileLock, error) {
+	file, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE, lockFileMode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		file.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, ErrLocked
+		}
+		return nil, err
+	}
+
+	return &fileLock{file: file, path: lockPath}, nil
+}
+
+// release clears the holder record and drops the lock.
+// The lock file itself is kept, since removing it would race with other
+// processes that already opened it.
+func (l *fileLock) release() {
+	_ = l.file.Truncate(0)
+	_ = syscall.Flock(int(l.file.Fd()), syscall.LOCK_UN)
+	l.file.Close()
+}
@@ -0,0 +1,45 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package todo
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// ロックファイルが残っていても古いとみなすまでの時間
+const staleLockAge = 5 * time.Minute
+
+// fileLock is a lock represented by the exclusive creation of a lock file,
+// used on platforms without flock(2)
+type fileLock struct {
+	path string
+}
+
+// tryLock attempts to create the lock file without blocking.
+// Without flock a crashed instance leaves the lock file behind, so a holder
+// record older than staleLockAge is treated as stale and removed.
+func tryLock(lockPath string) (*fileLock, error) {
+	file, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE|os.O_EXCL, lockFileMode)
+	if err == nil {
+		file.Close()
+		return &fileLock{path: lockPath}, nil
+	}
+	if !errors.Is(err, os.ErrExist) {
+		return nil, err
+	}
+
+	if holder := readLockHolder(lockPath); holder == nil || time.Since(holder.Since) > staleLockAge {
+		info, statErr := os.Stat(lockPath)
+		if statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+			_ = os.Remove(lockPath)
+		}
+	}
+	return nil, ErrLocked
+}
+
+// release removes the lock file
+func (l *fileLock) release() {
+	_ = os.Remove(l.path)
+}
@@ -0,0 +1,112 @@
+package todo
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/yuucu/todotui/internal/testutil"
+)
+
+func TestAcquireLock_Contention(t *testing.T) {
+	todoPath := filepath.Join(t.TempDir(), "todo.txt")
+
+	first, err := acquireLock(todoPath, time.Second)
+	if err != nil {
+		t.Fatalf("first acquireLock failed: %v", err)
+	}
+
+	_, err = acquireLock(todoPath, 100*time.Millisecond)
+	var lockErr *LockError
+	if !errors.As(err, &lockErr) {
+		t.Fatalf("second acquireLock error = %v, expected *LockError", err)
+	}
+	if !errors.Is(err, ErrLocked) {
+		t.Error("LockError should wrap ErrLocked")
+	}
+	if lockErr.Holder == nil || lockErr.Holder.PID != os.Getpid() {
+		t.Errorf("lock holder = %+v, expected pid %d", lockErr.Holder, os.Getpid())
+	}
+
+	first.release()
+
+	second, err := acquireLock(todoPath, time.Second)
+	if err != nil {
+		t.Fatalf("acquireLock after release failed: %v", err)
+	}
+	second.release()
+}
+
+func TestAcquireLock_StaleHolder(t *testing.T) {
+	dir := t.TempDir()
+	todoPath := filepath.Join(dir, "todo.txt")
+
+	// 異常終了したプロセスが残したロック情報を再現する
+	stale := LockHolder{PID: 999999, Host: "crashed-host", Since: time.Now().Add(-time.Hour)}
+	data, err := json.Marshal(stale)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lockPath := lockFilePath(todoPath)
+	if err := os.WriteFile(lockPath, data, lockFileMode); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(lockPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err := acquireLock(todoPath, time.Second)
+	if err != nil {
+		t.Fatalf("acquireLock with stale holder failed: %v", err)
+	}
+	defer lock.release()
+
+	holder := readLockHolder(lockPath)
+	if holder == nil || !holder.isCurrentProcess() {
+		t.Errorf("lock holder = %+v, expected current process", holder)
+	}
+}
+
+func TestStore_SaveWhileLocked(t *testing.T) {
+	todoPath := filepath.Join(t.TempDir(), "todo.txt")
+	testutil.WriteExternally(t, todoPath, "Task A\n")
+
+	store := NewStore(todoPath)
+	store.SetLockTimeout(100 * time.Millisecond)
+	list, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// 別インスタンスがロックを保持している状態を再現する
+	other, err := acquireLock(todoPath, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list[0].Completed = true
+	_, err = store.Save(list)
+	if !errors.Is(err, ErrLocked) {
+		t.Fatalf("Save() error = %v, expected ErrLocked", err)
+	}
+	if got := testutil.ReadFile(t, todoPath); got != "Task A\n" {
+		t.Errorf("file was modified while locked: %q", got)
+	}
+
+	if _, err := store.Load(); !errors.Is(err, ErrLocked) {
+		t.Errorf("Load() error = %v, expected ErrLocked", err)
+	}
+
+	other.release()
+
+	if _, err := store.Save(list); err != nil {
+		t.Fatalf("Save after release failed: %v", err)
+	}
+	if got := testutil.ReadFile(t, todoPath); got != "x Task A\n" {
+		t.Errorf("file content = %q", got)
+	}
+}
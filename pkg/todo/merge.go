@@ -0,0 +1,230 @@
+package todo
+
+import (
+	"fmt"
+	"slices"
+)
+
+// LCSテーブルのセル数上限（これを超える場合は差分領域全体を置換として扱う）
+const maxLCSCells = 4_000_000
+
+// Resolution decides how conflicting changes are combined during a merge
+type Resolution int
+
+const (
+	// ResolveNone reports conflicts as a *ConflictError
+	ResolveNone Resolution = iota
+	// ResolveOurs keeps the in-memory version of conflicting lines
+	ResolveOurs
+	// ResolveTheirs keeps the on-disk version of conflicting lines
+	ResolveTheirs
+	// ResolveBoth keeps the on-disk version followed by the in-memory version
+	ResolveBoth
+)
+
+// Conflict is a region that was changed differently in memory and on disk
+type Conflict struct {
+	Base   []string // Lines as of the last load
+	Ours   []string // Lines as they are in memory
+	Theirs []string // Lines as they are on disk now
+}
+
+// ConflictError is returned by Store.Save when the file was changed by another
+// program and the changes cannot be merged automatically
+type ConflictError struct {
+	Path      string
+	Conflicts []Conflict
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s was modified externally: %d conflicting change(s)", e.Path, len(e.Conflicts))
+}
+
+// matchLines computes a longest common subsequence of a and b.
+// The result has one entry per line of a holding the index of the matching
+// line in b, or -1 if the line has no counterpart.
+func matchLines(a, b []string) []int {
+	matches := make([]int, len(a))
+	for i := range matches {
+		matches[i] = -1
+	}
+
+	// Common prefix and suffix are matched directly, keeping the table small
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		matches[prefix] = prefix
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		matches[len(a)-1-suffix] = len(b) - 1 - suffix
+		suffix++
+	}
+
+	midA := a[prefix : len(a)-suffix]
+	midB := b[prefix : len(b)-suffix]
+	n, m := len(midA), len(midB)
+	if n == 0 || m == 0 || (n+1)*(m+1) > maxLCSCells {
+		return matches
+	}
+
+	// lengths[i][j] is the LCS length of midA[i:] and midB[j:]
+	lengths := make([][]int32, n+1)
+	for i := range lengths {
+		lengths[i] = make([]int32, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	for i, j := 0, 0; i < n && j < m; {
+		switch {
+		case midA[i] == midB[j]:
+			matches[prefix+i] = prefix + j
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+
+	return matches
+}
+
+// hunk is a range of base lines replaced by one side of a merge.
+// An empty range is an insertion before base line start.
+type hunk struct {
+	start, end int      // Range of base lines replaced
+	lines      []string // Lines in their place
+	ours       bool     // Whether the hunk comes from ours rather than theirs
+}
+
+// hunks returns the changes that turn base into side, in base order.
+// matches is the result of matchLines(base, side).
+func hunks(base, side []string, matches []int, ours bool) []hunk {
+	var result []hunk
+	for i, j := 0, 0; i < len(base) || j < len(side); {
+		if i < len(base) && matches[i] == j {
+			i, j = i+1, j+1
+			continue
+		}
+		start := i
+		for i < len(base) && matches[i] < 0 {
+			i++
+		}
+		end := len(side)
+		if i < len(base) {
+			end = matches[i]
+		}
+		result = append(result, hunk{start: start, end: i, lines: side[j:end], ours: ours})
+		j = end
+	}
+	return result
+}
+
+// overlaps reports whether h touches the base lines in [start, end).
+// Insertions at the same place overlap each other, but an insertion next to
+// a changed range does not overlap it.
+func (h hunk) overlaps(start, end int) bool {
+	if h.start == h.end && start == end {
+		return h.start == start
+	}
+	return h.start < end && start < h.end
+}
+
+// apply returns base[start:end] with the hunks of one side applied
+func apply(base []string, start, end int, hunks []hunk) []string {
+	var lines []string
+	for _, h := range hunks {
+		lines = append(lines, base[start:h.start]...)
+		lines = append(lines, h.lines...)
+		start = h.end
+	}
+	return append(lines, base[start:end]...)
+}
+
+// merge3 performs a line-level three-way merge of ours and theirs, both of
+// which were derived from base.
+// Every line is a task of its own, so changes are merged line by line:
+// lines changed on only one side take that side's version, even next to
+// lines changed on the other side, and tasks added by both sides at the same
+// place are all kept. Only base lines changed or deleted on both sides are a
+// conflict, combined according to resolution and always reported.
+func merge3(base, ours, theirs []string, resolution Resolution) ([]string, []Conflict) {
+	changes := append(hunks(base, ours, matchLines(base, ours), true),
+		hunks(base, theirs, matchLines(base, theirs), false)...)
+	// Insertions come before a change starting at the same line
+	slices.SortStableFunc(changes, func(a, b hunk) int {
+		if a.start != b.start {
+			return a.start - b.start
+		}
+		return a.end - b.end
+	})
+
+	var merged []string
+	var conflicts []Conflict
+	pos := 0
+	for len(changes) > 0 {
+		// Group the changes touching the same base lines
+		start, end := changes[0].start, changes[0].end
+		n := 1
+		for n < len(changes) && changes[n].overlaps(start, end) {
+			end = max(end, changes[n].end)
+			n++
+		}
+		group := changes[:n]
+		changes = changes[n:]
+
+		var oursHunks, theirsHunks []hunk
+		for _, h := range group {
+			if h.ours {
+				oursHunks = append(oursHunks, h)
+			} else {
+				theirsHunks = append(theirsHunks, h)
+			}
+		}
+		baseChunk := base[start:end]
+		oursChunk := apply(base, start, end, oursHunks)
+		theirsChunk := apply(base, start, end, theirsHunks)
+
+		merged = append(merged, base[pos:start]...)
+		pos = end
+		switch {
+		case len(theirsHunks) == 0, slices.Equal(oursChunk, theirsChunk):
+			merged = append(merged, oursChunk...)
+		case len(oursHunks) == 0:
+			merged = append(merged, theirsChunk...)
+		case len(baseChunk) == 0:
+			// Both sides appended different tasks at the same place
+			merged = append(merged, theirsChunk...)
+			merged = append(merged, oursChunk...)
+		default:
+			conflicts = append(conflicts, Conflict{
+				Base:   slices.Clone(baseChunk),
+				Ours:   slices.Clone(oursChunk),
+				Theirs: slices.Clone(theirsChunk),
+			})
+			switch resolution {
+			case ResolveOurs:
+				merged = append(merged, oursChunk...)
+			case ResolveTheirs:
+				merged = append(merged, theirsChunk...)
+			case ResolveBoth, ResolveNone:
+				merged = append(merged, theirsChunk...)
+				merged = append(merged, oursChunk...)
+			}
+		}
+	}
+	merged = append(merged, base[pos:]...)
+
+	return merged, conflicts
+}
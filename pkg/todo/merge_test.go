@@ -0,0 +1,212 @@
+package todo
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMerge3(t *testing.T) {
+	base := []string{"Task A", "Task B", "Task C", "Task D"}
+
+	tests := []struct {
+		name              string
+		ours              []string
+		theirs            []string
+		resolution        Resolution
+		expected          []string
+		expectedConflicts int
+		description       string
+	}{
+		{
+			name:              "no_changes",
+			ours:              base,
+			theirs:            base,
+			expected:          base,
+			expectedConflicts: 0,
+			description:       "どちらも変更していない場合はそのまま",
+		},
+		{
+			name:              "only_ours_changed",
+			ours:              []string{"Task A", "x Task B", "Task C", "Task D"},
+			theirs:            base,
+			expected:          []string{"Task A", "x Task B", "Task C", "Task D"},
+			expectedConflicts: 0,
+			description:       "メモリ側のみの変更が反映される",
+		},
+		{
+			name:              "only_theirs_changed",
+			ours:              base,
+			theirs:            []string{"Task A", "Task B", "Task C"},
+			expected:          []string{"Task A", "Task B", "Task C"},
+			expectedConflicts: 0,
+			description:       "ディスク側のみの変更が反映される",
+		},
+		{
+			name:              "independent_changes",
+			ours:              []string{"x Task A", "Task B", "Task C", "Task D"},
+			theirs:            []string{"Task A", "Task B", "Task C", "(A) Task D"},
+			expected:          []string{"x Task A", "Task B", "Task C", "(A) Task D"},
+			expectedConflicts: 0,
+			description:       "離れた行の変更は両方とも反映される",
+		},
+		{
+			name:              "both_appended",
+			ours:              []string{"Task A", "Task B", "Task C", "Task D", "Ours new"},
+			theirs:            []string{"Task A", "Task B", "Task C", "Task D", "Theirs new"},
+			expected:          []string{"Task A", "Task B", "Task C", "Task D", "Theirs new", "Ours new"},
+			expectedConflicts: 0,
+			description:       "両方で追加されたタスクは両方残る",
+		},
+		{
+			name:              "adjacent_changes",
+			ours:              []string{"x Task A", "Task B", "Task C", "Task D"},
+			theirs:            []string{"Task A", "(A) Task B", "Task C", "Task D"},
+			expected:          []string{"x Task A", "(A) Task B", "Task C", "Task D"},
+			expectedConflicts: 0,
+			description:       "隣り合う行の変更は両方とも反映される",
+		},
+		{
+			name:              "ours_appended_theirs_changed_last",
+			ours:              []string{"Task A", "Task B", "Task C", "Task D", "Ours new"},
+			theirs:            []string{"Task A", "Task B", "Task C", "x Task D"},
+			expected:          []string{"Task A", "Task B", "Task C", "x Task D", "Ours new"},
+			expectedConflicts: 0,
+			description:       "末尾への追加と最後の行の変更は両方とも反映される",
+		},
+		{
+			name:              "ours_changed_last_theirs_appended",
+			ours:              []string{"Task A", "Task B", "Task C", "x Task D"},
+			theirs:            []string{"Task A", "Task B", "Task C", "Task D", "Theirs new"},
+			expected:          []string{"Task A", "Task B", "Task C", "x Task D", "Theirs new"},
+			expectedConflicts: 0,
+			description:       "最後の行の変更と末尾への追加は両方とも反映される",
+		},
+		{
+			name:              "edit_vs_adjacent_delete",
+			ours:              []string{"Task A", "Task B edited", "Task C", "Task D"},
+			theirs:            []string{"Task A", "Task B", "Task D"},
+			expected:          []string{"Task A", "Task B edited", "Task D"},
+			expectedConflicts: 0,
+			description:       "編集と隣の行の削除は両方反映される",
+		},
+		{
+			name:              "same_change_both_sides",
+			ours:              []string{"Task A", "x Task B", "Task C", "Task D"},
+			theirs:            []string{"Task A", "x Task B", "Task C", "Task D"},
+			expected:          []string{"Task A", "x Task B", "Task C", "Task D"},
+			expectedConflicts: 0,
+			description:       "同じ変更は一度だけ反映される",
+		},
+		{
+			name:              "ours_edit_theirs_delete_elsewhere",
+			ours:              []string{"Task A", "Task B edited", "Task C", "Task D"},
+			theirs:            []string{"Task A", "Task B", "Task C"},
+			expected:          []string{"Task A", "Task B edited", "Task C"},
+			expectedConflicts: 0,
+			description:       "編集と別行の削除は両方反映される",
+		},
+		{
+			name:              "conflict_reported",
+			ours:              []string{"Task A", "x Task B", "Task C", "Task D"},
+			theirs:            []string{"Task A", "(A) Task B", "Task C", "Task D"},
+			resolution:        ResolveNone,
+			expected:          []string{"Task A", "(A) Task B", "x Task B", "Task C", "Task D"},
+			expectedConflicts: 1,
+			description:       "同じ行の異なる変更は競合として報告される",
+		},
+		{
+			name:              "conflict_resolved_ours",
+			ours:              []string{"Task A", "x Task B", "Task C", "Task D"},
+			theirs:            []string{"Task A", "(A) Task B", "Task C", "Task D"},
+			resolution:        ResolveOurs,
+			expected:          []string{"Task A", "x Task B", "Task C", "Task D"},
+			expectedConflicts: 1,
+			description:       "競合時にメモリ側を採用する",
+		},
+		{
+			name:              "conflict_resolved_theirs",
+			ours:              []string{"Task A", "x Task B", "Task C", "Task D"},
+			theirs:            []string{"Task A", "(A) Task B", "Task C", "Task D"},
+			resolution:        ResolveTheirs,
+			expected:          []string{"Task A", "(A) Task B", "Task C", "Task D"},
+			expectedConflicts: 1,
+			description:       "競合時にディスク側を採用する",
+		},
+		{
+			name:              "edit_vs_delete_conflict",
+			ours:              []string{"Task A", "Task B", "Task C edited", "Task D"},
+			theirs:            []string{"Task A", "Task B", "Task D"},
+			resolution:        ResolveNone,
+			expected:          []string{"Task A", "Task B", "Task C edited", "Task D"},
+			expectedConflicts: 1,
+			description:       "編集された行が外部で削除された場合は競合になる",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, conflicts := merge3(base, tt.ours, tt.theirs, tt.resolution)
+			if !slices.Equal(merged, tt.expected) {
+				t.Errorf("merge3() = %v, expected %v for %s", merged, tt.expected, tt.description)
+			}
+			if len(conflicts) != tt.expectedConflicts {
+				t.Errorf("merge3() conflicts = %d, expected %d for %s", len(conflicts), tt.expectedConflicts, tt.description)
+			}
+		})
+	}
+}
+
+func TestMatchLines(t *testing.T) {
+	tests := []struct {
+		name        string
+		a           []string
+		b           []string
+		expected    []int
+		description string
+	}{
+		{
+			name:        "identical",
+			a:           []string{"a", "b", "c"},
+			b:           []string{"a", "b", "c"},
+			expected:    []int{0, 1, 2},
+			description: "同一の行列はすべて対応する",
+		},
+		{
+			name:        "insertion",
+			a:           []string{"a", "c"},
+			b:           []string{"a", "b", "c"},
+			expected:    []int{0, 2},
+			description: "挿入された行は対応を持たない",
+		},
+		{
+			name:        "deletion",
+			a:           []string{"a", "b", "c"},
+			b:           []string{"a", "c"},
+			expected:    []int{0, -1, 1},
+			description: "削除された行は-1になる",
+		},
+		{
+			name:        "duplicate_lines",
+			a:           []string{"x", "y", "x"},
+			b:           []string{"y", "x"},
+			expected:    []int{-1, 0, 1},
+			description: "重複行があっても順序を保って対応する",
+		},
+		{
+			name:        "empty_b",
+			a:           []string{"a"},
+			b:           nil,
+			expected:    []int{-1},
+			description: "空との比較ではすべて-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := matchLines(tt.a, tt.b)
+			if !slices.Equal(result, tt.expected) {
+				t.Errorf("matchLines() = %v, expected %v for %s", result, tt.expected, tt.description)
+			}
+		})
+	}
+}
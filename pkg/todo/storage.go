@@ -1,8 +1,8 @@
 package todo
 
 import (
+	"errors"
 	"os"
-	"path/filepath"
 
 	todotxt "github.com/1set/todotxt"
 )
@@ -10,33 +10,42 @@ import (
 // ディレクトリ作成時のデフォルトパーミッション
 const defaultDirMode = 0755
 
-// Load reads a todo.txt file and returns a TaskList
+// Load reads a todo.txt file and returns a TaskList.
+// Each task's ID is the 1-based line it was read from; lines that are not
+// tasks are skipped here but kept in the file by Save.
 func Load(path string) (todotxt.TaskList, error) {
-	// Ensure the directory exists
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, defaultDirMode); err != nil {
-		return nil, err
-	}
+	var list todotxt.TaskList
+	err := withLock(path, DefaultLockTimeout, func() error {
+		// Ensure the directory and file exist
+		if err := ensureFile(path); err != nil {
+			return err
+		}
 
-	// Create file if it doesn't exist
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		file, err := os.Create(path)
+		data, err := os.ReadFile(path)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		file.Close()
+		list = parseTaskList(data)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-
-	return todotxt.LoadFromPath(path)
+	return list, nil
 }
 
-// Save writes a TaskList to a todo.txt file
+// Save writes a TaskList to a todo.txt file.
+// Tasks are matched to the lines of the file by their ID as returned by Load,
+// so lines that are not tasks and unmodified tasks are kept as they are.
+// The file is replaced atomically, so a crash or a failed write leaves the
+// previous contents untouched.
 func Save(list todotxt.TaskList, path string) error {
-	// Ensure the directory exists
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, defaultDirMode); err != nil {
-		return err
-	}
-
-	return list.WriteToPath(path)
+	return withLock(path, DefaultLockTimeout, func() error {
+		current, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		doc := parseDocument(current)
+		return writeFileAtomic(path, doc.content(doc.render(list)))
+	})
 }
@@ -0,0 +1,257 @@
+package todo
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	todotxt "github.com/1set/todotxt"
+)
+
+// snapshot records the state of the file as of the last load or save
+type snapshot struct {
+	modTime time.Time
+	size    int64
+	hash    [sha256.Size]byte
+	doc     *document
+}
+
+// Store reads and writes a single todo.txt file.
+// It remembers what the file looked like when it was last loaded, so that
+// changes made by other programs in the meantime are merged into a save
+// instead of being overwritten.
+//
+// Every read-modify-write cycle runs under an advisory lock, so several
+// instances working on the same file do not interleave their writes.
+type Store struct {
+	path        string
+	base        *snapshot
+	lockTimeout time.Duration
+	backup      BackupOptions
+	lineMap     []int // Previous line index -> current line index, -1 if gone
+}
+
+// NewStore creates a Store for the todo.txt file at path
+func NewStore(path string) *Store {
+	return &Store{path: path, lockTimeout: DefaultLockTimeout}
+}
+
+// SetBackup configures the backups taken before each save
+func (s *Store) SetBackup(opts BackupOptions) {
+	s.backup = opts
+}
+
+// SetLockTimeout sets how long Load and Save wait for the file lock
+func (s *Store) SetLockTimeout(timeout time.Duration) {
+	s.lockTimeout = timeout
+}
+
+// Path returns the path of the todo.txt file
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Load reads the file, creating it if necessary, and records its state.
+// A *LockError is returned if another process holds the lock too long.
+func (s *Store) Load() (todotxt.TaskList, error) {
+	var list todotxt.TaskList
+	err := withLock(s.path, s.lockTimeout, func() error {
+		if err := ensureFile(s.path); err != nil {
+			return err
+		}
+
+		data, info, err := readFile(s.path)
+		if err != nil {
+			return err
+		}
+
+		snap := newSnapshot(data, info)
+		list = snap.doc.tasks
+		s.setBase(snap)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Save writes list to the file.
+// Tasks are matched to the lines they were loaded from by their ID, so only
+// modified tasks are rewritten; every other line is kept as it is.
+// If the file was changed externally since the last Load, the changes made
+// in memory are rebased onto the current file contents. Conflicting changes
+// are reported as a *ConflictError and nothing is written.
+// The returned TaskList reflects what is on disk after the save.
+func (s *Store) Save(list todotxt.TaskList) (todotxt.TaskList, error) {
+	return s.SaveWithResolution(list, ResolveNone)
+}
+
+// SaveWithResolution is like Save but combines conflicting changes
+// according to resolution instead of failing
+func (s *Store) SaveWithResolution(list todotxt.TaskList, resolution Resolution) (todotxt.TaskList, error) {
+	var saved todotxt.TaskList
+	err := withLock(s.path, s.lockTimeout, func() error {
+		current, info, err := readFile(s.path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+
+		base := parseDocument(nil)
+		if s.base != nil {
+			base = s.base.doc
+		}
+		lines, err := s.rebase(base.render(list), current, info, resolution)
+		if err != nil {
+			return err
+		}
+		if current != nil {
+			// Keep the line endings of the file as it is now
+			base = parseDocument(current)
+		}
+		saved, err = s.replace(current, base.content(lines))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// replace writes data over the file whose current contents are current and
+// records the new state. It must be called with the lock held.
+func (s *Store) replace(current, data []byte) (todotxt.TaskList, error) {
+	// Snapshot the previous contents before they are replaced
+	if s.backup.Enabled && len(current) > 0 && !bytes.Equal(current, data) {
+		if err := createBackup(s.path, current, s.backup, time.Now()); err != nil {
+			return nil, fmt.Errorf("failed to back up %s: %w", s.path, err)
+		}
+	}
+
+	if err := writeFileAtomic(s.path, data); err != nil {
+		return nil, err
+	}
+
+	snap := &snapshot{doc: parseDocument(data)}
+	if info, statErr := os.Stat(s.path); statErr == nil {
+		snap = newSnapshot(data, info)
+		s.setBase(snap)
+	} else {
+		s.base = nil
+	}
+	return snap.doc.tasks, nil
+}
+
+// setBase records a new snapshot and how lines moved since the previous one
+func (s *Store) setBase(snap *snapshot) {
+	if s.base != nil {
+		s.lineMap = matchLines(s.base.doc.lines, snap.doc.lines)
+	} else {
+		s.lineMap = nil
+	}
+	s.base = snap
+}
+
+// TranslateLine maps a 1-based line number from before the last Load or Save
+// to the line holding the same content now. It reports false if the line was
+// changed or removed in between.
+func (s *Store) TranslateLine(line int) (int, bool) {
+	if s.lineMap == nil {
+		return line, true
+	}
+	if line < 1 || line > len(s.lineMap) || s.lineMap[line-1] < 0 {
+		return 0, false
+	}
+	return s.lineMap[line-1] + 1, true
+}
+
+// rebase returns the lines to write, rebasing ours onto the current file
+// contents if they differ from the last snapshot.
+// current and info are nil if the file does not exist.
+func (s *Store) rebase(ours []string, current []byte, info os.FileInfo, resolution Resolution) ([]string, error) {
+	if s.base == nil || info == nil {
+		// Nothing loaded yet, or the file was removed externally; write memory as is
+		return ours, nil
+	}
+	if !s.base.changed(current, info) {
+		return ours, nil
+	}
+
+	theirs := splitLines(string(current))
+	merged, conflicts := merge3(s.base.doc.lines, ours, theirs, resolution)
+	if len(conflicts) > 0 && resolution == ResolveNone {
+		return nil, &ConflictError{Path: s.path, Conflicts: conflicts}
+	}
+	return merged, nil
+}
+
+// changed reports whether the file differs from the snapshot.
+// An unchanged mtime and size is trusted; otherwise the content hash decides.
+func (snap *snapshot) changed(data []byte, info os.FileInfo) bool {
+	if info.ModTime().Equal(snap.modTime) && info.Size() == snap.size {
+		return false
+	}
+	return sha256.Sum256(data) != snap.hash
+}
+
+func newSnapshot(data []byte, info os.FileInfo) *snapshot {
+	return &snapshot{
+		modTime: info.ModTime(),
+		size:    info.Size(),
+		hash:    sha256.Sum256(data),
+		doc:     parseDocument(data),
+	}
+}
+
+// ensureFile creates the file and its directory if they do not exist
+func ensureFile(path string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, defaultDirMode); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		file.Close()
+	}
+	return nil
+}
+
+// readFile reads the file content together with its metadata
+func readFile(path string) ([]byte, os.FileInfo, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, info, nil
+}
+
+// splitLines splits text into lines without a trailing empty element
+func splitLines(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
+
+// joinLines joins lines into file content terminated by a newline
+func joinLines(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
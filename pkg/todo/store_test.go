@@ -0,0 +1,188 @@
+package todo
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/yuucu/todotui/internal/testutil"
+)
+
+func TestStore_SaveWithoutExternalChanges(t *testing.T) {
+	todoPath := filepath.Join(t.TempDir(), "todo.txt")
+	testutil.WriteExternally(t, todoPath, "Task A\nTask B\n")
+
+	store := NewStore(todoPath)
+	list, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	list[0].Completed = true
+	saved, err := store.Save(list)
+	if err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	if got := testutil.ReadFile(t, todoPath); got != "x Task A\nTask B\n" {
+		t.Errorf("file content = %q", got)
+	}
+	if len(saved) != 2 || !saved[0].Completed {
+		t.Errorf("Save() returned unexpected list: %v", saved)
+	}
+}
+
+func TestStore_MergesExternalChanges(t *testing.T) {
+	todoPath := filepath.Join(t.TempDir(), "todo.txt")
+	testutil.WriteExternally(t, todoPath, "Task A\nTask B\nTask C\n")
+
+	store := NewStore(todoPath)
+	list, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// 外部ツールがタスクを追加し、別のタスクを完了させる
+	testutil.WriteExternally(t, todoPath, "Task A\nTask B\nx Task C\nTask D from sync\n")
+
+	// メモリ上では先頭のタスクに優先度を付ける
+	list[0].Priority = "A"
+	saved, err := store.Save(list)
+	if err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	expected := "(A) Task A\nTask B\nx Task C\nTask D from sync\n"
+	if got := testutil.ReadFile(t, todoPath); got != expected {
+		t.Errorf("file content = %q, expected %q", got, expected)
+	}
+	if len(saved) != 4 {
+		t.Errorf("Save() returned %d tasks, expected 4", len(saved))
+	}
+}
+
+func TestStore_ConflictIsReported(t *testing.T) {
+	todoPath := filepath.Join(t.TempDir(), "todo.txt")
+	testutil.WriteExternally(t, todoPath, "Task A\nTask B\n")
+
+	store := NewStore(todoPath)
+	list, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	external := "Task A edited elsewhere\nTask B\n"
+	testutil.WriteExternally(t, todoPath, external)
+
+	list[0].Todo = "Task A edited here"
+	_, err = store.Save(list)
+
+	var conflictErr *ConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("Save() error = %v, expected *ConflictError", err)
+	}
+	if len(conflictErr.Conflicts) != 1 {
+		t.Errorf("conflicts = %d, expected 1", len(conflictErr.Conflicts))
+	}
+	if got := testutil.ReadFile(t, todoPath); got != external {
+		t.Errorf("file was modified despite conflict: %q", got)
+	}
+
+	tests := []struct {
+		name        string
+		resolution  Resolution
+		expected    string
+		description string
+	}{
+		{
+			name:        "keep_ours",
+			resolution:  ResolveOurs,
+			expected:    "Task A edited here\nTask B\n",
+			description: "メモリ側の変更で上書きする",
+		},
+		{
+			name:        "keep_both",
+			resolution:  ResolveBoth,
+			expected:    "Task A edited elsewhere\nTask A edited here\nTask B\n",
+			description: "両方の行を残す",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.WriteExternally(t, todoPath, "Task A\nTask B\n")
+			s := NewStore(todoPath)
+			l, err := s.Load()
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			testutil.WriteExternally(t, todoPath, external)
+
+			l[0].Todo = "Task A edited here"
+			if _, err := s.SaveWithResolution(l, tt.resolution); err != nil {
+				t.Fatalf("SaveWithResolution failed: %v", err)
+			}
+			if got := testutil.ReadFile(t, todoPath); got != tt.expected {
+				t.Errorf("file content = %q, expected %q for %s", got, tt.expected, tt.description)
+			}
+		})
+	}
+}
+
+func TestStore_TouchWithoutChange(t *testing.T) {
+	todoPath := filepath.Join(t.TempDir(), "todo.txt")
+	testutil.WriteExternally(t, todoPath, "Task A\n")
+
+	store := NewStore(todoPath)
+	list, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	// 内容を変えずにmtimeだけ更新する
+	testutil.WriteExternally(t, todoPath, "Task A\n")
+
+	list[0].Todo = "Task A changed"
+	if _, err := store.Save(list); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if got := testutil.ReadFile(t, todoPath); got != "Task A changed\n" {
+		t.Errorf("file content = %q", got)
+	}
+}
+
+func TestStore_TranslateLine(t *testing.T) {
+	todoPath := filepath.Join(t.TempDir(), "todo.txt")
+	testutil.WriteExternally(t, todoPath, "Task A\nTask B\nTask A\n")
+
+	store := NewStore(todoPath)
+	if _, err := store.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if line, ok := store.TranslateLine(2); !ok || line != 2 {
+		t.Errorf("TranslateLine(2) before reload = %d, %v", line, ok)
+	}
+
+	// 先頭に行が追加され、Task Bが変更される
+	testutil.WriteExternally(t, todoPath, "Task Z\nTask A\nTask B changed\nTask A\n")
+	if _, err := store.Load(); err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	tests := []struct {
+		line     int
+		expected int
+		ok       bool
+	}{
+		{line: 1, expected: 2, ok: true},
+		{line: 2, ok: false},
+		{line: 3, expected: 4, ok: true},
+		{line: 4, ok: false},
+	}
+	for _, tt := range tests {
+		line, ok := store.TranslateLine(tt.line)
+		if ok != tt.ok || line != tt.expected {
+			t.Errorf("TranslateLine(%d) = %d, %v, expected %d, %v", tt.line, line, ok, tt.expected, tt.ok)
+		}
+	}
+}
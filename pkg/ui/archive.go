@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	todotxt "github.com/1set/todotxt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yuucu/todotui/pkg/domain"
+	"github.com/yuucu/todotui/pkg/logger"
+	"github.com/yuucu/todotui/pkg/todo"
+)
+
+// autoArchive archives tasks on startup if configured and returns the tasks left in the todo file.
+// Like archiveTasks, it records the archived tasks in the journal.
+func autoArchive(store *todo.Store, appConfig AppConfig, taskList todotxt.TaskList) todotxt.TaskList {
+	if !appConfig.Archive.AutoOnStartup {
+		return taskList
+	}
+
+	remaining, archived, err := store.Archive(appConfig.Archive.Options(time.Now()))
+	if err != nil {
+		// Archiving is housekeeping; never keep the application from starting
+		logger.Warn("Failed to archive tasks on startup", "file", store.Path(), "error", err)
+		return taskList
+	}
+	logger.Info("Archived tasks on startup", "file", store.Path(), "archived", len(archived))
+	entries := todo.NewArchiveEntries(store.Path(), archived)
+	if journalErr := todo.AppendJournal(store.Path(), appConfig.Journal.Options(), entries...); journalErr != nil {
+		logger.Warn("Failed to write journal", "file", store.Path(), "error", journalErr)
+	}
+	return remaining
+}
+
+// archiveTasks moves completed tasks, and deleted tasks past the configured age, to done.txt.
+// The archived tasks are recorded in the journal but not in the undo history:
+// archiving cannot be undone, and the tasks have to be moved back from done.txt.
+// Undo and redo of earlier changes keep working on the tasks left in the file.
+func (m *Model) archiveTasks() tea.Cmd {
+	// The archive works on the file, so unsaved changes have to reach it first
+	if m.savePending || m.unsaved || m.pendingConflict != nil {
+		return m.setStatusMessage("🔒 Cannot archive while changes are waiting to be saved", 3*time.Second)
+	}
+
+	remaining, archived, err := m.store.Archive(m.appConfig.Archive.Options(time.Now()))
+	var lockErr *todo.LockError
+	if errors.As(err, &lockErr) {
+		return m.setStatusMessage("🔒 Cannot archive: "+lockErr.Error(), 3*time.Second)
+	}
+	if err != nil {
+		logger.Error("Failed to archive tasks", "file", m.todoFilePath, "error", err)
+		return m.setStatusMessage("❌ Failed to archive tasks: "+err.Error(), 5*time.Second)
+	}
+	if len(archived) == 0 {
+		return m.setStatusMessage("📦 Nothing to archive", 2*time.Second)
+	}
+
+	logger.Info("Archived tasks", "file", m.todoFilePath, "archived", len(archived))
+	m.pendingJournal = append(m.pendingJournal, todo.NewArchiveEntries(m.todoFilePath, archived)...)
+	m.flushJournal(true)
+	m.setTasks(remaining)
+	m.loadDoneTasks()
+	m.refreshLists()
+	return m.setStatusMessage(fmt.Sprintf("📦 Archived %d task(s) to done.txt", len(archived)), 3*time.Second)
+}
+
+// loadDoneTasks reads the archived tasks shown in the Completed Tasks filter
+func (m *Model) loadDoneTasks() {
+	if !m.appConfig.Archive.ShowInCompleted {
+		m.doneTasks = nil
+		return
+	}
+
+	taskList, err := todo.LoadDone(m.todoFilePath, m.appConfig.Archive.Options(time.Now()))
+	if err != nil {
+		logger.Warn("Failed to load archived tasks", "file", m.todoFilePath, "error", err)
+		return
+	}
+	m.doneTasks = domain.NewTasks(taskList)
+}
+
+// isArchivedTask reports whether task was read from done.txt.
+// Archived tasks are read-only; they no longer exist in the todo file.
+func (m *Model) isArchivedTask(task domain.Task) bool {
+	for _, done := range m.doneTasks {
+		if done.ToTodoTxtTask() == task.ToTodoTxtTask() {
+			return true
+		}
+	}
+	return false
+}
+
+// isTaskMutationKey reports whether key changes the selected task
+func isTaskMutationKey(key string) bool {
+	switch key {
+	case enterKey, eKey, EKey, dKey, pKey, tKey, rKey, DKey:
+		return true
+	}
+	return false
+}
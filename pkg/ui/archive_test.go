@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yuucu/todotui/pkg/todo"
+)
+
+// selectFilter selects the filter with the given name
+func selectFilter(t *testing.T, m *Model, name string) {
+	t.Helper()
+	for i, filter := range m.filters {
+		if filter.name == name {
+			m.filterList.selected = i
+			m.refreshTaskList()
+			return
+		}
+	}
+	t.Fatalf("filter %q not found", name)
+}
+
+// archivedInJournal returns the tasks recorded as archived in the journal of todoPath
+func archivedInJournal(t *testing.T, todoPath string, config AppConfig) []string {
+	t.Helper()
+	entries, err := todo.ReadJournal(todoPath, config.Journal.Options())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var archived []string
+	for _, entry := range entries {
+		if entry.Kind == todo.JournalKindArchive {
+			archived = append(archived, entry.Before)
+		}
+	}
+	return archived
+}
+
+func TestModel_ArchiveKey(t *testing.T) {
+	model, todoPath := newTestModelWithFile(t, "Task A\nx 2025-01-14 Task B\n")
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(AKey)})
+
+	content, err := os.ReadFile(todoPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "Task A\n" {
+		t.Errorf("todo.txt = %q, expected completed task removed", content)
+	}
+	done, err := os.ReadFile(filepath.Join(filepath.Dir(todoPath), "done.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(done) != "x 2025-01-14 Task B\n" {
+		t.Errorf("done.txt = %q", done)
+	}
+	if archived := archivedInJournal(t, todoPath, model.appConfig); !slices.Equal(archived, []string{"x 2025-01-14 Task B"}) {
+		t.Errorf("archived in journal = %q", archived)
+	}
+
+	// アーカイブは u で元に戻せない
+	model.Update(undoKeyMsg)
+	if model.statusMessage != "↩️ Nothing to undo" {
+		t.Errorf("statusMessage = %q, expected nothing to undo after archiving", model.statusMessage)
+	}
+
+	// アーカイブ済みタスクも完了フィルタに表示される
+	selectFilter(t, model, FilterCompletedTasks)
+	if model.filteredTasks.Len() != 1 || model.filteredTasks.ToTaskList()[0].String() != "x 2025-01-14 Task B" {
+		t.Fatalf("Completed filter = %v, expected the archived task", model.filteredTasks)
+	}
+
+	// アーカイブ済みタスクは読み取り専用
+	model.activePane = paneTask
+	model.taskList.selected = 0
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(rKey)})
+	if model.statusMessage != "📦 Archived tasks are read-only" {
+		t.Errorf("statusMessage = %q, expected read-only notice", model.statusMessage)
+	}
+	content, err = os.ReadFile(todoPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "Task A\n" {
+		t.Errorf("todo.txt changed to %q", content)
+	}
+}
+
+func TestNewModel_AutoArchive(t *testing.T) {
+	todoPath := filepath.Join(t.TempDir(), "todo.txt")
+	if err := os.WriteFile(todoPath, []byte("x 2025-01-14 Task A\nTask B\nTask C deleted_at:2020-01-01\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	config := DefaultAppConfig()
+	config.Archive.AutoOnStartup = true
+	config.Archive.PurgeDeletedAfterDays = 30
+	model, err := NewModel(todoPath, config)
+	if err != nil {
+		t.Fatalf("NewModel failed: %v", err)
+	}
+	t.Cleanup(model.Cleanup)
+
+	if model.tasks.Len() != 1 || model.tasks.ToTaskList()[0].String() != "Task B" {
+		t.Errorf("tasks = %v, expected only the active task", model.tasks)
+	}
+	if model.doneTasks.Len() != 2 {
+		t.Errorf("doneTasks = %v, expected completed and purged tasks", model.doneTasks)
+	}
+
+	// 起動時のアーカイブも A と同じようにジャーナルに記録される
+	expected := []string{"x 2025-01-14 Task A", "Task C deleted_at:2020-01-01"}
+	if archived := archivedInJournal(t, todoPath, config); !slices.Equal(archived, expected) {
+		t.Errorf("archived in journal = %q, expected %q", archived, expected)
+	}
+}
@@ -0,0 +1,250 @@
+package ui
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/samber/lo"
+	"github.com/yuucu/todotui/pkg/domain"
+)
+
+// Maximum number of candidates shown in the completion popup
+const maxCompletions = 8
+
+// Date shortcuts offered after due: and t:, in the order they are shown
+var dateCompletions = []string{
+	"today", "tomorrow", "mon", "tue", "wed", "thu", "fri", "sat", "sun",
+	"eow", "eom", "+1w", "+2w", "+1m",
+}
+
+// Recurrences offered after rec:, besides the ones already used
+var recurrenceCompletions = []string{"1d", "1w", "2w", "1m", "1y", "+1w", "+1m"}
+
+// completion is a candidate offered by the completion popup
+type completion struct {
+	text string // Word replacing the word at the cursor, e.g. "+work"
+	hint string // Shown next to the candidate, e.g. the date of a date shortcut
+}
+
+// completionState is the completion popup of the input box
+type completionState struct {
+	candidates []completion
+	selected   int // Candidate put in with Tab, -1 before the first Tab
+	start      int // Rune position of the word being completed
+	end        int // Rune position after it
+}
+
+// completionSource holds what can be completed
+type completionSource struct {
+	projects []string
+	contexts []string
+	values   map[string][]string // Values used for each key:value tag
+	calendar domain.Calendar
+	now      time.Time
+}
+
+// complete returns the candidates for word: projects after +, contexts after @,
+// dates after due: and t:, known values after other keys and known keys otherwise
+func (s completionSource) complete(word string) []completion {
+	if word == "" {
+		return nil
+	}
+
+	var candidates []completion
+	switch {
+	case strings.HasPrefix(word, "+"):
+		for _, project := range matchNames(s.projects, word[1:]) {
+			candidates = append(candidates, completion{text: "+" + project})
+		}
+	case strings.HasPrefix(word, "@"):
+		for _, context := range matchNames(s.contexts, word[1:]) {
+			candidates = append(candidates, completion{text: "@" + context})
+		}
+	case strings.Contains(word, ":"):
+		key, value, _ := strings.Cut(word, ":")
+		candidates = s.completeValue(key, value)
+	default:
+		keys := lo.Keys(s.values)
+		keys = append(keys, domain.TaskFieldDue, domain.TaskFieldThreshold, domain.TaskFieldRecurrence)
+		keys = lo.Uniq(keys)
+		sort.Strings(keys)
+		for _, key := range keys {
+			if hasPrefixFold(key, word) && key != word {
+				candidates = append(candidates, completion{text: key + ":"})
+			}
+		}
+	}
+
+	// すでに入力どおりの候補は出さない
+	candidates = lo.Filter(candidates, func(c completion, _ int) bool {
+		return c.text != word
+	})
+	if len(candidates) > maxCompletions {
+		candidates = candidates[:maxCompletions]
+	}
+	return candidates
+}
+
+// completeValue returns the candidates for the value of a key:value tag
+func (s completionSource) completeValue(key, value string) []completion {
+	var candidates []completion
+	switch key {
+	case domain.TaskFieldDue, domain.TaskFieldThreshold:
+		for _, shortcut := range dateCompletions {
+			if !hasPrefixFold(shortcut, value) {
+				continue
+			}
+			date, err := s.calendar.ParseDate(shortcut, s.now)
+			if err != nil {
+				continue
+			}
+			candidates = append(candidates, completion{
+				text: key + ":" + shortcut,
+				hint: date.Format(domain.DateFormat + " Mon"),
+			})
+		}
+	case domain.TaskFieldRecurrence:
+		values := lo.Uniq(append(slices.Clone(s.values[key]), recurrenceCompletions...))
+		for _, recurrence := range values {
+			if hasPrefixFold(recurrence, value) {
+				candidates = append(candidates, completion{text: key + ":" + recurrence})
+			}
+		}
+	default:
+		for _, known := range s.values[key] {
+			if hasPrefixFold(known, value) {
+				candidates = append(candidates, completion{text: key + ":" + known})
+			}
+		}
+	}
+	return candidates
+}
+
+// matchNames returns the names starting with typed, followed by the names
+// containing its letters in order (so "+wrk" finds "+work")
+func matchNames(names []string, typed string) []string {
+	var prefixed, fuzzy []string
+	for _, name := range names {
+		if hasPrefixFold(name, typed) {
+			prefixed = append(prefixed, name)
+		} else if _, ok := domain.FuzzyMatch(typed, name); ok {
+			fuzzy = append(fuzzy, name)
+		}
+	}
+	return append(prefixed, fuzzy...)
+}
+
+// hasPrefixFold reports whether s starts with prefix, ignoring case
+func hasPrefixFold(s, prefix string) bool {
+	return len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix)
+}
+
+// completionSource collects the projects, contexts and tags of the tasks
+func (m *Model) completionSource() completionSource {
+	values := make(map[string][]string)
+	for _, task := range m.tasks.ToTaskList() {
+		for key, value := range task.AdditionalTags {
+			if key == domain.TaskFieldDeleted {
+				continue
+			}
+			values[key] = append(values[key], value)
+		}
+	}
+	for key := range values {
+		values[key] = lo.Uniq(values[key])
+		sort.Strings(values[key])
+	}
+
+	return completionSource{
+		projects: m.getUniqueProjects(),
+		contexts: m.getUniqueContexts(),
+		values:   values,
+		calendar: m.appConfig.Calendar(),
+		now:      time.Now(),
+	}
+}
+
+// updateCompletions offers the candidates for the word before the cursor
+func (m *Model) updateCompletions() {
+	runes := []rune(m.textInput.Value())
+	end := min(m.textInput.Position(), len(runes))
+	start := end
+	for start > 0 && !unicode.IsSpace(runes[start-1]) {
+		start--
+	}
+
+	m.completion = completionState{
+		candidates: m.completionSource().complete(string(runes[start:end])),
+		selected:   -1,
+		start:      start,
+		end:        end,
+	}
+}
+
+// clearCompletions closes the completion popup
+func (m *Model) clearCompletions() {
+	m.completion = completionState{selected: -1}
+}
+
+// cycleCompletion puts the next (or, with a negative step, the previous)
+// candidate in place of the word being completed
+func (m *Model) cycleCompletion(step int) {
+	c := &m.completion
+	count := len(c.candidates)
+	if count == 0 {
+		return
+	}
+	if c.selected < 0 && step < 0 {
+		c.selected = count - 1
+	} else if c.selected < 0 {
+		c.selected = 0
+	} else {
+		c.selected = ((c.selected+step)%count + count) % count
+	}
+
+	runes := []rune(m.textInput.Value())
+	candidate := []rune(c.candidates[c.selected].text)
+	value := string(runes[:c.start]) + string(candidate) + string(runes[min(c.end, len(runes)):])
+	m.textInput.SetValue(value)
+	c.end = c.start + len(candidate)
+	m.textInput.SetCursor(c.end)
+}
+
+// renderCompletions renders the completion popup shown below the input box
+func (m *Model) renderCompletions() string {
+	if len(m.completion.candidates) == 0 {
+		return ""
+	}
+
+	itemStyle := lipgloss.NewStyle().
+		Foreground(m.currentTheme.Text).
+		Padding(0, 1)
+	selectedStyle := itemStyle.
+		Background(m.currentTheme.SelectionBg).
+		Foreground(m.currentTheme.SelectionFg).
+		Bold(true)
+	hintStyle := lipgloss.NewStyle().
+		Foreground(m.currentTheme.TextMuted)
+
+	var lines []string
+	for i, candidate := range m.completion.candidates {
+		style := itemStyle
+		if i == m.completion.selected {
+			style = selectedStyle
+		}
+		line := style.Render(candidate.text)
+		if candidate.hint != "" {
+			line += " " + hintStyle.Render(candidate.hint)
+		}
+		lines = append(lines, line)
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(m.currentTheme.BorderInactive).
+		Render(strings.Join(lines, "\n"))
+}
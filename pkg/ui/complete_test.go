@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yuucu/todotui/internal/testutil"
+	"github.com/yuucu/todotui/pkg/domain"
+)
+
+func TestCompletionSource_Complete(t *testing.T) {
+	source := completionSource{
+		projects: []string{"home", "homework", "work"},
+		contexts: []string{"office", "phone"},
+		values:   map[string][]string{"waiting": {"alice", "bob"}, "rec": {"3d"}},
+		calendar: domain.NewCalendar(time.Sunday),
+		// 基準日時: 2025年1月15日（水）
+		now: time.Date(2025, 1, 15, 9, 0, 0, 0, time.Local),
+	}
+
+	tests := []struct {
+		word     string
+		expected string
+	}{
+		{word: "+wo", expected: "+work,+homework"},
+		{word: "+hom", expected: "+home,+homework"},
+		{word: "+home", expected: "+homework"},
+		{word: "+wrk", expected: "+homework,+work"},
+		{word: "+", expected: "+home,+homework,+work"},
+		{word: "@PH", expected: "@phone"},
+		{word: "@xyz", expected: ""},
+		{word: "wa", expected: "waiting:"},
+		{word: "d", expected: "due:"},
+		{word: "waiting:", expected: "waiting:alice,waiting:bob"},
+		{word: "waiting:b", expected: "waiting:bob"},
+		{word: "due:t", expected: "due:today,due:tomorrow,due:tue,due:thu"},
+		{word: "t:eo", expected: "t:eow,t:eom"},
+		{word: "rec:", expected: "rec:3d,rec:1d,rec:1w,rec:2w,rec:1m,rec:1y,rec:+1w,rec:+1m"},
+		{word: "", expected: ""},
+		{word: "report", expected: ""},
+	}
+
+	for _, tt := range tests {
+		var texts []string
+		for _, candidate := range source.complete(tt.word) {
+			texts = append(texts, candidate.text)
+		}
+		if got := strings.Join(texts, ","); got != tt.expected {
+			t.Errorf("complete(%q) = %q, expected %q", tt.word, got, tt.expected)
+		}
+	}
+
+	// 日付の候補には実際の日付が添えられる
+	if got := source.complete("due:tom"); len(got) != 1 || got[0].hint != "2025-01-16 Thu" {
+		t.Errorf("complete(due:tom) = %+v", got)
+	}
+}
+
+func TestModel_AddWithCompletion(t *testing.T) {
+	model, todoPath := newTestModelWithFile(t, "Write report +work @office\n")
+
+	pressKey(model, aKey)
+	typeText(model, "Call +w")
+	if got := len(model.completion.candidates); got != 1 {
+		t.Fatalf("candidates = %+v, expected +work", model.completion.candidates)
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if got := model.textInput.Value(); got != "Call +work" {
+		t.Errorf("value after tab = %q", got)
+	}
+
+	// 候補を確定した後も入力を続けられる
+	typeText(model, " @")
+	model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	typeText(model, " due:tom")
+	model.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if got := model.textInput.Value(); got != "Call +work @office due:tomorrow" {
+		t.Errorf("value = %q", got)
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	tomorrow := time.Now().AddDate(0, 0, 1).Format(domain.DateFormat)
+	expected := "Write report +work @office\nCall @office +work due:" + tomorrow + "\n"
+	if got := testutil.ReadFile(t, todoPath); got != expected {
+		t.Errorf("file content = %q, expected %q", got, expected)
+	}
+	if len(model.completion.candidates) != 0 {
+		t.Errorf("the popup should close after saving")
+	}
+}
+
+func TestModel_CycleCompletion(t *testing.T) {
+	model, _ := newTestModelWithFile(t, "Task +home\nTask +homework\nTask +hobby\n")
+
+	pressKey(model, aKey)
+	typeText(model, "Fix +ho")
+
+	// Tab で順に、Shift+Tab で逆に候補を切り替える
+	for _, expected := range []string{"Fix +hobby", "Fix +home", "Fix +homework", "Fix +hobby"} {
+		model.Update(tea.KeyMsg{Type: tea.KeyTab})
+		if got := model.textInput.Value(); got != expected {
+			t.Errorf("value = %q, expected %q", got, expected)
+		}
+	}
+	model.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	if got := model.textInput.Value(); got != "Fix +homework" {
+		t.Errorf("value after shift+tab = %q", got)
+	}
+	if !strings.Contains(model.View(), "+home") {
+		t.Errorf("the popup should be shown")
+	}
+}
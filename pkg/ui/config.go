@@ -5,9 +5,14 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	todotxt "github.com/1set/todotxt"
+	"github.com/samber/lo"
 	"github.com/spf13/viper"
+	"github.com/yuucu/todotui/pkg/domain"
 	"github.com/yuucu/todotui/pkg/logger"
+	"github.com/yuucu/todotui/pkg/todo"
 )
 
 // 設定ファイル用のディレクトリパーミッション
@@ -46,11 +51,91 @@ type AppConfig struct {
 	// Default todo file path
 	DefaultTodoFile string `mapstructure:"default_todo_file"`
 
+	// Number of days the defer key pushes a task's t: date forward
+	DeferDays int `mapstructure:"defer_days"`
+
+	// First day of the week (e.g. sunday, monday) for "This Week" and eow
+	WeekStart string `mapstructure:"week_start"`
+
 	// UI settings
 	UI UIConfig `mapstructure:"ui"`
 
 	// Logging settings
 	Logging LoggingConfig `mapstructure:"logging"`
+
+	// Backup settings
+	Backup BackupConfig `mapstructure:"backup"`
+
+	// done.txt archive settings
+	Archive ArchiveConfig `mapstructure:"archive"`
+
+	// Journal of task changes
+	Journal JournalConfig `mapstructure:"journal"`
+
+	// Saved filters shown in the "Saved" section of the Workspaces pane
+	Filters []SavedFilterConfig `mapstructure:"filters"`
+
+	// Urgency score used by the Next filter and the urgency sort
+	Urgency UrgencyConfig `mapstructure:"urgency"`
+}
+
+// SavedFilterConfig defines a named filter, written as a query and/or
+// structured criteria. All given criteria must match.
+type SavedFilterConfig struct {
+	Name string `mapstructure:"name"`
+
+	// Query in the : prompt syntax, e.g. "+work -status:done"
+	Query string `mapstructure:"query"`
+
+	// Structured criteria, combined with the query
+	Projects []string `mapstructure:"projects"`
+	Contexts []string `mapstructure:"contexts"`
+	Priority string   `mapstructure:"priority"` // e.g. "A" or "A..B"
+	Due      string   `mapstructure:"due"`      // e.g. "<=+7d"
+	Status   string   `mapstructure:"status"`   // e.g. "open"
+
+	// Sort order of the tasks, e.g. "due,priority"; empty uses the sort selected with s
+	Sort string `mapstructure:"sort"`
+}
+
+// QueryString returns the query and the structured criteria as one query
+func (c SavedFilterConfig) QueryString() string {
+	var terms []string
+	for _, project := range c.Projects {
+		terms = append(terms, "+"+project)
+	}
+	for _, context := range c.Contexts {
+		terms = append(terms, "@"+context)
+	}
+	if c.Priority != "" {
+		terms = append(terms, "pri:"+c.Priority)
+	}
+	if c.Due != "" {
+		terms = append(terms, domain.TaskFieldDuePrefix+c.Due)
+	}
+	if c.Status != "" {
+		terms = append(terms, "status:"+c.Status)
+	}
+
+	query := strings.TrimSpace(c.Query)
+	if query == "" {
+		return strings.Join(terms, " ")
+	}
+	if len(terms) == 0 {
+		return query
+	}
+	// Keep an OR in the query from taking the criteria with it
+	return "(" + query + ") " + strings.Join(terms, " ")
+}
+
+// ParseQuery parses the saved filter into a query
+func (c SavedFilterConfig) ParseQuery() (*domain.Query, error) {
+	return domain.ParseQuery(c.QueryString())
+}
+
+// ParseSort parses the sort order of the saved filter
+func (c SavedFilterConfig) ParseSort() (domain.SortOrder, error) {
+	return domain.ParseSortOrder(c.Sort)
 }
 
 // UIConfig defines UI-specific settings
@@ -69,17 +154,136 @@ type UIConfig struct {
 	CheckboxStyle string `mapstructure:"checkbox_style"`
 }
 
+// UrgencyConfig defines how the urgency score is computed and shown
+type UrgencyConfig struct {
+	// Coefficients of the score; see domain.UrgencyCoefficients
+	PriorityA float64 `mapstructure:"priority_a"`
+	PriorityB float64 `mapstructure:"priority_b"`
+	PriorityC float64 `mapstructure:"priority_c"`
+	Due       float64 `mapstructure:"due"`
+	Age       float64 `mapstructure:"age"`
+	Project   float64 `mapstructure:"project"`
+	Upcoming  float64 `mapstructure:"upcoming"`
+
+	// Weights of tags, e.g. "+work": 2.0, "@phone": -1.0, "waiting": -5.0
+	Tags map[string]float64 `mapstructure:"tags"`
+
+	// Number of tasks listed in the Next filter
+	NextCount int `mapstructure:"next_count"`
+
+	// Show the score of each task in the task pane
+	Show bool `mapstructure:"show"`
+}
+
+// Coefficients converts the configuration into domain.UrgencyCoefficients
+func (c UrgencyConfig) Coefficients() domain.UrgencyCoefficients {
+	return domain.UrgencyCoefficients{
+		PriorityA: c.PriorityA,
+		PriorityB: c.PriorityB,
+		PriorityC: c.PriorityC,
+		Due:       c.Due,
+		Age:       c.Age,
+		Project:   c.Project,
+		Upcoming:  c.Upcoming,
+		Tags:      c.Tags,
+	}
+}
+
+// defaultUrgencyConfig returns the urgency settings with the domain's default coefficients
+func defaultUrgencyConfig() UrgencyConfig {
+	coefficients := domain.DefaultUrgencyCoefficients()
+	return UrgencyConfig{
+		PriorityA: coefficients.PriorityA,
+		PriorityB: coefficients.PriorityB,
+		PriorityC: coefficients.PriorityC,
+		Due:       coefficients.Due,
+		Age:       coefficients.Age,
+		Project:   coefficients.Project,
+		Upcoming:  coefficients.Upcoming,
+		NextCount: DefaultNextCount,
+	}
+}
+
 // LoggingConfig defines logging settings
 type LoggingConfig struct {
 	LogLevel string `mapstructure:"log_level"`
 }
 
+// BackupConfig defines the backups taken before each save
+type BackupConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+
+	// Backup directory; empty means .todotui/backups next to the todo file
+	Dir string `mapstructure:"dir"`
+
+	// Retention limits; 0 disables the limit
+	MaxCount   int `mapstructure:"max_count"`
+	MaxAgeDays int `mapstructure:"max_age_days"`
+}
+
+// Options converts the configuration into todo.BackupOptions
+func (c BackupConfig) Options() todo.BackupOptions {
+	return todo.BackupOptions{
+		Enabled:  c.Enabled,
+		Dir:      c.Dir,
+		MaxCount: c.MaxCount,
+		MaxAge:   time.Duration(c.MaxAgeDays) * 24 * time.Hour,
+	}
+}
+
+// JournalConfig defines the append-only journal of task changes
+type JournalConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+
+	// Journal file; empty means .todotui/journal.jsonl next to the todo file
+	File string `mapstructure:"file"`
+}
+
+// Options converts the configuration into todo.JournalOptions
+func (c JournalConfig) Options() todo.JournalOptions {
+	return todo.JournalOptions{
+		Enabled: c.Enabled,
+		File:    c.File,
+	}
+}
+
+// ArchiveConfig defines how tasks are moved to done.txt
+type ArchiveConfig struct {
+	// Archive file; empty means done.txt next to the todo file
+	DoneFile string `mapstructure:"done_file"`
+
+	// Archive completed tasks when the application starts
+	AutoOnStartup bool `mapstructure:"auto_on_startup"`
+
+	// Also archive deleted tasks older than this many days; 0 keeps them
+	PurgeDeletedAfterDays int `mapstructure:"purge_deleted_after_days"`
+
+	// Include archived tasks in the "Completed Tasks" filter
+	ShowInCompleted bool `mapstructure:"show_in_completed"`
+}
+
+// Options converts the configuration into todo.ArchiveOptions
+func (c ArchiveConfig) Options(now time.Time) todo.ArchiveOptions {
+	return todo.ArchiveOptions{
+		DoneFile: c.DoneFile,
+		Select: func(task *todotxt.Task) bool {
+			domainTask, err := domain.NewTask(task)
+			if err != nil {
+				return false
+			}
+			return domainTask.IsArchivable(now, c.PurgeDeletedAfterDays)
+		},
+	}
+}
+
 // DefaultAppConfig returns the default application configuration
 func DefaultAppConfig() AppConfig {
 	return AppConfig{
 		Theme:           "catppuccin",
 		PriorityLevels:  []string{"", "A", "B", "C", "D"},
 		DefaultTodoFile: "", // No default file, must be specified explicitly
+		DeferDays:       DefaultDeferDays,
+		WeekStart:       DefaultWeekStart,
 		UI: UIConfig{
 			LeftPaneRatio:     0.33,
 			MinLeftPaneWidth:  18,
@@ -90,9 +294,31 @@ func DefaultAppConfig() AppConfig {
 		Logging: LoggingConfig{
 			LogLevel: "WARN", // デフォルトは警告レベル
 		},
+		// Backups and the journal are on by default and go to .todotui/ next to the todo file
+		Backup: BackupConfig{
+			Enabled:    true,
+			MaxCount:   DefaultBackupMaxCount,
+			MaxAgeDays: DefaultBackupMaxAgeDays,
+		},
+		Archive: ArchiveConfig{
+			ShowInCompleted: true,
+		},
+		Journal: JournalConfig{
+			Enabled: true,
+		},
+		Urgency: defaultUrgencyConfig(),
 	}
 }
 
+// Calendar returns the calendar given by the week start setting
+func (c AppConfig) Calendar() domain.Calendar {
+	weekStart, err := domain.ParseWeekday(c.WeekStart)
+	if err != nil {
+		weekStart = domain.DefaultWeekStart
+	}
+	return domain.NewCalendar(weekStart)
+}
+
 // LoadConfigFromFile loads configuration from a file using Viper
 // Supports YAML, JSON, TOML, HCL, envfile and Java properties config files
 func LoadConfigFromFile(configPath string) (AppConfig, error) {
@@ -264,6 +490,66 @@ func validateAndFixConfig(config AppConfig) AppConfig {
 		config.DefaultTodoFile = ExpandHomePath(config.DefaultTodoFile)
 	}
 
+	// Validate defer days
+	if config.DeferDays <= 0 {
+		config.DeferDays = DefaultDeferDays
+	}
+
+	// Validate the number of tasks in the Next filter
+	if config.Urgency.NextCount <= 0 {
+		config.Urgency.NextCount = DefaultNextCount
+	}
+
+	// Validate week start
+	if weekStart, err := domain.ParseWeekday(config.WeekStart); err != nil {
+		config.WeekStart = DefaultWeekStart
+	} else {
+		config.WeekStart = strings.ToLower(weekStart.String())
+	}
+
+	// Validate backup settings
+	if config.Backup.MaxCount < 0 {
+		config.Backup.MaxCount = DefaultBackupMaxCount
+	}
+	if config.Backup.MaxAgeDays < 0 {
+		config.Backup.MaxAgeDays = DefaultBackupMaxAgeDays
+	}
+	if config.Backup.Dir != "" {
+		config.Backup.Dir = ExpandHomePath(config.Backup.Dir)
+	}
+
+	// Validate archive settings
+	if config.Archive.PurgeDeletedAfterDays < 0 {
+		config.Archive.PurgeDeletedAfterDays = 0
+	}
+	if config.Archive.DoneFile != "" {
+		config.Archive.DoneFile = ExpandHomePath(config.Archive.DoneFile)
+	}
+
+	// Validate journal settings
+	if config.Journal.File != "" {
+		config.Journal.File = ExpandHomePath(config.Journal.File)
+	}
+
+	// Drop saved filters without a name or with a query that does not parse
+	config.Filters = lo.Filter(config.Filters, func(filter SavedFilterConfig, _ int) bool {
+		if strings.TrimSpace(filter.Name) == "" {
+			logger.Warn("Ignoring saved filter without a name", "query", filter.QueryString())
+			return false
+		}
+		if _, err := filter.ParseQuery(); err != nil {
+			logger.Warn("Ignoring saved filter with an invalid query", "name", filter.Name, "error", err)
+			return false
+		}
+		return true
+	})
+	for i, filter := range config.Filters {
+		if _, err := filter.ParseSort(); err != nil {
+			logger.Warn("Ignoring invalid sort of saved filter", "name", filter.Name, "error", err)
+			config.Filters[i].Sort = ""
+		}
+	}
+
 	return config
 }
 
@@ -283,6 +569,8 @@ func SaveConfigToFile(config AppConfig, configPath string) error {
 	v.Set("theme", config.Theme)
 	v.Set("priority_levels", config.PriorityLevels)
 	v.Set("default_todo_file", config.DefaultTodoFile)
+	v.Set("defer_days", config.DeferDays)
+	v.Set("week_start", config.WeekStart)
 	v.Set("ui.left_pane_ratio", config.UI.LeftPaneRatio)
 	v.Set("ui.min_left_pane_width", config.UI.MinLeftPaneWidth)
 	v.Set("ui.min_right_pane_width", config.UI.MinRightPaneWidth)
@@ -292,6 +580,45 @@ func SaveConfigToFile(config AppConfig, configPath string) error {
 	// Set logging configuration
 	v.Set("logging.log_level", config.Logging.LogLevel)
 
+	// Set backup configuration
+	v.Set("backup.enabled", config.Backup.Enabled)
+	v.Set("backup.dir", config.Backup.Dir)
+	v.Set("backup.max_count", config.Backup.MaxCount)
+	v.Set("backup.max_age_days", config.Backup.MaxAgeDays)
+
+	// Set archive configuration
+	v.Set("archive.done_file", config.Archive.DoneFile)
+	v.Set("archive.auto_on_startup", config.Archive.AutoOnStartup)
+	v.Set("archive.purge_deleted_after_days", config.Archive.PurgeDeletedAfterDays)
+	v.Set("archive.show_in_completed", config.Archive.ShowInCompleted)
+
+	// Set journal configuration
+	v.Set("journal.enabled", config.Journal.Enabled)
+	v.Set("journal.file", config.Journal.File)
+
+	// Set urgency configuration
+	v.Set("urgency.priority_a", config.Urgency.PriorityA)
+	v.Set("urgency.priority_b", config.Urgency.PriorityB)
+	v.Set("urgency.priority_c", config.Urgency.PriorityC)
+	v.Set("urgency.due", config.Urgency.Due)
+	v.Set("urgency.age", config.Urgency.Age)
+	v.Set("urgency.project", config.Urgency.Project)
+	v.Set("urgency.upcoming", config.Urgency.Upcoming)
+	if len(config.Urgency.Tags) > 0 {
+		v.Set("urgency.tags", config.Urgency.Tags)
+	}
+	v.Set("urgency.next_count", config.Urgency.NextCount)
+	v.Set("urgency.show", config.Urgency.Show)
+
+	// Set saved filters
+	if len(config.Filters) > 0 {
+		filters := make([]map[string]any, 0, len(config.Filters))
+		for _, filter := range config.Filters {
+			filters = append(filters, savedFilterSettings(filter))
+		}
+		v.Set("filters", filters)
+	}
+
 	// Set config file path (Viper will determine format by extension)
 	v.SetConfigFile(configPath)
 
@@ -302,3 +629,30 @@ func SaveConfigToFile(config AppConfig, configPath string) error {
 
 	return nil
 }
+
+// savedFilterSettings returns the settings of a saved filter, leaving out empty criteria
+func savedFilterSettings(filter SavedFilterConfig) map[string]any {
+	settings := map[string]any{"name": filter.Name}
+	if filter.Query != "" {
+		settings["query"] = filter.Query
+	}
+	if len(filter.Projects) > 0 {
+		settings["projects"] = filter.Projects
+	}
+	if len(filter.Contexts) > 0 {
+		settings["contexts"] = filter.Contexts
+	}
+	if filter.Priority != "" {
+		settings["priority"] = filter.Priority
+	}
+	if filter.Due != "" {
+		settings["due"] = filter.Due
+	}
+	if filter.Status != "" {
+		settings["status"] = filter.Status
+	}
+	if filter.Sort != "" {
+		settings["sort"] = filter.Sort
+	}
+	return settings
+}
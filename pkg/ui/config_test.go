@@ -4,6 +4,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/yuucu/todotui/pkg/domain"
 )
 
 func TestExpandHomePath(t *testing.T) {
@@ -104,6 +107,33 @@ func TestDefaultAppConfig(t *testing.T) {
 	if config.UI.VerticalPadding != 2 {
 		t.Errorf("Default vertical padding = %d, expected 2", config.UI.VerticalPadding)
 	}
+
+	if config.DeferDays != DefaultDeferDays {
+		t.Errorf("Default defer days = %d, expected %d", config.DeferDays, DefaultDeferDays)
+	}
+
+	// 緊急度の係数はdomainのデフォルト値
+	defaults := domain.DefaultUrgencyCoefficients()
+	if got := config.Urgency.Coefficients(); got.PriorityA != defaults.PriorityA || got.Due != defaults.Due {
+		t.Errorf("Default urgency coefficients = %+v", got)
+	}
+	if config.Urgency.NextCount != DefaultNextCount || config.Urgency.Show {
+		t.Errorf("Default urgency settings = %+v", config.Urgency)
+	}
+
+	// アーカイブ設定のデフォルト値
+	if config.Archive.AutoOnStartup {
+		t.Error("Auto archive should be disabled by default")
+	}
+
+	if !config.Archive.ShowInCompleted {
+		t.Error("Archived tasks should be shown in the Completed filter by default")
+	}
+
+	// ジャーナル設定のデフォルト値
+	if !config.Journal.Enabled || config.Journal.File != "" {
+		t.Errorf("Default journal = %+v, expected enabled next to the todo file", config.Journal)
+	}
 }
 
 func TestValidateAndFixConfig(t *testing.T) {
@@ -191,6 +221,53 @@ func TestValidateAndFixConfig(t *testing.T) {
 			t.Errorf("Negative MinRightPaneWidth should be fixed to 28, got %d", result.UI.MinRightPaneWidth)
 		}
 	})
+
+	t.Run("negative backup limits fixed", func(t *testing.T) {
+		config := AppConfig{
+			Backup: BackupConfig{
+				Enabled:    true,
+				MaxCount:   -1,
+				MaxAgeDays: -7,
+			},
+		}
+
+		result := validateAndFixConfig(config)
+
+		if result.Backup.MaxCount != DefaultBackupMaxCount {
+			t.Errorf("Negative MaxCount should be fixed to %d, got %d", DefaultBackupMaxCount, result.Backup.MaxCount)
+		}
+		if result.Backup.MaxAgeDays != DefaultBackupMaxAgeDays {
+			t.Errorf("Negative MaxAgeDays should be fixed to %d, got %d", DefaultBackupMaxAgeDays, result.Backup.MaxAgeDays)
+		}
+
+		opts := result.Backup.Options()
+		if opts.MaxAge != time.Duration(DefaultBackupMaxAgeDays)*24*time.Hour {
+			t.Errorf("Options().MaxAge = %v", opts.MaxAge)
+		}
+	})
+
+	t.Run("week start normalized", func(t *testing.T) {
+		tests := []struct {
+			weekStart string
+			expected  string
+			weekday   time.Weekday
+		}{
+			{weekStart: "Monday", expected: "monday", weekday: time.Monday},
+			{weekStart: "sat", expected: "saturday", weekday: time.Saturday},
+			{weekStart: "", expected: DefaultWeekStart, weekday: time.Sunday},
+			{weekStart: "someday", expected: DefaultWeekStart, weekday: time.Sunday},
+		}
+
+		for _, tt := range tests {
+			result := validateAndFixConfig(AppConfig{WeekStart: tt.weekStart})
+			if result.WeekStart != tt.expected {
+				t.Errorf("WeekStart %q = %q, expected %q", tt.weekStart, result.WeekStart, tt.expected)
+			}
+			if got := result.Calendar().WeekStart; got != tt.weekday {
+				t.Errorf("Calendar().WeekStart for %q = %v, expected %v", tt.weekStart, got, tt.weekday)
+			}
+		}
+	})
 }
 
 func TestValidateAndFixConfigPriorityLevels(t *testing.T) {
@@ -430,3 +507,145 @@ func TestConfigIntegration(t *testing.T) {
 			validatedConfig.UI.LeftPaneRatio, customConfig.UI.LeftPaneRatio)
 	}
 }
+
+func TestSavedFilterConfig_QueryString(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   SavedFilterConfig
+		expected string
+	}{
+		{
+			name:     "query only",
+			filter:   SavedFilterConfig{Name: "Waiting", Query: " waiting:* status:open "},
+			expected: "waiting:* status:open",
+		},
+		{
+			name:     "structured criteria",
+			filter:   SavedFilterConfig{Name: "Sprint", Projects: []string{"sprint"}, Contexts: []string{"office"}, Priority: "A..B", Due: "<=+7d", Status: "open"},
+			expected: "+sprint @office pri:A..B due:<=+7d status:open",
+		},
+		{
+			name:     "query and criteria",
+			filter:   SavedFilterConfig{Name: "Calls", Query: "@phone OR @call", Status: "open"},
+			expected: "(@phone OR @call) status:open",
+		},
+		{
+			name:     "nothing matches every task",
+			filter:   SavedFilterConfig{Name: "Everything"},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.QueryString(); got != tt.expected {
+				t.Errorf("QueryString() = %q, expected %q", got, tt.expected)
+			}
+			if _, err := tt.filter.ParseQuery(); err != nil {
+				t.Errorf("ParseQuery() failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFromFileSavedFilters(t *testing.T) {
+	configContent := `filters:
+  - name: Waiting on others
+    query: "waiting:* status:open"
+    sort: bogus
+  - name: Sprint backlog
+    projects: [sprint]
+    priority: A..B
+    status: open
+    sort: due,priority
+  - name: Broken
+    query: "(+work"
+  - query: "+nameless"
+`
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte(configContent), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadConfigFromFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfigFromFile failed: %v", err)
+	}
+	config := validateAndFixConfig(loaded)
+
+	// 名前のないフィルタと解析できないフィルタは除外される
+	if len(config.Filters) != 2 {
+		t.Fatalf("Filters = %+v, expected 2 valid filters", config.Filters)
+	}
+	if config.Filters[0].Name != "Waiting on others" || config.Filters[0].Query != "waiting:* status:open" {
+		t.Errorf("first filter = %+v", config.Filters[0])
+	}
+	if got := config.Filters[1].QueryString(); got != "+sprint pri:A..B status:open" {
+		t.Errorf("second filter query = %q", got)
+	}
+	// 解析できない並び順は無視され、フィルタ自体は残る
+	if config.Filters[0].Sort != "" || config.Filters[1].Sort != "due,priority" {
+		t.Errorf("sorts = %q, %q", config.Filters[0].Sort, config.Filters[1].Sort)
+	}
+
+	// 保存して読み込み直しても同じ内容になる
+	savedPath := filepath.Join(t.TempDir(), "saved.yaml")
+	if err := SaveConfigToFile(config, savedPath); err != nil {
+		t.Fatalf("SaveConfigToFile failed: %v", err)
+	}
+	reloaded, err := LoadConfigFromFile(savedPath)
+	if err != nil {
+		t.Fatalf("LoadConfigFromFile failed: %v", err)
+	}
+	if len(reloaded.Filters) != 2 || reloaded.Filters[1].QueryString() != "+sprint pri:A..B status:open" ||
+		reloaded.Filters[1].Sort != "due,priority" {
+		t.Errorf("reloaded filters = %+v", reloaded.Filters)
+	}
+}
+
+func TestLoadConfigFromFileUrgency(t *testing.T) {
+	configContent := `urgency:
+  due: 8.5
+  tags:
+    "+Work": 2
+    waiting: -5
+  next_count: 0
+  show: true
+`
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte(configContent), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadConfigFromFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfigFromFile failed: %v", err)
+	}
+	config := validateAndFixConfig(loaded)
+
+	// 指定しなかった係数はデフォルト値のまま
+	coefficients := config.Urgency.Coefficients()
+	if coefficients.Due != 8.5 || coefficients.PriorityA != domain.DefaultUrgencyCoefficients().PriorityA {
+		t.Errorf("coefficients = %+v", coefficients)
+	}
+	// キーの大文字小文字は区別しない
+	if coefficients.Tags["+work"] != 2 || coefficients.Tags["waiting"] != -5 {
+		t.Errorf("tag coefficients = %v", coefficients.Tags)
+	}
+	if config.Urgency.NextCount != DefaultNextCount || !config.Urgency.Show {
+		t.Errorf("urgency settings = %+v", config.Urgency)
+	}
+
+	// 保存して読み込み直しても同じ内容になる
+	savedPath := filepath.Join(t.TempDir(), "saved.yaml")
+	if err := SaveConfigToFile(config, savedPath); err != nil {
+		t.Fatalf("SaveConfigToFile failed: %v", err)
+	}
+	reloaded, err := LoadConfigFromFile(savedPath)
+	if err != nil {
+		t.Fatalf("LoadConfigFromFile failed: %v", err)
+	}
+	if reloaded.Urgency.Due != 8.5 || reloaded.Urgency.Tags["waiting"] != -5 || !reloaded.Urgency.Show {
+		t.Errorf("reloaded urgency = %+v", reloaded.Urgency)
+	}
+}
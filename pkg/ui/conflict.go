@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yuucu/todotui/pkg/logger"
+	"github.com/yuucu/todotui/pkg/todo"
+)
+
+// handleConflictKey handles key input while the conflict prompt is shown
+func (m *Model) handleConflictKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case mKey:
+		return m.resolveConflict(todo.ResolveOurs, "✅ Kept your changes")
+	case tKey:
+		return m.resolveConflict(todo.ResolveTheirs, "✅ Kept changes from disk")
+	case bKey:
+		return m.resolveConflict(todo.ResolveBoth, "✅ Kept both versions")
+	case escKey, ctrlCKey:
+		// Discard unsaved changes and show the file as it is on disk
+		m.pendingConflict = nil
+		m.viewMode = ViewFilter
+		m.unsaved = false
+		m.flushJournal(false)
+		taskList, err := m.store.Load()
+		if err != nil {
+			logger.Error("Failed to reload tasks after conflict", "file", m.todoFilePath, "error", err)
+			return m.setStatusMessage("❌ Failed to reload tasks: "+err.Error(), 5*time.Second)
+		}
+		m.setTasks(taskList)
+		m.refreshLists()
+		return m.setStatusMessage("🔄 Discarded your changes", 3*time.Second)
+	}
+	return nil
+}
+
+// resolveConflict saves the pending changes using the given resolution
+func (m *Model) resolveConflict(resolution todo.Resolution, message string) tea.Cmd {
+	m.pendingConflict = nil
+	m.viewMode = ViewFilter
+
+	logger.Debug("Resolving conflict", "file", m.todoFilePath, "resolution", resolution)
+	taskList, err := m.store.SaveWithResolution(m.tasks.ToTaskList(), resolution)
+	if err != nil {
+		logger.Error("Failed to save tasks after conflict", "file", m.todoFilePath, "error", err)
+		m.unsaved = true
+		return m.setStatusMessage("❌ Failed to save tasks to file: "+err.Error(), 5*time.Second)
+	}
+	m.unsaved = false
+	// Keeping the version on disk may have dropped the journaled changes
+	m.flushJournal(resolution != todo.ResolveTheirs)
+	m.setTasks(taskList)
+	m.refreshLists()
+	return m.setStatusMessage(message, 2*time.Second)
+}
+
+// renderConflictView renders the prompt asking how to resolve a conflict
+func (m *Model) renderConflictView() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(m.currentTheme.Warning).
+		Bold(true).
+		Padding(0, 1)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(m.currentTheme.TextMuted).
+		Padding(0, 1)
+
+	theirsStyle := lipgloss.NewStyle().
+		Foreground(m.currentTheme.Secondary).
+		Padding(0, 3)
+
+	oursStyle := lipgloss.NewStyle().
+		Foreground(m.currentTheme.Primary).
+		Padding(0, 3)
+
+	helpStyle := lipgloss.NewStyle().
+		Foreground(m.currentTheme.TextSubtle).
+		Padding(0, 1)
+
+	var sections []string
+	sections = append(sections,
+		titleStyle.Render(ConflictTitle),
+		labelStyle.Render(fmt.Sprintf("%s was changed by another program while you were editing.", m.todoFilePath)),
+		"",
+	)
+
+	// Keep the prompt within the terminal height
+	maxLines := max(MinimumContentHeight, m.height-8)
+	usedLines := 0
+
+	if m.pendingConflict != nil {
+		for i, conflict := range m.pendingConflict.Conflicts {
+			if usedLines >= maxLines {
+				sections = append(sections, labelStyle.Render(Ellipsis))
+				break
+			}
+
+			sections = append(sections, labelStyle.Render(fmt.Sprintf("Conflict %d/%d", i+1, len(m.pendingConflict.Conflicts))))
+			sections = append(sections, labelStyle.Render("On disk:"))
+			sections = append(sections, theirsStyle.Render(formatConflictLines(conflict.Theirs)))
+			sections = append(sections, labelStyle.Render("Yours:"))
+			sections = append(sections, oursStyle.Render(formatConflictLines(conflict.Ours)))
+			sections = append(sections, "")
+			usedLines += len(conflict.Theirs) + len(conflict.Ours) + 4
+		}
+	}
+
+	sections = append(sections, helpStyle.Render(ConflictModeHelp))
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// formatConflictLines formats the lines of one side of a conflict
+func formatConflictLines(lines []string) string {
+	if len(lines) == 0 {
+		return "(removed)"
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yuucu/todotui/internal/testutil"
+	"github.com/yuucu/todotui/pkg/todo"
+)
+
+// newTestModelWithFile creates a model backed by a real todo file
+func newTestModelWithFile(t *testing.T, content string) (*Model, string) {
+	t.Helper()
+	todoPath := filepath.Join(t.TempDir(), "todo.txt")
+	if err := os.WriteFile(todoPath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	model, err := NewModel(todoPath, DefaultAppConfig())
+	if err != nil {
+		t.Fatalf("NewModel failed: %v", err)
+	}
+	t.Cleanup(model.Cleanup)
+	model.refreshLists()
+	return model, todoPath
+}
+
+func TestModel_ConflictPrompt(t *testing.T) {
+	tests := []struct {
+		name        string
+		key         string
+		expected    string
+		description string
+	}{
+		{
+			name:        "keep_mine",
+			key:         mKey,
+			expected:    "(A) Task A\n",
+			description: "自分の変更を採用する",
+		},
+		{
+			name:        "keep_theirs",
+			key:         tKey,
+			expected:    "Task A renamed\n",
+			description: "ディスク上の変更を採用する",
+		},
+		{
+			name:        "keep_both",
+			key:         bKey,
+			expected:    "Task A renamed\n(A) Task A\n",
+			description: "両方の変更を残す",
+		},
+		{
+			name:        "discard_mine",
+			key:         escKey,
+			expected:    "Task A renamed\n",
+			description: "自分の変更を破棄してディスクから再読み込みする",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, todoPath := newTestModelWithFile(t, "Task A\n")
+			testutil.WriteExternally(t, todoPath, "Task A renamed\n")
+
+			task := model.tasks.Get(0)
+			if err := task.CyclePriority(model.appConfig.PriorityLevels); err != nil {
+				t.Fatal(err)
+			}
+			model.saveAndRefresh()
+
+			if model.viewMode != ViewConflict {
+				t.Fatalf("viewMode = %v, expected ViewConflict for %s", model.viewMode, tt.description)
+			}
+
+			var keyMsg tea.KeyMsg
+			if tt.key == escKey {
+				keyMsg = tea.KeyMsg{Type: tea.KeyEsc}
+			} else {
+				keyMsg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(tt.key)}
+			}
+			model.Update(keyMsg)
+
+			if model.viewMode != ViewFilter {
+				t.Errorf("viewMode = %v, expected ViewFilter after resolving for %s", model.viewMode, tt.description)
+			}
+			content, err := os.ReadFile(todoPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("file content = %q, expected %q for %s", content, tt.expected, tt.description)
+			}
+		})
+	}
+}
+
+func TestModel_UnsavedChangesSurviveReload(t *testing.T) {
+	model, todoPath := newTestModelWithFile(t, "Task A\nTask B\nTask C\n")
+	lockErr := &todo.LockError{
+		Path:   todoPath,
+		Holder: &todo.LockHolder{PID: 4242, Host: "teammate", Since: time.Now()},
+	}
+
+	task := model.tasks.Get(0)
+	if err := task.CyclePriority(model.appConfig.PriorityLevels); err != nil {
+		t.Fatal(err)
+	}
+	// ロックが解放されないまま再試行回数の上限に達した状態にする
+	model.lockRetries = maxLockRetries
+	model.waitForLock(lockErr)
+
+	// 他のプログラムによる変更を検知しても、保存されていない変更を読み込みで上書きしない
+	testutil.WriteExternally(t, todoPath, "Task A\nTask B\nTask C renamed\n")
+	model.Update(TaskListChangedMsg{})
+
+	if model.unsaved {
+		t.Error("unsaved = true, expected the changes to be saved when the file changed")
+	}
+	content, err := os.ReadFile(todoPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "(A) Task A\nTask B\nTask C renamed\n"; string(content) != expected {
+		t.Errorf("file content = %q, expected %q", content, expected)
+	}
+}
@@ -1,5 +1,7 @@
 package ui
 
+import "time"
+
 // ===============================
 // ターミナル・UI関連定数
 // ===============================
@@ -80,8 +82,14 @@ const (
 	FilterCompletedTasks = "Completed Tasks"
 	FilterDeletedTasks   = "Deleted Tasks"
 	FilterNoProject      = "No Project"
+	FilterUpcoming       = "Upcoming"
+	FilterNext           = "Next"
+
+	// Prefix of the filter showing the query typed at the : prompt
+	FilterQueryPrefix = "🔍 "
 
 	// Section headers
+	FilterHeaderSaved    = "── Saved ──"
 	FilterHeaderProjects = "── Projects ──"
 	FilterHeaderContexts = "── Contexts ──"
 )
@@ -91,6 +99,28 @@ const (
 // ===============================
 
 const (
+	// Text input prompt and placeholder for adding/editing tasks
+	TextInputPrompt = "> "
+	TaskInputPrompt = "Enter a task..."
+
+	// Query prompt
+	QueryPrompt      = ":"
+	QueryPlaceholder = "+project @context pri:A..B due:<=+7d -status:done \"text\""
+
+	// Name of the sort order that keeps the order of the file
+	SortNameFileOrder = "file order"
+
+	// Prefix of the urgency score shown after a task
+	UrgencyPrefix = "⚡"
+
+	// Search prompt
+	SearchPrompt      = "/"
+	SearchPlaceholder = "fuzzy search tasks, projects and contexts"
+
+	// Bulk edit prompt of visual mode
+	BulkEditPrompt      = "edit> "
+	BulkEditPlaceholder = "+project @context -+project -@context due:<date> due:none"
+
 	// Text area placeholder
 	TaskInputPlaceholder = "Enter task description (e.g., 'call @mom +home due:2025-01-15')"
 
@@ -99,12 +129,18 @@ const (
 	EditTaskTitle = "Edit Task"
 
 	// Edit mode help
-	EditModeHelp = "Enter: save | Esc/Ctrl+C: cancel"
+	EditModeHelp = "Enter: save | Tab/Shift+Tab: complete +project @context key: | Esc/Ctrl+C: cancel"
+
+	// Conflict prompt
+	ConflictTitle    = "⚠️  File changed on disk"
+	ConflictModeHelp = "m: keep mine | t: keep theirs | b: keep both | Esc: discard my changes"
 
 	// Help text
-	HelpFilterPane      = "?: help | j/k: navigate | Enter: select filter & move to tasks | Tab/h/l: switch panes | a: add | q: quit"
-	HelpTaskPane        = "?: help | j/k: navigate | Enter: toggle completion | e: edit | p: priority toggle | t: toggle due today | d: delete | y: copy task | Tab/h/l: switch panes | a: add | q: quit"
-	HelpDeletedTaskPane = "?: help | j/k: navigate | r: restore task | y: copy task | Tab/h/l: switch panes | a: add | q: quit"
+	HelpFilterPane        = "?: help | j/k: navigate | Enter: select filter & move to tasks | /: search | :: query | Tab/h/l: switch panes | a: add | q: quit"
+	HelpTaskPane          = "?: help | j/k: navigate | Enter: toggle completion | e/E: edit | p: priority toggle | t: toggle due today | D: defer | d: delete | u/ctrl+r: undo/redo | y: copy task | v/V: select | s: sort | /: search | n/N: next/prev match | Tab/h/l: switch panes | a: add | q: quit"
+	HelpVisualMode        = "j/k: extend | V: mark task | Enter: complete | d: delete | p: priority | t: due today | D: defer | r: restore | y: copy | c/+/@: edit tags & due | v/Esc: exit"
+	HelpDeletedTaskPane   = "?: help | j/k: navigate | r: restore task | y: copy task | Tab/h/l: switch panes | a: add | q: quit"
+	HelpCompletedTaskPane = "?: help | j/k: navigate | r: restore task | A: archive to done.txt | y: copy task | Tab/h/l: switch panes | a: add | q: quit"
 
 	// Panel titles
 	FilterPaneTitle = "Workspaces"
@@ -124,11 +160,46 @@ const (
 	DefaultMinLeftPaneWidth  = 18
 	DefaultMinRightPaneWidth = 28
 
+	// Number of days the D key defers a task by
+	DefaultDeferDays = 1
+
+	// First day of the week
+	DefaultWeekStart = "sunday"
+
+	// Number of tasks listed in the Next filter
+	DefaultNextCount = 10
+
+	// Backup retention default values
+	DefaultBackupMaxCount   = 20
+	DefaultBackupMaxAgeDays = 30
+
 	// File permissions
 	DefaultConfigDirMode = 0755
 	DefaultFileDirMode   = 0755
 )
 
+// ===============================
+// File Lock Constants
+// ===============================
+
+const (
+	// How long a save from the UI waits for the file lock before retrying in the background
+	uiLockTimeout = 200 * time.Millisecond
+
+	// Interval and number of background retries while another instance holds the lock
+	lockRetryInterval = time.Second
+	maxLockRetries    = 10
+)
+
+// ===============================
+// Undo History Constants
+// ===============================
+
+const (
+	// Number of changes that can be undone
+	maxUndoHistory = 100
+)
+
 // ===============================
 // Other Constants
 // ===============================
@@ -147,11 +218,12 @@ const (
 
 // よく使用されるキー文字列定数
 const (
-	ctrlCKey = "ctrl+c"
-	escKey   = "esc"
-	enterKey = "enter"
-	tabKey   = "tab"
-	spaceKey = " "
+	ctrlCKey    = "ctrl+c"
+	escKey      = "esc"
+	enterKey    = "enter"
+	tabKey      = "tab"
+	shiftTabKey = "shift+tab"
+	spaceKey    = " "
 
 	// Direction keys
 	upKey    = "up"
@@ -176,6 +248,32 @@ const (
 	tKey = "t"
 	rKey = "r"
 	yKey = "y"
+	mKey = "m"
+	bKey = "b"
+	AKey = "A"
+	uKey = "u"
+	DKey = "D"
+	nKey = "n"
+	NKey = "N"
+	sKey = "s"
+	EKey = "E"
+	cKey = "c"
+	vKey = "v"
+	VKey = "V"
+
+	// Keys that open the bulk edit prompt with a project or context
+	plusKey = "+"
+	atKey   = "@"
+
+	// Undo/redo keys
+	ctrlRKey = "ctrl+r"
+
+	// Key that opens the filtered tasks in the external editor
+	ctrlEKey = "ctrl+e"
+
+	// Search and query prompt keys
+	slashKey = "/"
+	colonKey = ":"
 
 	// Help key
 	helpKey = "?"
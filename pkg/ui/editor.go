@@ -0,0 +1,185 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	todotxt "github.com/1set/todotxt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yuucu/todotui/pkg/domain"
+	"github.com/yuucu/todotui/pkg/logger"
+	"github.com/yuucu/todotui/pkg/todo"
+)
+
+// Editor used when neither $VISUAL nor $EDITOR is set
+const defaultEditor = "vi"
+
+// editorSession is a temporary file of tasks opened in the external editor
+type editorSession struct {
+	path  string          // Temporary file, removed when the editor exits
+	ids   []domain.TaskID // Tasks written to the file, one per line
+	lines []string        // Text of the tasks as written
+}
+
+// editorCommand returns the editor command line from $VISUAL or $EDITOR
+func editorCommand() []string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(name)); len(fields) > 0 {
+			return fields
+		}
+	}
+	return []string{defaultEditor}
+}
+
+// newEditorSession writes tasks to a temporary file, one per line
+func newEditorSession(tasks domain.Tasks) (*editorSession, error) {
+	session := &editorSession{}
+	for _, task := range tasks {
+		session.ids = append(session.ids, task.ID())
+		session.lines = append(session.lines, task.String())
+	}
+
+	file, err := os.CreateTemp("", "todotui-*.txt")
+	if err != nil {
+		return nil, err
+	}
+	session.path = file.Name()
+
+	content := strings.Join(session.lines, "\n") + "\n"
+	if _, writeErr := file.WriteString(content); writeErr != nil {
+		file.Close()
+		os.Remove(session.path)
+		return nil, writeErr
+	}
+	if closeErr := file.Close(); closeErr != nil {
+		os.Remove(session.path)
+		return nil, closeErr
+	}
+	return session, nil
+}
+
+// editInEditor opens tasks in the external editor. The changes are applied
+// when the editor exits: changed lines edit their task, removed lines delete
+// it and new lines are added as new tasks.
+func (m *Model) editInEditor(tasks domain.Tasks) tea.Cmd {
+	// Archived tasks no longer exist in the todo file and cannot be changed
+	tasks = tasks.Filter(func(task domain.Task, _ int) bool {
+		return !m.isArchivedTask(task)
+	})
+
+	session, err := newEditorSession(tasks)
+	if err != nil {
+		return m.setStatusMessage("❌ Failed to create temporary file: "+err.Error(), 3*time.Second)
+	}
+
+	args := editorCommand()
+	cmd := exec.Command(args[0], append(args[1:], session.path)...) //nolint:gosec // The editor is chosen by the user
+	logger.Debug("Opening editor", "command", args, "file", session.path, "tasks", len(session.ids))
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return EditorFinishedMsg{session: session, err: err}
+	})
+}
+
+// finishEditing applies the changes made in the external editor
+func (m *Model) finishEditing(msg EditorFinishedMsg) tea.Cmd {
+	session := msg.session
+	defer os.Remove(session.path)
+
+	if msg.err != nil {
+		return m.setStatusMessage("❌ Editor failed: "+msg.err.Error(), 3*time.Second)
+	}
+	content, err := os.ReadFile(session.path)
+	if err != nil {
+		return m.setStatusMessage("❌ Failed to read edited tasks: "+err.Error(), 3*time.Second)
+	}
+
+	// Blank lines are ignored, and date expressions become ISO dates as in the input box
+	var edited []string
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		normalized, dateErr := m.appConfig.Calendar().NormalizeDates(line, time.Now())
+		if dateErr != nil {
+			return m.setStatusMessage("❌ "+dateErr.Error(), 3*time.Second)
+		}
+		edited = append(edited, normalized)
+	}
+
+	changes := todo.DiffLines(session.lines, edited)
+	if len(changes) == 0 {
+		return m.setStatusMessage("✏️ No changes", 2*time.Second)
+	}
+
+	// 編集・追加された行が全て解析できる場合だけ変更する。途中で止めると一部の変更だけが保存される
+	var added []*todotxt.Task
+	for _, c := range changes {
+		if c.New < 0 {
+			continue
+		}
+		task, parseErr := todotxt.ParseTask(edited[c.New])
+		if parseErr != nil {
+			return m.setStatusMessage(fmt.Sprintf("❌ Failed to parse %q, nothing changed", edited[c.New]), 3*time.Second)
+		}
+		if c.Old < 0 {
+			added = append(added, task)
+		}
+	}
+
+	m.beginGroup()
+	defer m.endGroup()
+
+	var editedCount, deletedCount, skipped int
+	var updateErr error
+	for _, c := range changes {
+		if c.Old < 0 {
+			continue
+		}
+		id := session.ids[c.Old]
+		if c.New < 0 {
+			updateErr = m.updateTask("Delete", id, func(task *domain.Task) error {
+				return task.SoftDelete(time.Now())
+			})
+			if updateErr == nil {
+				deletedCount++
+			}
+		} else {
+			text := edited[c.New]
+			updateErr = m.updateTask("Edit", id, func(task *domain.Task) error {
+				return task.SetText(text)
+			})
+			if updateErr == nil {
+				editedCount++
+			}
+		}
+		if errors.Is(updateErr, errTaskNotFound) {
+			// The file was reloaded and the task changed or disappeared while editing
+			skipped++
+			updateErr = nil
+		}
+		if updateErr != nil {
+			break
+		}
+	}
+	if updateErr == nil {
+		for _, task := range added {
+			m.addTask("Add", task)
+		}
+	}
+
+	var message tea.Cmd
+	switch {
+	case updateErr != nil:
+		message = m.setStatusMessage("❌ Failed to update task: "+updateErr.Error(), 3*time.Second)
+	case skipped > 0:
+		message = m.setStatusMessage(fmt.Sprintf("⚠️ %s changed on disk while editing and were skipped", countTasks(skipped)), 3*time.Second)
+	default:
+		message = m.setStatusMessage(fmt.Sprintf("✅ %d edited, %d added, %d deleted", editedCount, len(added), deletedCount), 2*time.Second)
+	}
+	return tea.Batch(message, m.saveAndRefresh())
+}
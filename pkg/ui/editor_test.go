@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/yuucu/todotui/internal/testutil"
+)
+
+func TestEditorCommand(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "")
+	if got := editorCommand(); !slices.Equal(got, []string{defaultEditor}) {
+		t.Errorf("editorCommand() = %v, expected the default editor", got)
+	}
+
+	t.Setenv("EDITOR", "code --wait")
+	if got := editorCommand(); !slices.Equal(got, []string{"code", "--wait"}) {
+		t.Errorf("editorCommand() = %v", got)
+	}
+
+	// $VISUAL が優先される
+	t.Setenv("VISUAL", "nvim")
+	if got := editorCommand(); !slices.Equal(got, []string{"nvim"}) {
+		t.Errorf("editorCommand() = %v", got)
+	}
+}
+
+// editInTest writes the filtered tasks to an editor file, replaces its
+// content as an editor would and applies the result
+func editInTest(t *testing.T, m *Model, content string, editorErr error) {
+	t.Helper()
+	session, err := newEditorSession(m.filteredTasks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(session.path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	m.Update(EditorFinishedMsg{session: session, err: editorErr})
+	if _, err := os.Stat(session.path); !os.IsNotExist(err) {
+		t.Errorf("the temporary file should be removed, stat error = %v", err)
+	}
+}
+
+func TestModel_EditInEditor(t *testing.T) {
+	model, todoPath := newTestModelWithFile(t, "Task A +work\nTask B\nx 2025-01-14 Done task\nTask C\n")
+	selectFilter(t, model, FilterAllTasks)
+
+	session, err := newEditorSession(model.filteredTasks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	written, _ := os.ReadFile(session.path)
+	os.Remove(session.path)
+	if string(written) != "Task A +work\nTask B\nTask C\n" {
+		t.Errorf("editor file = %q, expected the filtered tasks", written)
+	}
+
+	// 変更した行は編集、消した行は削除、新しい行は追加になる
+	editInTest(t, model, "(A) Task A +work\n\nTask C\nTask D due:2025-02-01\n", nil)
+	lines := strings.Split(strings.TrimSpace(testutil.ReadFile(t, todoPath)), "\n")
+	expected := []string{"(A) Task A +work", "Task B deleted_at:", "x 2025-01-14 Done task", "Task C", "Task D due:2025-02-01"}
+	if len(lines) != len(expected) {
+		t.Fatalf("file content = %q", lines)
+	}
+	for i, line := range lines {
+		if !strings.HasPrefix(line, expected[i]) {
+			t.Errorf("line %d = %q, expected %q", i+1, line, expected[i])
+		}
+	}
+
+	// 変更全体が一度の u で元に戻る
+	model.Update(undoKeyMsg)
+	if got := testutil.ReadFile(t, todoPath); got != "Task A +work\nTask B\nx 2025-01-14 Done task\nTask C\n" {
+		t.Errorf("file content after undo = %q", got)
+	}
+}
+
+func TestModel_EditInEditorFailures(t *testing.T) {
+	content := "Task A\nTask B\n"
+	model, todoPath := newTestModelWithFile(t, content)
+	selectFilter(t, model, FilterAllTasks)
+
+	// エディタが異常終了した場合は何も変更しない
+	editInTest(t, model, "Changed\n", errors.New("exit status 1"))
+	if got := testutil.ReadFile(t, todoPath); got != content {
+		t.Errorf("file content = %q, expected no change", got)
+	}
+	if !strings.Contains(model.statusMessage, "Editor failed") {
+		t.Errorf("status message = %q", model.statusMessage)
+	}
+
+	editInTest(t, model, "Task A due:someday\nTask B\n", nil)
+	if got := testutil.ReadFile(t, todoPath); got != content {
+		t.Errorf("file content = %q, expected no change for an invalid date", got)
+	}
+
+	// 解析できない行が一つでもあれば、他の行の編集も反映しない
+	editInTest(t, model, "Task A edited\nx 2025-13-45 Task B\n", nil)
+	if got := testutil.ReadFile(t, todoPath); got != content {
+		t.Errorf("file content = %q, expected no change when a line does not parse", got)
+	}
+	first := model.tasks.Get(0)
+	if got := first.String(); got != "Task A" {
+		t.Errorf("first task = %q, expected the edit not to be applied", got)
+	}
+
+	editInTest(t, model, content, nil)
+	if !strings.Contains(model.statusMessage, "No changes") {
+		t.Errorf("status message = %q", model.statusMessage)
+	}
+}
@@ -28,16 +28,29 @@ func (m *Model) refreshFilterList() {
 
 	var filters []FilterData
 
+	// The query typed at the : prompt comes first
+	if queryFilter := m.getQueryFilter(); queryFilter != nil {
+		filters = append(filters, *queryFilter)
+	}
+
+	// The most urgent tasks
+	if nextFilter := m.getNextFilter(); nextFilter != nil {
+		filters = append(filters, *nextFilter)
+	}
+
 	// Add time-based filters
 	filters = append(filters, m.getTimeBasedFilters()...)
 
+	// Tasks whose threshold date has not arrived are listed under "Upcoming" only
+	now := time.Now()
+
 	// Always add "All Tasks" filter
 	allTasksFilter := FilterData{
 		name: FilterAllTasks,
 		filterFn: func(tasks domain.Tasks) domain.Tasks {
 			return tasks.Filter(func(task domain.Task, _ int) bool {
 				// Show only incomplete, non-deleted tasks
-				return !task.IsDeleted() && !task.IsCompleted()
+				return !task.IsDeleted() && !task.IsCompleted() && !task.IsUpcoming(now)
 			})
 		},
 	}
@@ -49,12 +62,15 @@ func (m *Model) refreshFilterList() {
 		filterFn: func(tasks domain.Tasks) domain.Tasks {
 			return tasks.Filter(func(task domain.Task, _ int) bool {
 				// Show only incomplete, non-deleted tasks with no projects
-				return !task.IsDeleted() && !task.IsCompleted() && len(task.Projects()) == 0
+				return !task.IsDeleted() && !task.IsCompleted() && !task.IsUpcoming(now) && len(task.Projects()) == 0
 			})
 		},
 	}
 	filters = append(filters, noProjectFilter)
 
+	// Add saved filters from the configuration
+	filters = append(filters, m.getSavedFilters()...)
+
 	// Add project filters if any exist
 	projects := m.getUniqueProjects()
 	if len(projects) > 0 {
@@ -71,7 +87,7 @@ func (m *Model) refreshFilterList() {
 					return func(tasks domain.Tasks) domain.Tasks {
 						return tasks.Filter(func(task domain.Task, _ int) bool {
 							// Show only incomplete, non-deleted tasks with the specified project
-							return !task.IsDeleted() && !task.IsCompleted() && lo.Contains(task.Projects(), p)
+							return !task.IsDeleted() && !task.IsCompleted() && !task.IsUpcoming(now) && lo.Contains(task.Projects(), p)
 						})
 					}
 				}(project),
@@ -95,7 +111,7 @@ func (m *Model) refreshFilterList() {
 					return func(tasks domain.Tasks) domain.Tasks {
 						return tasks.Filter(func(task domain.Task, _ int) bool {
 							// Show only incomplete, non-deleted tasks with the specified context
-							return !task.IsDeleted() && !task.IsCompleted() && lo.Contains(task.Contexts(), c)
+							return !task.IsDeleted() && !task.IsCompleted() && !task.IsUpcoming(now) && lo.Contains(task.Contexts(), c)
 						})
 					}
 				}(context),
@@ -106,7 +122,10 @@ func (m *Model) refreshFilterList() {
 	filters = append(filters, FilterData{
 		name: FilterCompletedTasks,
 		filterFn: func(tasks domain.Tasks) domain.Tasks {
-			return tasks.Filter(func(task domain.Task, _ int) bool {
+			// Archived tasks from done.txt are listed after those still in the todo file
+			completed := make(domain.Tasks, 0, len(tasks)+len(m.doneTasks))
+			completed = append(append(completed, tasks...), m.doneTasks...)
+			return completed.Filter(func(task domain.Task, _ int) bool {
 				// Show all completed tasks (not deleted)
 				return task.IsCompleted() && !task.IsDeleted()
 			})
@@ -157,7 +176,7 @@ func (m *Model) refreshFilterList() {
 	// switch to "All Tasks" filter
 	if !foundPreviousFilter && currentFilterName != "" {
 		// Check if it was a time-based filter that got removed
-		timeBasedFilters := []string{"Due Today", "This Week", "Overdue"}
+		timeBasedFilters := []string{FilterNext, "Due Today", "This Week", "Overdue", FilterUpcoming}
 		wasTimeBasedFilter := lo.Contains(timeBasedFilters, currentFilterName)
 
 		if wasTimeBasedFilter {
@@ -200,23 +219,34 @@ func (m *Model) refreshTaskList() {
 			m.filters[m.filterList.selected].name == FilterDeletedTasks
 		isNoProjectFilter := m.filterList.selected < len(m.filters) &&
 			m.filters[m.filterList.selected].name == FilterNoProject
+		// Query and saved filters show exactly what matches, even if nothing does
+		isQueryFilter := m.filterList.selected < len(m.filters) &&
+			m.filters[m.filterList.selected].query != nil
 
-		if !isDeletedTasksFilter && !isNoProjectFilter {
+		if !isDeletedTasksFilter && !isNoProjectFilter && !isQueryFilter {
 			// Default to all incomplete tasks (only for non-deleted and non-no-project task filters)
+			now := time.Now()
 			filteredTasks = m.tasks.Filter(func(task domain.Task, _ int) bool {
-				return !task.IsDeleted() && !task.IsCompleted()
+				return !task.IsDeleted() && !task.IsCompleted() && !task.IsUpcoming(now)
 			})
 		}
 	}
 
 	// ソート処理をdomainの新しいTasks型に委譲
-	sortedTasks := filteredTasks.SortByCompletionStatus()
-	filteredTasks = sortedTasks
+	filteredTasks = m.sortTasks(filteredTasks)
+
+	// Narrow the list down to the tasks matching the / search
+	filteredTasks = m.searchTasks(filteredTasks)
 
 	// Convert tasks to display strings and track completion status
 	var items []string
 	var completedItems []bool
 	var checkboxColors []lipgloss.Color
+	var highlights [][]int
+
+	// The urgency score is shown after the tags when enabled
+	showUrgency := m.appConfig.Urgency.Show
+	urgency := m.urgencyFn(time.Now())
 
 	taskList := filteredTasks.ToTaskList()
 	for i := range taskList {
@@ -257,9 +287,12 @@ func (m *Model) refreshTaskList() {
 		display := task.Todo
 
 		// For both completed and active tasks, keep plain text and let renderTaskItem handle all styling
+		var prefix string
 		if task.HasPriority() {
-			display = fmt.Sprintf("(%s) ", task.Priority) + display
+			prefix = fmt.Sprintf("(%s) ", task.Priority)
+			display = prefix + display
 		}
+		highlights = append(highlights, m.searchHighlights(&filteredTasks[i], prefix))
 
 		var tags []string
 		for _, project := range task.Projects {
@@ -268,11 +301,18 @@ func (m *Model) refreshTaskList() {
 		for _, context := range task.Contexts {
 			tags = append(tags, "@"+context)
 		}
+		if threshold, ok := task.AdditionalTags[domain.TaskFieldThreshold]; ok {
+			tags = append(tags, domain.TaskFieldThresholdPrefix+threshold)
+		}
 		if task.HasDueDate() {
 			dueDate := task.DueDate.Format(domain.DateFormat)
 			tags = append(tags, domain.TaskFieldDuePrefix+dueDate)
 		}
 
+		if showUrgency {
+			tags = append(tags, formatUrgency(urgency(&filteredTasks[i])))
+		}
+
 		if len(tags) > 0 {
 			display += " " + strings.Join(tags, " ")
 		}
@@ -286,6 +326,10 @@ func (m *Model) refreshTaskList() {
 	m.taskList.SetCompletedItems(completedItems)
 	// Set checkbox colors to match due date colors
 	m.taskList.SetCheckboxColors(checkboxColors)
+	// Highlight the characters matched by the search
+	m.taskList.SetHighlights(highlights)
+	// Keep showing the tasks marked in visual mode
+	m.updateMarks()
 }
 
 // getUniqueProjects returns sorted unique project names
@@ -349,7 +393,11 @@ func (m *Model) getStatusInfo() string {
 	if m.filterList.selected < len(m.filters) {
 		filterName := m.filters[m.filterList.selected].name
 		// Clean up filter name for display
-		if strings.HasPrefix(filterName, "  +") || strings.HasPrefix(filterName, "  @") {
+		if strings.HasPrefix(filterName, FilterQueryPrefix) {
+			// Queries may contain " (", so they are shown as they are
+			currentFilter = filterName
+		} else if strings.HasPrefix(filterName, "  ") {
+			// Project, context and saved filters
 			currentFilter = strings.TrimSpace(filterName)
 		} else if !strings.Contains(filterName, "─") {
 			currentFilter = strings.Split(filterName, " (")[0]
@@ -364,9 +412,14 @@ func (m *Model) getStatusInfo() string {
 	totalTasks := m.tasks.FilterActive().Len()
 	filteredCount := m.filteredTasks.Len()
 
+	// Show the search next to the filter it narrows down
+	if m.search != "" {
+		currentFilter += " " + SearchPrompt + m.search
+	}
+
 	// Icons and info
-	return fmt.Sprintf("🏷️  %s │ 📋 %d/%d │ 🕐 %s",
-		currentFilter, filteredCount, totalTasks, now)
+	return fmt.Sprintf("🏷️  %s │ ↕ %s │ 📋 %d/%d │ 🕐 %s",
+		currentFilter, m.sortName(), filteredCount, totalTasks, now)
 }
 
 // Helper function to create filters with count check
@@ -23,6 +23,10 @@ func (m *Model) getTimeBasedFilters() []FilterData {
 		filters = append(filters, *filter)
 	}
 
+	if filter := m.addFilterIfNotEmpty(FilterUpcoming, m.getUpcomingFilterFn()); filter != nil {
+		filters = append(filters, *filter)
+	}
+
 	return filters
 }
 
@@ -72,7 +76,7 @@ func (m *Model) getThisWeekFilterFn() func(domain.Tasks) domain.Tasks {
 				return false
 			}
 
-			return task.IsThisWeek(now)
+			return task.IsDueThisWeek(m.appConfig.Calendar(), now)
 		})
 	}
 }
@@ -96,3 +100,14 @@ func (m *Model) getOverdueFilterFn() func(domain.Tasks) domain.Tasks {
 		})
 	}
 }
+
+// getUpcomingFilterFn returns the filter function for tasks whose threshold date has not arrived yet
+func (m *Model) getUpcomingFilterFn() func(domain.Tasks) domain.Tasks {
+	return func(tasks domain.Tasks) domain.Tasks {
+		now := time.Now()
+		return tasks.Filter(func(task domain.Task, _ int) bool {
+			// IsUpcoming already skips deleted and completed tasks
+			return task.IsUpcoming(now)
+		})
+	}
+}
@@ -18,8 +18,13 @@ func (m *Model) initializeHelpContent() {
 				{"q / Ctrl+C", "Quit application"},
 				{"a", "Add new task"},
 				{"e", "Edit selected task"},
+				{"E", "Edit selected task in $EDITOR"},
+				{"Ctrl+E", "Edit all listed tasks in $EDITOR"},
 				{"d", "Delete selected task"},
 				{"r", "Restore deleted/completed task"},
+				{"u", "Undo last change"},
+				{"Ctrl+R", "Redo last undone change"},
+				{"A", "Archive completed tasks to done.txt"},
 			},
 		},
 		{
@@ -31,6 +36,10 @@ func (m *Model) initializeHelpContent() {
 				{"j / ↓", "Move down / Scroll down (in help)"},
 				{"k / ↑", "Move up / Scroll up (in help)"},
 				{"Enter", "Apply filter / Complete task"},
+				{"/", "Search tasks (fuzzy)"},
+				{"n / N", "Jump to next / previous search match"},
+				{":", "Filter tasks with a query"},
+				{"s", "Cycle sort order (file, priority, due, urgency, ...)"},
 			},
 		},
 		{
@@ -49,6 +58,20 @@ func (m *Model) initializeHelpContent() {
 				{"y", "Copy task text to clipboard"},
 				{"p", "Cycle task priority"},
 				{"t", "Toggle due date to today"},
+				{"D", "Defer task by N days (t:)"},
+				{"v", "Mark a range of tasks (visual mode)"},
+				{"V", "Mark single tasks (visual mode)"},
+			},
+		},
+		{
+			Category: "Visual Mode",
+			Items: []HelpItem{
+				{"j / k / g / G", "Extend the range"},
+				{"V", "Mark/unmark the task, or keep the range"},
+				{"Enter d p t D r y", "Complete, delete, ... all marked tasks"},
+				{"c / + / @", "Add or remove projects, contexts, due"},
+				{"v / Esc", "Leave visual mode"},
+				{"u", "Undo the whole bulk change"},
 			},
 		},
 		{
@@ -56,6 +79,36 @@ func (m *Model) initializeHelpContent() {
 			Items: []HelpItem{
 				{"Esc / Ctrl+C", "Cancel editing"},
 				{"Enter / Ctrl+S", "Save task"},
+				{"Tab / Shift+Tab", "Complete +project, @context, key:, due:"},
+			},
+		},
+		{
+			Category: "Search Prompt",
+			Items: []HelpItem{
+				{"rprt +wk", "Letters in order, in the text, projects or contexts"},
+				{"↑ / ↓", "Move through the matches"},
+				{"Enter / Esc", "Keep the search / restore the previous one"},
+				{"Esc (after Enter)", "Clear the search"},
+			},
+		},
+		{
+			Category: "Query Prompt",
+			Items: []HelpItem{
+				{"+work @office", "Tasks with the project and context"},
+				{"pri:A..B due:<=+7d", "Priority range, due within a week"},
+				{"-status:done", "Leave out completed tasks"},
+				{"AND / OR / NOT ( )", "Combine terms"},
+				{"Enter / Esc", "Keep the query / restore the previous one"},
+				{"Empty query", "Remove the query"},
+			},
+		},
+		{
+			Category: "Conflict Prompt",
+			Items: []HelpItem{
+				{"m", "Keep your version of conflicting lines"},
+				{"t", "Keep the version on disk"},
+				{"b", "Keep both versions"},
+				{"Esc", "Discard your unsaved changes"},
 			},
 		},
 	}
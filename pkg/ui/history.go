@@ -0,0 +1,222 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	todotxt "github.com/1set/todotxt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yuucu/todotui/pkg/domain"
+	"github.com/yuucu/todotui/pkg/logger"
+	"github.com/yuucu/todotui/pkg/todo"
+)
+
+// change records one task mutation so that it can be undone and redone.
+// The task is found again by its text next to the line it is on, which keeps
+// working after the file is saved or reloaded.
+type change struct {
+	action string // Shown in the status bar, e.g. "Complete"
+	line   int    // Line of the task in the file, 0 for a task that was not saved yet
+	before string // Task text before the change, empty for an added task
+	after  string // Task text after the change
+	group  int    // Changes of one bulk operation share a nonzero group and are undone together
+}
+
+// history holds the changes that can be undone and redone
+type history struct {
+	undo      []change
+	redo      []change
+	lastGroup int // Last group given to a bulk operation
+}
+
+// popGroup removes the last change from stack together with the changes of
+// the same group before it, and returns them in the order they were made
+func popGroup(stack *[]change) []change {
+	changes := *stack
+	if len(changes) == 0 {
+		return nil
+	}
+	start := len(changes) - 1
+	if group := changes[start].group; group != 0 {
+		for start > 0 && changes[start-1].group == group {
+			start--
+		}
+	}
+	popped := append([]change(nil), changes[start:]...)
+	*stack = changes[:start]
+	return popped
+}
+
+// record adds a change made by the user; anything undone before can no longer be redone
+func (h *history) record(c change) {
+	h.undo = append(h.undo, c)
+	if len(h.undo) > maxUndoHistory {
+		h.undo = h.undo[len(h.undo)-maxUndoHistory:]
+	}
+	h.redo = nil
+}
+
+// translateLines follows the recorded tasks to the lines they moved to
+func (h *history) translateLines(translate func(int) (int, bool)) {
+	for _, stack := range [][]change{h.undo, h.redo} {
+		for i := range stack {
+			if stack[i].line <= 0 {
+				continue
+			}
+			if line, ok := translate(stack[i].line); ok {
+				stack[i].line = line
+			}
+		}
+	}
+}
+
+// beginGroup starts a bulk operation; the changes recorded until endGroup
+// are undone and redone as one
+func (m *Model) beginGroup() {
+	m.history.lastGroup++
+	m.changeGroup = m.history.lastGroup
+}
+
+// endGroup ends the bulk operation started by beginGroup
+func (m *Model) endGroup() {
+	m.changeGroup = 0
+}
+
+// recordChange remembers a change made by the user for undo and for the journal
+func (m *Model) recordChange(c change) {
+	c.group = m.changeGroup
+	m.history.record(c)
+	m.journal(c.action, c.line, c.before, c.after)
+}
+
+// journal queues a journal entry; it is written once the change is saved
+func (m *Model) journal(kind string, line int, before, after string) {
+	if !m.appConfig.Journal.Enabled {
+		return
+	}
+	m.pendingJournal = append(m.pendingJournal, todo.NewJournalEntry(m.todoFilePath, kind, line, before, after))
+}
+
+// flushJournal writes the queued journal entries after a successful save.
+// Changes that never reach the file are dropped with keep set to false.
+func (m *Model) flushJournal(keep bool) {
+	entries := m.pendingJournal
+	m.pendingJournal = nil
+	if !keep {
+		return
+	}
+	if err := todo.AppendJournal(m.todoFilePath, m.appConfig.Journal.Options(), entries...); err != nil {
+		// The journal is an audit trail; failing to write it must not lose the save
+		logger.Warn("Failed to write journal", "file", m.todoFilePath, "error", err)
+	}
+}
+
+// setTasks replaces the task list with one returned by the store.
+// Lines recorded against the previous list are translated to the new one.
+func (m *Model) setTasks(taskList todotxt.TaskList) {
+	m.history.translateLines(m.store.TranslateLine)
+	// Keep following the task being edited if lines above it were added or removed
+	if m.editingTask != nil {
+		m.editingTask = m.translateID(*m.editingTask)
+	}
+	m.tasks = domain.NewTasks(taskList)
+}
+
+// undo reverts the most recent change, or bulk operation, and saves the result
+func (m *Model) undo() tea.Cmd {
+	changes := popGroup(&m.history.undo)
+	if len(changes) == 0 {
+		return m.setStatusMessage("↩️ Nothing to undo", 2*time.Second)
+	}
+
+	// 後に行った変更から順に戻す
+	for i := len(changes) - 1; i >= 0; i-- {
+		c := changes[i]
+		if err := m.applyChange(c.after, c.before, c.line); err != nil {
+			// Changes already undone stay undone and can be redone
+			m.history.redo = append(m.history.redo, changes[i+1:]...)
+			return m.undoFailed("undo", c, err, i < len(changes)-1)
+		}
+		m.journal("Undo "+c.action, c.line, c.after, c.before)
+	}
+	m.history.redo = append(m.history.redo, changes...)
+	return tea.Batch(
+		m.setStatusMessage("↩️ Undone: "+describeChanges(changes), 3*time.Second),
+		m.saveAndRefresh(),
+	)
+}
+
+// redo applies the most recently undone change, or bulk operation, again and saves the result
+func (m *Model) redo() tea.Cmd {
+	changes := popGroup(&m.history.redo)
+	if len(changes) == 0 {
+		return m.setStatusMessage("↪️ Nothing to redo", 2*time.Second)
+	}
+
+	for i, c := range changes {
+		if err := m.applyChange(c.before, c.after, c.line); err != nil {
+			// Changes already redone stay redone and can be undone
+			m.history.undo = append(m.history.undo, changes[:i]...)
+			return m.undoFailed("redo", c, err, i > 0)
+		}
+		m.journal("Redo "+c.action, c.line, c.before, c.after)
+	}
+	m.history.undo = append(m.history.undo, changes...)
+	return tea.Batch(
+		m.setStatusMessage("↪️ Redone: "+describeChanges(changes), 3*time.Second),
+		m.saveAndRefresh(),
+	)
+}
+
+// undoFailed reports a change that could not be undone or redone.
+// Changes of the same bulk operation applied before it are saved.
+func (m *Model) undoFailed(verb string, c change, err error, partial bool) tea.Cmd {
+	message := m.setStatusMessage(fmt.Sprintf("❌ Cannot %s %s: %v", verb, c.action, err), 3*time.Second)
+	if !partial {
+		return message
+	}
+	return tea.Batch(message, m.saveAndRefresh())
+}
+
+// describeChanges describes the changes undone or redone for the status bar
+func describeChanges(changes []change) string {
+	if len(changes) == 1 {
+		return fmt.Sprintf("%s %q", changes[0].action, changeText(changes[0]))
+	}
+	return fmt.Sprintf("%s (%d tasks)", changes[0].action, len(changes))
+}
+
+// applyChange replaces the task whose text is from with to.
+// An empty from adds a new task and an empty to removes the task.
+func (m *Model) applyChange(from, to string, line int) error {
+	if from == "" {
+		task, err := todotxt.ParseTask(to)
+		if err != nil {
+			return err
+		}
+		taskList := m.tasks.ToTaskList()
+		taskList = append(taskList, *task)
+		m.tasks = domain.NewTasks(taskList)
+		return nil
+	}
+
+	index, found := m.tasks.IndexOfText(from, line)
+	if !found {
+		logger.Warn("Task to undo or redo not found", "line", line, "text", from)
+		return errTaskNotFound
+	}
+	if to == "" {
+		m.tasks = m.tasks.Remove(index)
+		return nil
+	}
+	task := m.tasks.Get(index)
+	return task.SetText(to)
+}
+
+// changeText returns the text describing the task a change applies to
+func changeText(c change) string {
+	if c.after != "" {
+		return c.after
+	}
+	return c.before
+}
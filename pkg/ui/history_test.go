@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yuucu/todotui/internal/testutil"
+	"github.com/yuucu/todotui/pkg/todo"
+)
+
+var (
+	undoKeyMsg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(uKey)}
+	redoKeyMsg = tea.KeyMsg{Type: tea.KeyCtrlR}
+)
+
+func TestModel_UndoRedo(t *testing.T) {
+	model, todoPath := newTestModelWithFile(t, "Task A\nTask B\n")
+	selectFilter(t, model, FilterAllTasks)
+	model.activePane = paneTask
+	model.taskList.selected = 1
+
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := testutil.ReadFile(t, todoPath); !strings.HasPrefix(got, "Task A\nx ") {
+		t.Fatalf("file content after completing = %q", got)
+	}
+
+	model.Update(undoKeyMsg)
+	if got := testutil.ReadFile(t, todoPath); got != "Task A\nTask B\n" {
+		t.Errorf("file content after undo = %q", got)
+	}
+	if !strings.Contains(model.statusMessage, "Undone: Complete") {
+		t.Errorf("status message = %q, expected the undone action", model.statusMessage)
+	}
+
+	model.Update(redoKeyMsg)
+	if got := testutil.ReadFile(t, todoPath); !strings.HasPrefix(got, "Task A\nx ") {
+		t.Errorf("file content after redo = %q", got)
+	}
+	if !strings.Contains(model.statusMessage, "Redone: Complete") {
+		t.Errorf("status message = %q, expected the redone action", model.statusMessage)
+	}
+}
+
+func TestModel_UndoAdd(t *testing.T) {
+	model, todoPath := newTestModelWithFile(t, "Task A\n")
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(aKey)})
+	model.textInput.SetValue("Task B")
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := testutil.ReadFile(t, todoPath); got != "Task A\nTask B\n" {
+		t.Fatalf("file content after add = %q", got)
+	}
+
+	model.Update(undoKeyMsg)
+	if got := testutil.ReadFile(t, todoPath); got != "Task A\n" {
+		t.Errorf("file content after undo = %q", got)
+	}
+
+	model.Update(redoKeyMsg)
+	if got := testutil.ReadFile(t, todoPath); got != "Task A\nTask B\n" {
+		t.Errorf("file content after redo = %q", got)
+	}
+}
+
+func TestModel_UndoAfterReload(t *testing.T) {
+	model, todoPath := newTestModelWithFile(t, "(A) Task A\nTask A\n")
+	selectFilter(t, model, FilterAllTasks)
+	model.activePane = paneTask
+	model.taskList.selected = 1
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(pKey)})
+	if got := testutil.ReadFile(t, todoPath); got != "(A) Task A\n(A) Task A\n" {
+		t.Fatalf("file content after priority change = %q", got)
+	}
+
+	// 別のプログラムが先頭に行を追加し、再読み込みされる
+	external := "Task Z\n" + testutil.ReadFile(t, todoPath)
+	testutil.WriteExternally(t, todoPath, external)
+	model.Update(TaskListChangedMsg{})
+
+	model.Update(undoKeyMsg)
+	if got := testutil.ReadFile(t, todoPath); got != "Task Z\n(A) Task A\nTask A\n" {
+		t.Errorf("file content after undo = %q", got)
+	}
+}
+
+func TestModel_NothingToUndo(t *testing.T) {
+	model, _ := newTestModelWithFile(t, "Task A\n")
+
+	model.Update(undoKeyMsg)
+	if !strings.Contains(model.statusMessage, "Nothing to undo") {
+		t.Errorf("status message = %q", model.statusMessage)
+	}
+	model.Update(redoKeyMsg)
+	if !strings.Contains(model.statusMessage, "Nothing to redo") {
+		t.Errorf("status message = %q", model.statusMessage)
+	}
+}
+
+func TestModel_JournalsChanges(t *testing.T) {
+	model, todoPath := newTestModelWithFile(t, "Task A\n")
+	selectFilter(t, model, FilterAllTasks)
+	model.activePane = paneTask
+
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	model.Update(undoKeyMsg)
+
+	entries, err := todo.ReadJournal(todoPath, model.appConfig.Journal.Options())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("journal has %d entries, expected 2: %+v", len(entries), entries)
+	}
+	if entries[0].Kind != "Complete" || entries[0].Before != "Task A" || !strings.HasPrefix(entries[0].After, "x ") {
+		t.Errorf("first entry = %+v", entries[0])
+	}
+	if entries[1].Kind != "Undo Complete" || entries[1].After != "Task A" {
+		t.Errorf("second entry = %+v", entries[1])
+	}
+}
@@ -3,6 +3,7 @@ package ui
 import (
 	"strings"
 	"time"
+	"unicode"
 
 	todotxt "github.com/1set/todotxt"
 	"github.com/charmbracelet/lipgloss"
@@ -12,6 +13,7 @@ import (
 // Constants for list rendering
 const (
 	selectionIndicator = "▶ "
+	markIndicator      = "▌ "
 	spacing            = "  "
 	checkboxCompleted  = "● "
 	checkboxIncomplete = "○ "
@@ -27,6 +29,8 @@ type SimpleList struct {
 	isTaskList     bool             // Whether this is a task list (affects rendering)
 	completedItems []bool           // Track which items are completed
 	checkboxColors []lipgloss.Color // Track checkbox colors for incomplete tasks
+	highlights     [][]int          // Rune positions of search matches in each item
+	markedItems    []bool           // Track which items are marked in visual mode
 }
 
 // SetTheme sets the theme for styling
@@ -49,6 +53,16 @@ func (l *SimpleList) SetCheckboxColors(colors []lipgloss.Color) {
 	l.checkboxColors = colors
 }
 
+// SetHighlights sets the rune positions of the characters to highlight in each item
+func (l *SimpleList) SetHighlights(highlights [][]int) {
+	l.highlights = highlights
+}
+
+// SetMarkedItems sets which items are marked in visual mode
+func (l *SimpleList) SetMarkedItems(marked []bool) {
+	l.markedItems = marked
+}
+
 func (l *SimpleList) SetItems(items []string) {
 	l.items = items
 	if l.selected >= len(items) {
@@ -176,11 +190,24 @@ func (l *SimpleList) renderTaskItem(item string, index int) string {
 		if index == l.selected {
 			return selectionIndicator + item
 		}
+		if index < len(l.markedItems) && l.markedItems[index] {
+			return markIndicator + item
+		}
 		return spacing + item
 	}
 
 	// Determine if this task is completed
 	isCompleted := index < len(l.completedItems) && l.completedItems[index]
+	isMarked := index < len(l.markedItems) && l.markedItems[index]
+
+	// Characters matched by the search
+	var matched map[int]bool
+	if index < len(l.highlights) {
+		matched = make(map[int]bool, len(l.highlights[index]))
+		for _, position := range l.highlights[index] {
+			matched[position] = true
+		}
+	}
 
 	// Create modern checkbox with circles (● / ○)
 	var checkbox string
@@ -224,10 +251,11 @@ func (l *SimpleList) renderTaskItem(item string, index int) string {
 				Foreground(l.theme.SelectionFg).
 				Strikethrough(true).
 				Bold(true)
-			content = contentStyle.Render(item)
+			content = l.renderMatches(item, 0, matched, contentStyle)
 		} else {
 			// For incomplete selected tasks: apply background highlighting while preserving component colors
-			content = l.styleActiveTaskContentWithBackground(item, l.theme.SelectionBg)
+			background := l.theme.SelectionBg
+			content = l.styleTaskContentHighlighted(item, &background, matched)
 		}
 
 		// Combine components: indicator + checkbox + highlighted content
@@ -241,30 +269,35 @@ func (l *SimpleList) renderTaskItem(item string, index int) string {
 		contentStyle := lipgloss.NewStyle().
 			Foreground(l.theme.TextMuted).
 			Strikethrough(true)
-		content = contentStyle.Render(item)
+		content = l.renderMatches(item, 0, matched, contentStyle)
 	} else {
 		// For active tasks, parse and style individual components
-		content = l.styleActiveTaskContent(item)
+		content = l.styleTaskContentHighlighted(item, nil, matched)
+	}
+
+	// Marked items are shown with a bar in place of the spacing
+	if isMarked {
+		indicator := lipgloss.NewStyle().
+			Foreground(l.theme.Primary).
+			Bold(true).
+			Render(markIndicator)
+		return indicator + checkbox + content
 	}
 
 	// Add spacing for non-selected items
 	return spacing + checkbox + content
 }
 
-// styleActiveTaskContent parses and styles components of an active task
-func (l *SimpleList) styleActiveTaskContent(item string) string {
-	return l.styleTaskContentInternal(item, nil)
-}
-
-// styleActiveTaskContentWithBackground parses and styles components of an active task with a background color
-func (l *SimpleList) styleActiveTaskContentWithBackground(item string, backgroundColor lipgloss.Color) string {
-	return l.styleTaskContentInternal(item, &backgroundColor)
-}
-
 // styleTaskContentInternal is the common implementation for styling task content
 func (l *SimpleList) styleTaskContentInternal(item string, backgroundColor *lipgloss.Color) string {
+	return l.styleTaskContentHighlighted(item, backgroundColor, nil)
+}
+
+// styleTaskContentHighlighted styles task content, highlighting the characters
+// at the rune positions in matched
+func (l *SimpleList) styleTaskContentHighlighted(item string, backgroundColor *lipgloss.Color, matched map[int]bool) string {
 	// Split the content to parse priority, todo text, and tags
-	parts := strings.Fields(item)
+	parts, offsets := fieldsWithOffsets(item)
 	if len(parts) == 0 {
 		return item
 	}
@@ -291,21 +324,28 @@ func (l *SimpleList) styleTaskContentInternal(item string, backgroundColor *lipg
 			default:
 				priorityStyle = priorityStyle.Foreground(l.theme.PriorityDefault)
 			}
-			styledParts = append(styledParts, priorityStyle.Render(part))
+			styledParts = append(styledParts, l.renderMatches(part, offsets[i], matched, priorityStyle))
 		} else if strings.HasPrefix(part, "+") {
 			// Project tag
 			projectStyle := lipgloss.NewStyle().Foreground(l.theme.Secondary)
 			if backgroundColor != nil {
 				projectStyle = projectStyle.Background(*backgroundColor).Bold(true)
 			}
-			styledParts = append(styledParts, projectStyle.Render(part))
+			styledParts = append(styledParts, l.renderMatches(part, offsets[i], matched, projectStyle))
 		} else if strings.HasPrefix(part, "@") {
 			// Context tag
 			contextStyle := lipgloss.NewStyle().Foreground(l.theme.Primary)
 			if backgroundColor != nil {
 				contextStyle = contextStyle.Background(*backgroundColor).Bold(true)
 			}
-			styledParts = append(styledParts, contextStyle.Render(part))
+			styledParts = append(styledParts, l.renderMatches(part, offsets[i], matched, contextStyle))
+		} else if strings.HasPrefix(part, UrgencyPrefix) {
+			// Urgency score
+			urgencyStyle := lipgloss.NewStyle().Foreground(l.theme.TextMuted)
+			if backgroundColor != nil {
+				urgencyStyle = urgencyStyle.Background(*backgroundColor)
+			}
+			styledParts = append(styledParts, l.renderMatches(part, offsets[i], matched, urgencyStyle))
 		} else if strings.HasPrefix(part, "due:") {
 			// Due date tag
 			dueStyle := lipgloss.NewStyle()
@@ -355,14 +395,14 @@ func (l *SimpleList) styleTaskContentInternal(item string, backgroundColor *lipg
 				}
 			}
 
-			styledParts = append(styledParts, dueStyle.Render(part))
+			styledParts = append(styledParts, l.renderMatches(part, offsets[i], matched, dueStyle))
 		} else {
 			// Regular text (todo content)
 			textStyle := lipgloss.NewStyle()
 			if backgroundColor != nil {
 				textStyle = textStyle.Background(*backgroundColor).Foreground(l.theme.SelectionFg).Bold(true)
 			}
-			styledParts = append(styledParts, textStyle.Render(part))
+			styledParts = append(styledParts, l.renderMatches(part, offsets[i], matched, textStyle))
 		}
 	}
 
@@ -409,3 +449,55 @@ func (l *SimpleList) renderFilterItem(item string, index int) string {
 	// Non-selected filter item
 	return spacing + item
 }
+
+// renderMatches renders text with style, highlighting the characters whose
+// rune position (counted from offset) is in matched
+func (l *SimpleList) renderMatches(text string, offset int, matched map[int]bool, style lipgloss.Style) string {
+	if len(matched) == 0 {
+		return style.Render(text)
+	}
+
+	matchStyle := style.Foreground(l.theme.Warning).Bold(true).Underline(true)
+	runes := []rune(text)
+	var b strings.Builder
+	for start := 0; start < len(runes); {
+		// 一致している文字と一致していない文字の連続をまとめて描画する
+		isMatch := matched[offset+start]
+		end := start
+		for end < len(runes) && matched[offset+end] == isMatch {
+			end++
+		}
+		if isMatch {
+			b.WriteString(matchStyle.Render(string(runes[start:end])))
+		} else {
+			b.WriteString(style.Render(string(runes[start:end])))
+		}
+		start = end
+	}
+	return b.String()
+}
+
+// fieldsWithOffsets splits s like strings.Fields and also returns the rune
+// position of each field in s
+func fieldsWithOffsets(s string) ([]string, []int) {
+	var fields []string
+	var offsets []int
+	start := -1
+	runes := []rune(s)
+	for i, r := range runes {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				fields = append(fields, string(runes[start:i]))
+				offsets = append(offsets, start)
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		fields = append(fields, string(runes[start:]))
+		offsets = append(offsets, start)
+	}
+	return fields, offsets
+}
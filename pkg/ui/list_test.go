@@ -193,3 +193,20 @@ func TestSimpleList_ThemeHandling(t *testing.T) {
 	list.SetCheckboxColors(colors)
 	assert.Equal(t, colors, list.checkboxColors, "Checkbox colors should be set")
 }
+
+func TestFieldsWithOffsets(t *testing.T) {
+	fields, offsets := fieldsWithOffsets("(A)  会議 +work\t@office ")
+	assert.Equal(t, []string{"(A)", "会議", "+work", "@office"}, fields)
+	assert.Equal(t, []int{0, 5, 8, 14}, offsets)
+}
+
+func TestSimpleList_renderTaskItemWithHighlights(t *testing.T) {
+	list := &SimpleList{theme: &Theme{Warning: lipgloss.Color("#FFAA00")}}
+	list.SetTaskList(true)
+	list.SetItems([]string{"(A) Write report +work"})
+	list.SetHighlights([][]int{{10, 12, 14, 15}})
+
+	// 強調されても文字が欠けたり入れ替わったりしない（テストでは色が付かない）
+	rendered := list.renderTaskItem(list.items[0], 1)
+	assert.Equal(t, spacing+checkboxIncomplete+"(A) Write report +work", rendered)
+}
@@ -1,6 +1,9 @@
 package ui
 
 import (
+	"errors"
+	"fmt"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -14,6 +17,9 @@ import (
 	"github.com/yuucu/todotui/pkg/todo"
 )
 
+// errTaskNotFound is returned when a task cannot be found by its identity
+var errTaskNotFound = errors.New("task was changed or removed on disk")
+
 // watchFile watches for changes to the todo file
 func (m *Model) watchFile() tea.Cmd {
 	return tea.Batch(
@@ -21,7 +27,12 @@ func (m *Model) watchFile() tea.Cmd {
 			for {
 				select {
 				case event := <-m.watcher.Events:
-					if event.Op&fsnotify.Write == fsnotify.Write {
+					// The directory is watched, so ignore events for other files
+					if filepath.Clean(event.Name) != m.watchPath {
+						continue
+					}
+					// Saves replace the file via rename, which shows up as Create
+					if event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
 						return TaskListChangedMsg{}
 					}
 				case err := <-m.watcher.Errors:
@@ -37,20 +48,27 @@ func NewModel(todoFile string, appConfig AppConfig) (*Model, error) {
 	logger.Debug("Creating new model", "todo_file", todoFile, "theme", appConfig.Theme)
 
 	// Load tasks from file
-	taskList, err := todo.Load(todoFile)
+	store := todo.NewStore(todoFile)
+	taskList, err := store.Load()
 	if err != nil {
 		logger.Error("Failed to load tasks from file", "file", todoFile, "error", err)
 		return nil, err
 	}
+	// Keep the UI responsive from now on; blocked saves are retried in the background
+	store.SetLockTimeout(uiLockTimeout)
+	store.SetBackup(appConfig.Backup.Options())
 
 	logger.Info("Loaded tasks from file", "file", todoFile, "task_count", len(taskList))
 
+	// Move completed tasks to done.txt before anything is shown
+	taskList = autoArchive(store, appConfig, taskList)
+
 	// Get theme for the lists
 	currentTheme := GetTheme(appConfig.Theme)
 
 	// Initialize text input for adding/editing tasks
 	ti := textinput.New()
-	ti.Placeholder = "Enter a task..."
+	ti.Placeholder = TaskInputPrompt
 	ti.CharLimit = 512
 	ti.Width = 50 // Will be updated in updatePaneSizes
 
@@ -58,6 +76,7 @@ func NewModel(todoFile string, appConfig AppConfig) (*Model, error) {
 		filterList:       SimpleList{},
 		taskList:         SimpleList{},
 		todoFilePath:     todoFile,
+		store:            store,
 		tasks:            domain.NewTasks(taskList),
 		activePane:       paneFilter,
 		viewMode:         ViewFilter,
@@ -66,13 +85,15 @@ func NewModel(todoFile string, appConfig AppConfig) (*Model, error) {
 		height:           DefaultTerminalHeight,
 		currentTheme:     &currentTheme,
 		appConfig:        appConfig,
-		originalTask:     "",
 		statusMessage:    "",
 		statusMessageEnd: time.Now(),
 		textInput:        ti,
 		editingTask:      nil,
 	}
 
+	// Load archived tasks for the Completed Tasks filter
+	model.loadDoneTasks()
+
 	// Initialize enhanced list features
 	model.filterList.SetTheme(&currentTheme)
 	model.filterList.SetTaskList(false) // Filter list is not a task list
@@ -96,30 +117,141 @@ func NewModel(todoFile string, appConfig AppConfig) (*Model, error) {
 	}
 	model.watcher = watcher
 
-	// Watch the todo file
-	err = watcher.Add(todoFile)
+	// Watch the directory of the todo file rather than the file itself.
+	// Saves atomically rename a new file over the old one, which would
+	// silently drop a watch placed on the original inode.
+	watchPath, err := filepath.EvalSymlinks(todoFile)
+	if err != nil {
+		watchPath = todoFile
+	}
+	model.watchPath = filepath.Clean(watchPath)
+	err = watcher.Add(filepath.Dir(model.watchPath))
 	if err != nil {
 		logger.Error("Failed to watch todo file", "file", todoFile, "error", err)
 		watcher.Close()
 		return nil, err
 	}
 
-	logger.Debug("File watcher initialized successfully", "file", todoFile)
+	logger.Debug("File watcher initialized successfully", "file", model.watchPath)
 	return model, nil
 }
 
-// saveAndRefresh saves the task list and refreshes the UI
+// saveAndRefresh saves the task list and refreshes the UI.
+// Changes made to the file by other programs since it was last loaded are
+// merged in; conflicting changes open the conflict prompt instead.
 func (m *Model) saveAndRefresh() tea.Cmd {
+	// A save blocked by another instance is already scheduled and will include this change
+	if m.savePending {
+		m.refreshLists()
+		return nil
+	}
+
 	logger.Debug("Saving tasks to file", "file", m.todoFilePath, "task_count", m.tasks.Len())
-	if err := todo.Save(m.tasks.ToTaskList(), m.todoFilePath); err != nil {
+	taskList, err := m.store.Save(m.tasks.ToTaskList())
+	var conflictErr *todo.ConflictError
+	if errors.As(err, &conflictErr) {
+		logger.Warn("External changes conflict with local changes", "file", m.todoFilePath, "conflicts", len(conflictErr.Conflicts))
+		m.pendingConflict = conflictErr
+		m.viewMode = ViewConflict
+		return nil
+	}
+	var lockErr *todo.LockError
+	if errors.As(err, &lockErr) {
+		logger.Warn("Todo file is locked by another instance", "file", m.todoFilePath, "error", err)
+		m.refreshLists()
+		return m.waitForLock(lockErr)
+	}
+	if err != nil {
 		logger.Error("Failed to save tasks to file", "file", m.todoFilePath, "error", err)
+		m.unsaved = true
+		m.refreshLists()
 		return m.setStatusMessage("❌ Failed to save tasks to file: "+err.Error(), 5*time.Second)
 	}
 	logger.Debug("Tasks saved successfully", "file", m.todoFilePath)
+	m.lockRetries = 0
+	m.unsaved = false
+	m.flushJournal(true)
+	m.setTasks(taskList)
+	m.refreshLists()
+	return nil
+}
+
+// waitForLock schedules another save attempt while another instance holds the file lock
+func (m *Model) waitForLock(lockErr *todo.LockError) tea.Cmd {
+	if m.lockRetries >= maxLockRetries {
+		m.lockRetries = 0
+		m.unsaved = true
+		return m.setStatusMessage("❌ Changes not saved: "+lockErr.Error(), 5*time.Second)
+	}
+
+	m.lockRetries++
+	m.savePending = true
+	message := fmt.Sprintf("🔒 Waiting: %s (%d/%d)", lockErr.Error(), m.lockRetries, maxLockRetries)
+	return tea.Batch(
+		m.setStatusMessage(message, 2*lockRetryInterval),
+		tea.Tick(lockRetryInterval, func(time.Time) tea.Msg {
+			return SaveRetryMsg{}
+		}),
+	)
+}
+
+// syncWithFile picks up changes made to the file by other programs.
+// Changes that could not be saved are saved again instead, which merges the
+// file in or opens the conflict prompt, so a reload never drops them.
+func (m *Model) syncWithFile() tea.Cmd {
+	switch {
+	case m.pendingConflict != nil || m.savePending:
+		// Unsaved local changes are waiting on a conflict decision or on the file lock
+		return nil
+	case m.unsaved:
+		return m.saveAndRefresh()
+	default:
+		return m.reloadTasks()
+	}
+}
+
+// reloadTasks reloads the task list from file, retrying later if another instance holds the lock
+func (m *Model) reloadTasks() tea.Cmd {
+	taskList, err := m.store.Load()
+	var lockErr *todo.LockError
+	if errors.As(err, &lockErr) {
+		logger.Debug("Reload blocked by file lock", "file", m.todoFilePath, "error", err)
+		return tea.Batch(
+			m.setStatusMessage("🔒 Waiting: "+lockErr.Error(), 2*lockRetryInterval),
+			tea.Tick(lockRetryInterval, func(time.Time) tea.Msg {
+				return ReloadRetryMsg{}
+			}),
+		)
+	}
+	if err != nil {
+		logger.Error("Failed to reload tasks from file", "file", m.todoFilePath, "error", err)
+		return nil
+	}
+	m.setTasks(taskList)
+	// Another instance may have archived tasks
+	m.loadDoneTasks()
 	m.refreshLists()
 	return nil
 }
 
+// translateID maps a task identity from before the last load or save to the current file
+func (m *Model) translateID(id domain.TaskID) *domain.TaskID {
+	if line, ok := m.store.TranslateLine(id.Line); ok {
+		id.Line = line
+	}
+	return &id
+}
+
+// isBackgroundMsg reports whether msg comes from the file watcher, a timer or the terminal
+// rather than from the user typing
+func isBackgroundMsg(msg tea.Msg) bool {
+	switch msg.(type) {
+	case tea.WindowSizeMsg, TaskListChangedMsg, ReloadRetryMsg, SaveRetryMsg, StatusMessageClearMsg:
+		return true
+	}
+	return false
+}
+
 // Init initializes the model
 func (m *Model) Init() tea.Cmd {
 	// updatePaneSizes is already called in NewModel
@@ -136,8 +268,16 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
 			switch msg.String() {
+			case tabKey:
+				// Put in the next completion candidate
+				m.cycleCompletion(1)
+				return m, nil
+			case shiftTabKey:
+				m.cycleCompletion(-1)
+				return m, nil
 			case "ctrl+c", "esc":
 				// Cancel input
+				m.clearCompletions()
 				m.viewMode = ViewFilter
 				m.editingTask = nil
 				m.textInput.SetValue("")
@@ -145,10 +285,18 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			case "enter":
 				// Save task
+				m.clearCompletions()
 				text := strings.TrimSpace(m.textInput.Value())
 				logger.Debug("Attempting to save task", "text", text, "mode", m.viewMode)
 
 				if text != "" {
+					// Turn date expressions such as due:tomorrow into ISO dates
+					normalized, dateErr := m.appConfig.Calendar().NormalizeDates(text, time.Now())
+					if dateErr != nil {
+						return m, m.setStatusMessage("❌ "+dateErr.Error(), 3*time.Second)
+					}
+					text = normalized
+
 					if m.viewMode == ViewAdd {
 						// Create new task
 						task, err := todotxt.ParseTask(text)
@@ -158,38 +306,28 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						}
 						logger.Debug("Parsed new task successfully", "task", task.String())
 
-						taskList := m.tasks.ToTaskList()
-						taskList = append(taskList, *task)
-						m.tasks = domain.NewTasks(taskList)
-						logger.Debug("Added task to list", "total_tasks", len(taskList))
+						m.addTask("Add", task)
+						logger.Debug("Added task to list", "total_tasks", m.tasks.Len())
 
 					} else if m.viewMode == ViewEdit {
 						// Update existing task
 						if m.editingTask != nil {
-							// Parse the edited text and update the task
-							newTask, err := todotxt.ParseTask(text)
+							err := m.updateTask("Edit", *m.editingTask, func(task *domain.Task) error {
+								return task.SetText(text)
+							})
+							if errors.Is(err, errTaskNotFound) {
+								// The file was reloaded and the task changed or disappeared meanwhile
+								m.viewMode = ViewFilter
+								m.editingTask = nil
+								m.textInput.SetValue("")
+								m.textInput.Blur()
+								return m, m.setStatusMessage("❌ Failed to save task: "+err.Error(), 3*time.Second)
+							}
 							if err != nil {
 								logger.Error("Failed to parse edited task", "text", text, "error", err)
 								return m, m.setStatusMessage("❌ Failed to parse task", 3*time.Second)
 							}
-							logger.Debug("Parsed edited task successfully", "task", newTask.String())
-
-							*m.editingTask = *newTask
-							// Find and update the task in the main list
-							taskList := m.tasks.ToTaskList()
-							found := false
-							for i := range taskList {
-								if taskList[i].String() == m.originalTask {
-									taskList[i] = *newTask
-									found = true
-									logger.Debug("Updated task in list", "index", i, "original", m.originalTask, "new", newTask.String())
-									break
-								}
-							}
-							if !found {
-								logger.Warn("Original task not found in list for editing", "original", m.originalTask)
-							}
-							m.tasks = domain.NewTasks(taskList)
+							logger.Debug("Updated task in list", "line", m.editingTask.Line, "new", text)
 						} else {
 							logger.Warn("editingTask is nil during edit mode")
 						}
@@ -202,8 +340,8 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 					logger.Debug("About to save and refresh")
 					return m, tea.Batch(
-						m.saveAndRefresh(),
 						m.setStatusMessage("✅ Task saved", 2*time.Second),
+						m.saveAndRefresh(),
 					)
 				}
 				// If text is empty, just cancel the edit
@@ -215,10 +353,47 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 		}
-		// Update text input
-		m.textInput, cmd = m.textInput.Update(msg)
-		logger.Debug("Text input updated", "value", m.textInput.Value(), "focused", m.textInput.Focused())
-		return m, cmd
+		// File and timer events are handled below so the edit keeps up with the file
+		if !isBackgroundMsg(msg) {
+			// Update text input
+			m.textInput, cmd = m.textInput.Update(msg)
+			m.updateCompletions()
+			logger.Debug("Text input updated", "value", m.textInput.Value(), "focused", m.textInput.Focused())
+			return m, cmd
+		}
+	}
+
+	// The query prompt takes key input; other messages are handled as usual
+	if m.viewMode == ViewQuery {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			return m, m.handleQueryKey(keyMsg)
+		}
+	}
+
+	// The search prompt takes key input; other messages are handled as usual
+	if m.viewMode == ViewSearch {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			return m, m.handleSearchKey(keyMsg)
+		}
+	}
+
+	// Visual mode and its bulk edit prompt take key input; other messages are handled as usual
+	if m.viewMode == ViewVisual {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			return m, m.handleVisualKey(keyMsg)
+		}
+	}
+	if m.viewMode == ViewBulkEdit {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			return m, m.handleBulkEditKey(keyMsg)
+		}
+	}
+
+	// Handle the conflict prompt before anything else can touch the tasks
+	if m.viewMode == ViewConflict {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+			return m, m.handleConflictKey(keyMsg)
+		}
 	}
 
 	switch msg := msg.(type) {
@@ -259,6 +434,13 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// Archived tasks no longer exist in the todo file and cannot be changed
+		if m.activePane == paneTask && isTaskMutationKey(msg.String()) {
+			if selected, ok := m.filteredTasks.SafeGet(m.taskList.selected); ok && m.isArchivedTask(selected) {
+				return m, m.setStatusMessage("📦 Archived tasks are read-only", 2*time.Second)
+			}
+		}
+
 		// Handle normal mode keys
 		switch msg.String() {
 		case helpKey:
@@ -268,6 +450,29 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		case qKey, ctrlCKey:
 			return m, tea.Quit
+		case slashKey:
+			// Search the task list
+			m.startSearch()
+			return m, nil
+		case colonKey:
+			// Filter tasks with a query
+			m.startQuery()
+			return m, nil
+		case sKey:
+			// Cycle through the sort orders
+			return m, m.cycleSort()
+		case nKey:
+			// Jump to the next search match
+			return m, m.jumpToMatch(1)
+		case NKey:
+			// Jump to the previous search match
+			return m, m.jumpToMatch(-1)
+		case escKey:
+			// Clear the search
+			if m.search != "" {
+				return m, m.clearSearch()
+			}
+			return m, nil
 		case aKey:
 			// Add new task
 			m.viewMode = ViewAdd
@@ -283,16 +488,30 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					// Store the task content for editing
 					selectedTask := m.filteredTasks.Get(m.taskList.selected)
 					m.textInput.SetValue(selectedTask.String())
-					m.originalTask = selectedTask.String()
 
-					// Convert domain.Task to todotxt.Task for editing
-					m.editingTask = selectedTask.ToTodoTxtTask()
+					// Remember which task is edited; the list may be reloaded meanwhile
+					id := selectedTask.ID()
+					m.editingTask = &id
 
 					m.textInput.Focus()
-					logger.Debug("Starting edit mode", "original_task", m.originalTask, "editing_task", m.editingTask.String())
+					logger.Debug("Starting edit mode", "task", selectedTask.String(), "line", id.Line)
 					return m, nil
 				}
 			}
+		case EKey:
+			// Edit the selected task in the external editor
+			if m.activePane == paneTask {
+				if selected, ok := m.filteredTasks.SafeGet(m.taskList.selected); ok {
+					return m, m.editInEditor(domain.Tasks{selected})
+				}
+			}
+			return m, nil
+		case ctrlEKey:
+			// Edit all tasks of the task list in the external editor
+			if m.filteredTasks.Len() > 0 {
+				return m, m.editInEditor(m.filteredTasks)
+			}
+			return m, nil
 		case tabKey:
 			// Switch between panes
 			if m.activePane == paneFilter {
@@ -320,26 +539,49 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Toggle task completion
 			if m.taskList.selected < m.filteredTasks.Len() {
 				taskToToggle := m.filteredTasks.Get(m.taskList.selected)
-				// Find the task in main tasks list and toggle completion using domain model
-				index, task, found := m.findTaskInList(taskToToggle)
-				if found {
-					// Toggle completion directly on the domain task
-					isCompleted := task.ToggleCompletion()
-					// Update the task in the list
-					taskList := m.tasks.ToTaskList()
-					taskList[index] = *task.ToTodoTxtTask()
-					m.tasks = domain.NewTasks(taskList)
-
-					// Show status message
+				// Toggle completion on the task in the main list using domain model
+				var isCompleted bool
+				var next *todotxt.Task
+				var recurErr error
+				action := "Complete"
+				if taskToToggle.IsCompleted() {
+					action = "Reopen"
+				}
+				// The next occurrence of a recurring task is undone with its completion
+				m.beginGroup()
+				defer m.endGroup()
+				err := m.updateTask(action, taskToToggle.ID(), func(task *domain.Task) error {
+					isCompleted = task.ToggleCompletion()
 					if isCompleted {
+						// Completing a recurring task creates its next occurrence
+						next, recurErr = task.NextOccurrence(task.GetCompletedDate())
+					}
+					return nil
+				})
+				if err == nil {
+					// Show status message
+					if recurErr != nil {
+						return m, tea.Batch(
+							m.setStatusMessage("⚠️ Task completed, but not repeated: "+recurErr.Error(), 3*time.Second),
+							m.saveAndRefresh(),
+						)
+					}
+					if next != nil {
+						m.addTask("Repeat", next)
 						return m, tea.Batch(
+							m.setStatusMessage("🔁 Task completed, next one added: "+next.String(), 3*time.Second),
 							m.saveAndRefresh(),
+						)
+					}
+					if isCompleted {
+						return m, tea.Batch(
 							m.setStatusMessage("✅ Task completed", 2*time.Second),
+							m.saveAndRefresh(),
 						)
 					} else {
 						return m, tea.Batch(
-							m.saveAndRefresh(),
 							m.setStatusMessage("🔄 Task marked as incomplete", 2*time.Second),
+							m.saveAndRefresh(),
 						)
 					}
 				}
@@ -353,16 +595,11 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					if m.filterList.selected < len(m.filters) && m.filters[m.filterList.selected].name != FilterDeletedTasks {
 						// Soft delete using domain method
 						taskToDelete := m.filteredTasks.Get(m.taskList.selected)
-						// Find the task in main tasks list and soft delete using domain method
-						index, task, found := m.findTaskInList(taskToDelete)
-						if found {
-							err := task.SoftDelete(time.Now())
-							if err == nil {
-								// Update the task in the list
-								taskList := m.tasks.ToTaskList()
-								taskList[index] = *task.ToTodoTxtTask()
-								m.tasks = domain.NewTasks(taskList)
-							}
+						err := m.updateTask("Delete", taskToDelete.ID(), func(task *domain.Task) error {
+							return task.SoftDelete(time.Now())
+						})
+						if err != nil {
+							return m, m.setStatusMessage("❌ Failed to update task: "+err.Error(), 3*time.Second)
 						}
 						return m, m.saveAndRefresh()
 					}
@@ -373,16 +610,11 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Toggle priority level
 				if m.taskList.selected < m.filteredTasks.Len() {
 					taskToUpdate := m.filteredTasks.Get(m.taskList.selected)
-					// Find the task in main tasks list and cycle priority using domain method
-					index, task, found := m.findTaskInList(taskToUpdate)
-					if found {
-						err := task.CyclePriority(m.appConfig.PriorityLevels)
-						if err == nil {
-							// Update the task in the list
-							taskList := m.tasks.ToTaskList()
-							taskList[index] = *task.ToTodoTxtTask()
-							m.tasks = domain.NewTasks(taskList)
-						}
+					err := m.updateTask("Change priority", taskToUpdate.ID(), func(task *domain.Task) error {
+						return task.CyclePriority(m.appConfig.PriorityLevels)
+					})
+					if err != nil {
+						return m, m.setStatusMessage("❌ Failed to update task: "+err.Error(), 3*time.Second)
 					}
 					return m, m.saveAndRefresh()
 				}
@@ -392,20 +624,37 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Toggle due date to today
 				if m.taskList.selected < m.filteredTasks.Len() {
 					taskToUpdate := m.filteredTasks.Get(m.taskList.selected)
-					// Find the task in main tasks list and toggle due date using domain method
-					index, task, found := m.findTaskInList(taskToUpdate)
-					if found {
-						err := task.ToggleDueToday(time.Now())
-						if err == nil {
-							// Update the task in the list
-							taskList := m.tasks.ToTaskList()
-							taskList[index] = *task.ToTodoTxtTask()
-							m.tasks = domain.NewTasks(taskList)
-						}
+					err := m.updateTask("Toggle due today", taskToUpdate.ID(), func(task *domain.Task) error {
+						return task.ToggleDueToday(time.Now())
+					})
+					if err != nil {
+						return m, m.setStatusMessage("❌ Failed to update task: "+err.Error(), 3*time.Second)
 					}
 					return m, m.saveAndRefresh()
 				}
 			}
+		case DKey:
+			if m.activePane == paneTask {
+				// Defer the task by pushing its threshold date forward
+				if m.taskList.selected < m.filteredTasks.Len() {
+					taskToDefer := m.filteredTasks.Get(m.taskList.selected)
+					var threshold time.Time
+					err := m.updateTask("Defer", taskToDefer.ID(), func(task *domain.Task) error {
+						if err := task.Defer(m.appConfig.DeferDays, time.Now()); err != nil {
+							return err
+						}
+						threshold, _ = task.GetThresholdDate()
+						return nil
+					})
+					if err != nil {
+						return m, m.setStatusMessage("❌ Failed to defer task: "+err.Error(), 3*time.Second)
+					}
+					return m, tea.Batch(
+						m.setStatusMessage("⏳ Deferred until "+threshold.Format(domain.DateFormat), 2*time.Second),
+						m.saveAndRefresh(),
+					)
+				}
+			}
 		case rKey:
 			if m.activePane == paneTask {
 				// Restore deleted or completed task
@@ -419,35 +668,53 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 					// Handle deleted tasks restoration
 					if currentFilter == FilterDeletedTasks {
-						// Find the task in main tasks list and restore using domain method
-						index, task, found := m.findTaskInList(taskToRestore)
-						if found {
-							err := task.RestoreFromDeleted()
-							if err == nil {
-								// Update the task in the list
-								taskList := m.tasks.ToTaskList()
-								taskList[index] = *task.ToTodoTxtTask()
-								m.tasks = domain.NewTasks(taskList)
-							}
+						err := m.updateTask("Restore", taskToRestore.ID(), func(task *domain.Task) error {
+							return task.RestoreFromDeleted()
+						})
+						if err != nil {
+							return m, m.setStatusMessage("❌ Failed to update task: "+err.Error(), 3*time.Second)
 						}
 						return m, m.saveAndRefresh()
 					}
 
 					// Handle completed tasks restoration
-					if currentFilter == "Completed Tasks" {
-						// Find the task in main tasks list and toggle completion using domain model
-						index, task, found := m.findTaskInList(taskToRestore)
-						if found {
+					if currentFilter == FilterCompletedTasks {
+						err := m.updateTask("Reopen", taskToRestore.ID(), func(task *domain.Task) error {
 							task.ToggleCompletion() // This will mark as incomplete
-							// Update the task in the list
-							taskList := m.tasks.ToTaskList()
-							taskList[index] = *task.ToTodoTxtTask()
-							m.tasks = domain.NewTasks(taskList)
+							return nil
+						})
+						if err != nil {
+							return m, m.setStatusMessage("❌ Failed to update task: "+err.Error(), 3*time.Second)
 						}
 						return m, m.saveAndRefresh()
 					}
 				}
 			}
+		case vKey:
+			// Mark a range of tasks to act on them at once
+			if m.activePane == paneTask && m.filteredTasks.Len() > 0 {
+				m.startVisual()
+				m.startRange()
+				m.updateMarks()
+			}
+			return m, nil
+		case VKey:
+			// Mark the selected task to act on several tasks at once
+			if m.activePane == paneTask && m.filteredTasks.Len() > 0 {
+				m.startVisual()
+				m.toggleMark()
+				m.updateMarks()
+			}
+			return m, nil
+		case AKey:
+			// Archive completed tasks to done.txt
+			return m, m.archiveTasks()
+		case uKey:
+			// Undo the most recent change
+			return m, m.undo()
+		case ctrlRKey:
+			// Redo the most recently undone change
+			return m, m.redo()
 		case yKey:
 			if m.activePane == paneTask {
 				// Copy task text to clipboard
@@ -497,13 +764,15 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Return nil to re-render without clearing screen
 		return m, nil
 	case TaskListChangedMsg:
-		// Reload tasks from file
-		if taskList, err := todo.Load(m.todoFilePath); err == nil {
-			m.tasks = domain.NewTasks(taskList)
-			m.refreshLists()
-		}
 		// Continue watching
-		return m, m.watchFile()
+		return m, tea.Batch(m.syncWithFile(), m.watchFile())
+	case ReloadRetryMsg:
+		return m, m.syncWithFile()
+	case SaveRetryMsg:
+		m.savePending = false
+		return m, m.saveAndRefresh()
+	case EditorFinishedMsg:
+		return m, m.finishEditing(msg)
 	case StatusMessageClearMsg:
 		// Clear status message if it has expired
 		if time.Now().After(m.statusMessageEnd) {
@@ -533,15 +802,41 @@ func (m *Model) setStatusMessage(message string, duration time.Duration) tea.Cmd
 	})
 }
 
-// findTaskInList finds a task in the main task list and returns its index and domain task
-func (m *Model) findTaskInList(targetTask domain.Task) (int, domain.Task, bool) {
-	for i := 0; i < m.tasks.Len(); i++ {
-		task := m.tasks.Get(i)
-		if task.String() == targetTask.String() {
-			return i, task, true
-		}
+// findTaskInList finds the task with the given identity in the main task list and returns its index and domain task
+func (m *Model) findTaskInList(id domain.TaskID) (int, domain.Task, bool) {
+	index, found := m.tasks.IndexOf(id)
+	if !found {
+		return -1, domain.Task{}, false
+	}
+	return index, m.tasks.Get(index), true
+}
+
+// updateTask applies fn to the task with the given identity in the main task list
+// and records the change under action so that it can be undone.
+// errTaskNotFound is returned if the task no longer exists, e.g. after a reload.
+func (m *Model) updateTask(action string, id domain.TaskID, fn func(*domain.Task) error) error {
+	_, task, found := m.findTaskInList(id)
+	if !found {
+		logger.Warn("Task not found in list", "line", id.Line, "fingerprint", id.Fingerprint)
+		return errTaskNotFound
+	}
+	before := task.String()
+	// Tasks share the underlying todotxt.Task, so the main list is updated in place
+	if err := fn(&task); err != nil {
+		return err
+	}
+	if after := task.String(); after != before {
+		m.recordChange(change{action: action, line: id.Line, before: before, after: after})
 	}
-	return -1, domain.Task{}, false
+	return nil
+}
+
+// addTask appends a new task to the main task list and records the change under action
+func (m *Model) addTask(action string, task *todotxt.Task) {
+	taskList := m.tasks.ToTaskList()
+	taskList = append(taskList, *task)
+	m.tasks = domain.NewTasks(taskList)
+	m.recordChange(change{action: action, after: task.String()})
 }
 
 // updateTextInputSize updates the text input width based on current terminal size
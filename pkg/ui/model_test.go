@@ -1,11 +1,16 @@
 package ui
 
 import (
+	"os"
 	"strings"
 	"testing"
+	"time"
 
 	todotxt "github.com/1set/todotxt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yuucu/todotui/internal/testutil"
 	"github.com/yuucu/todotui/pkg/domain"
+	"github.com/yuucu/todotui/pkg/todo"
 )
 
 // createTestModel creates a test model with sample data
@@ -39,7 +44,7 @@ func TestModel_findTaskInList(t *testing.T) {
 
 	// Test finding existing task
 	targetTask := model.tasks.Get(0)
-	index, task, found := model.findTaskInList(targetTask)
+	index, task, found := model.findTaskInList(targetTask.ID())
 
 	if !found {
 		t.Error("findTaskInList() should find existing task")
@@ -61,7 +66,7 @@ func TestModel_findTaskInList(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to create domain task: %v", err)
 	}
-	_, _, found = model.findTaskInList(*nonExistentTask)
+	_, _, found = model.findTaskInList(nonExistentTask.ID())
 
 	if found {
 		t.Error("findTaskInList() should not find non-existent task")
@@ -209,3 +214,204 @@ func TestTaskListIntegrity(t *testing.T) {
 		}
 	}
 }
+
+func TestModel_waitForLock(t *testing.T) {
+	model := createTestModel()
+	lockErr := &todo.LockError{
+		Path:   "todo.txt",
+		Holder: &todo.LockHolder{PID: 4242, Host: "teammate", Since: time.Now()},
+	}
+
+	for i := 1; i <= maxLockRetries; i++ {
+		model.savePending = false
+		if cmd := model.waitForLock(lockErr); cmd == nil {
+			t.Fatalf("waitForLock() returned nil command on attempt %d", i)
+		}
+		if !model.savePending {
+			t.Errorf("savePending = false on attempt %d, expected a retry to be scheduled", i)
+		}
+		if !strings.Contains(model.statusMessage, "🔒") || !strings.Contains(model.statusMessage, "pid 4242") {
+			t.Errorf("status message = %q, expected lock holder to be shown", model.statusMessage)
+		}
+	}
+
+	// 再試行回数の上限に達したら保存を諦めてエラーを表示する
+	model.savePending = false
+	model.waitForLock(lockErr)
+	if model.savePending {
+		t.Error("savePending = true after exhausting retries")
+	}
+	if !model.unsaved {
+		t.Error("unsaved = false after exhausting retries, expected the changes to be kept")
+	}
+	if !strings.Contains(model.statusMessage, "❌") {
+		t.Errorf("status message = %q, expected failure message", model.statusMessage)
+	}
+}
+
+func TestModel_DuplicateLines(t *testing.T) {
+	today := time.Now().Format(domain.DateFormat)
+
+	tests := []struct {
+		name     string
+		key      tea.KeyMsg
+		expected string
+	}{
+		{
+			name:     "complete_second_duplicate",
+			key:      tea.KeyMsg{Type: tea.KeyEnter},
+			expected: "Task A\nx " + today + " Task A\nTask B\n",
+		},
+		{
+			name:     "delete_second_duplicate",
+			key:      tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(dKey)},
+			expected: "Task A\nTask A deleted_at:" + today + "\nTask B\n",
+		},
+		{
+			name:     "prioritize_second_duplicate",
+			key:      tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(pKey)},
+			expected: "Task A\n(A) Task A\nTask B\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model, todoPath := newTestModelWithFile(t, "Task A\nTask A\nTask B\n")
+			selectFilter(t, model, FilterAllTasks)
+			model.activePane = paneTask
+			model.taskList.selected = 1
+
+			model.Update(tt.key)
+
+			content, err := os.ReadFile(todoPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("file content = %q, expected %q", content, tt.expected)
+			}
+		})
+	}
+}
+
+func TestModel_EditAfterReload(t *testing.T) {
+	model, todoPath := newTestModelWithFile(t, "Task A\nTask A\n")
+	selectFilter(t, model, FilterAllTasks)
+	model.activePane = paneTask
+	model.taskList.selected = 1
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(eKey)})
+
+	// 編集中に別のプログラムが先頭に行を追加し、再読み込みされる
+	testutil.WriteExternally(t, todoPath, "Task Z\nTask A\nTask A\n")
+	model.Update(TaskListChangedMsg{})
+	if model.viewMode != ViewEdit || len(model.tasks) != 3 {
+		t.Fatalf("expected reload while editing, got mode %v with %d tasks", model.viewMode, len(model.tasks))
+	}
+
+	model.textInput.SetValue("Task A edited")
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	content, err := os.ReadFile(todoPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "Task Z\nTask A\nTask A edited\n"; string(content) != expected {
+		t.Errorf("file content = %q, expected %q", content, expected)
+	}
+}
+
+func TestModel_CompleteRecurringTask(t *testing.T) {
+	model, todoPath := newTestModelWithFile(t, "Pay rent due:2025-01-31 rec:+1m\n")
+	selectFilter(t, model, FilterAllTasks)
+	model.activePane = paneTask
+
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	today := time.Now().Format(domain.DateFormat)
+	expected := "x " + today + " Pay rent rec:+1m due:2025-01-31\nPay rent rec:+1m due:2025-02-28\n"
+	if got := testutil.ReadFile(t, todoPath); got != expected {
+		t.Errorf("file content = %q, expected %q", got, expected)
+	}
+	if !strings.Contains(model.statusMessage, "next one added") {
+		t.Errorf("status message = %q", model.statusMessage)
+	}
+
+	// 完了と次回分の追加は 1 回の u でまとめて取り消せる
+	model.Update(undoKeyMsg)
+	if got := testutil.ReadFile(t, todoPath); got != "Pay rent rec:+1m due:2025-01-31\n" {
+		t.Errorf("file content after undo = %q", got)
+	}
+
+	model.Update(redoKeyMsg)
+	if got := testutil.ReadFile(t, todoPath); got != expected {
+		t.Errorf("file content after redo = %q, expected %q", got, expected)
+	}
+}
+
+func TestModel_DeferTask(t *testing.T) {
+	model, todoPath := newTestModelWithFile(t, "Write report +work\nCall Bob\n")
+	selectFilter(t, model, FilterAllTasks)
+	model.activePane = paneTask
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("D")})
+
+	tomorrow := time.Now().AddDate(0, 0, 1).Format(domain.DateFormat)
+	expected := "Write report +work t:" + tomorrow + "\nCall Bob\n"
+	if got := testutil.ReadFile(t, todoPath); got != expected {
+		t.Errorf("file content = %q, expected %q", got, expected)
+	}
+
+	// 開始日前のタスクは「Upcoming」にのみ表示される
+	selectFilter(t, model, FilterAllTasks)
+	if model.filteredTasks.Len() != 1 {
+		t.Fatalf("All Tasks should only list Call Bob, got %d tasks", model.filteredTasks.Len())
+	}
+	if remaining := model.filteredTasks.Get(0); remaining.String() != "Call Bob" {
+		t.Errorf("All Tasks lists %q, expected Call Bob", remaining.String())
+	}
+	for _, filter := range model.filters {
+		if filter.name == "+work" {
+			t.Error("project filter of a deferred task should not be listed")
+		}
+	}
+	selectFilter(t, model, FilterUpcoming)
+	if model.filteredTasks.Len() != 1 {
+		t.Fatalf("Upcoming should list the deferred task, got %d tasks", model.filteredTasks.Len())
+	}
+	if deferred := model.filteredTasks.Get(0); deferred.ToTodoTxtTask().Todo != "Write report" {
+		t.Errorf("Upcoming lists %q, expected the deferred task", deferred.String())
+	}
+
+	// 再度延期すると開始日からさらに延びる
+	model.activePane = paneTask
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("D")})
+	dayAfter := time.Now().AddDate(0, 0, 2).Format(domain.DateFormat)
+	expected = "Write report +work t:" + dayAfter + "\nCall Bob\n"
+	if got := testutil.ReadFile(t, todoPath); got != expected {
+		t.Errorf("file content after second defer = %q, expected %q", got, expected)
+	}
+}
+
+func TestModel_AddTaskWithDateExpression(t *testing.T) {
+	model, todoPath := newTestModelWithFile(t, "Task A\n")
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(aKey)})
+	model.textInput.SetValue("Call Bob due:tomorrow")
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	tomorrow := time.Now().AddDate(0, 0, 1).Format(domain.DateFormat)
+	if got := testutil.ReadFile(t, todoPath); got != "Task A\nCall Bob due:"+tomorrow+"\n" {
+		t.Errorf("file content = %q", got)
+	}
+
+	// 解釈できない日付は保存せず入力を続けられる
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(aKey)})
+	model.textInput.SetValue("Call Alice due:someday")
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.viewMode != ViewAdd {
+		t.Errorf("view mode = %v, expected to stay in add mode", model.viewMode)
+	}
+	if !strings.Contains(model.statusMessage, "invalid date") {
+		t.Errorf("status message = %q", model.statusMessage)
+	}
+}
@@ -0,0 +1,200 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yuucu/todotui/pkg/domain"
+)
+
+// startQuery opens the query prompt with the current query
+func (m *Model) startQuery() {
+	m.queryBefore = m.query
+	m.queryPrevFilter = m.selectedFilterName()
+	m.queryError = ""
+	m.viewMode = ViewQuery
+	m.textInput.Prompt = QueryPrompt
+	m.textInput.PromptStyle = lipgloss.NewStyle().Foreground(m.currentTheme.Primary).Bold(true)
+	m.textInput.Placeholder = QueryPlaceholder
+	m.textInput.SetValue("")
+	if m.query != nil {
+		m.textInput.SetValue(m.query.String())
+	}
+	m.textInput.CursorEnd()
+	m.textInput.Focus()
+}
+
+// endQuery closes the query prompt
+func (m *Model) endQuery() {
+	m.viewMode = ViewFilter
+	m.queryError = ""
+	m.resetTextInput()
+}
+
+// resetTextInput sets the text input back up for adding and editing tasks
+func (m *Model) resetTextInput() {
+	m.textInput.Prompt = TextInputPrompt
+	m.textInput.PromptStyle = lipgloss.NewStyle()
+	m.textInput.Placeholder = TaskInputPrompt
+	m.textInput.SetValue("")
+	m.textInput.Blur()
+}
+
+// handleQueryKey handles key input while the query prompt is shown.
+// The query is applied as it is typed; Enter keeps it and Esc restores the previous one.
+func (m *Model) handleQueryKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case escKey, ctrlCKey:
+		m.endQuery()
+		m.setQuery(m.queryBefore)
+		if m.queryBefore == nil {
+			m.selectFilter(m.queryPrevFilter)
+		}
+		return nil
+	case enterKey:
+		if m.queryError != "" {
+			return m.setStatusMessage("❌ "+m.queryError, 3*time.Second)
+		}
+		m.endQuery()
+		if m.query == nil {
+			// An empty query goes back to the filter used before
+			if m.queryPrevFilter == "" || strings.HasPrefix(m.queryPrevFilter, FilterQueryPrefix) {
+				m.queryPrevFilter = FilterAllTasks
+			}
+			m.selectFilter(m.queryPrevFilter)
+			return nil
+		}
+		m.activePane = paneTask
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.textInput, cmd = m.textInput.Update(msg)
+	m.applyQueryInput()
+	return cmd
+}
+
+// applyQueryInput parses the text in the prompt and shows the matching tasks.
+// While the text does not parse, the last valid query stays applied.
+func (m *Model) applyQueryInput() {
+	text := strings.TrimSpace(m.textInput.Value())
+	if text == "" {
+		m.queryError = ""
+		m.setQuery(nil)
+		return
+	}
+	query, err := domain.ParseQuery(text)
+	if err != nil {
+		m.queryError = err.Error()
+		return
+	}
+	m.queryError = ""
+	m.setQuery(query)
+}
+
+// setQuery replaces the active query and selects its filter
+func (m *Model) setQuery(query *domain.Query) {
+	m.query = query
+	m.refreshFilterList()
+	if query != nil {
+		m.selectFilter(queryFilterName(query))
+	} else {
+		m.refreshTaskList()
+	}
+}
+
+// selectFilter selects the filter with the given name, if it is listed
+func (m *Model) selectFilter(name string) bool {
+	for i, filter := range m.filters {
+		if filter.name == name {
+			m.filterList.SetSelectedIndexPreserveScroll(i)
+			m.refreshTaskList()
+			return true
+		}
+	}
+	return false
+}
+
+// selectedFilterName returns the name of the selected filter, or "" if there is none
+func (m *Model) selectedFilterName() string {
+	if m.filterList.selected < len(m.filters) {
+		return m.filters[m.filterList.selected].name
+	}
+	return ""
+}
+
+// queryFilterName returns the name the filter of query is listed under
+func queryFilterName(query *domain.Query) string {
+	return FilterQueryPrefix + query.String()
+}
+
+// getQueryFilter returns the filter for the active query, or nil if there is none
+func (m *Model) getQueryFilter() *FilterData {
+	if m.query == nil {
+		return nil
+	}
+	filter := m.newQueryFilter(queryFilterName(m.query), m.query)
+	return &filter
+}
+
+// getSavedFilters returns the saved filters from the configuration, headed by their section header
+func (m *Model) getSavedFilters() []FilterData {
+	var filters []FilterData
+	for _, saved := range m.appConfig.Filters {
+		query, err := saved.ParseQuery()
+		if err != nil {
+			// Invalid filters are dropped when the configuration is loaded
+			continue
+		}
+		filter := m.newQueryFilter(savedFilterName(saved.Name), query)
+		// Invalid sorts are cleared when the configuration is loaded
+		filter.sort, _ = saved.ParseSort()
+		filters = append(filters, filter)
+	}
+	if len(filters) == 0 {
+		return nil
+	}
+	header := FilterData{
+		name: FilterHeaderSaved,
+		filterFn: func(tasks domain.Tasks) domain.Tasks {
+			return domain.Tasks{}
+		},
+	}
+	return append([]FilterData{header}, filters...)
+}
+
+// newQueryFilter returns a filter listing the tasks that match query
+func (m *Model) newQueryFilter(name string, query *domain.Query) FilterData {
+	calendar := m.appConfig.Calendar()
+	return FilterData{
+		name: name,
+		filterFn: func(tasks domain.Tasks) domain.Tasks {
+			return query.Filter(calendar, time.Now())(tasks)
+		},
+		query: query,
+	}
+}
+
+// savedFilterName returns the name a saved filter is listed under
+func savedFilterName(name string) string {
+	return "  " + strings.TrimSpace(name)
+}
+
+// renderQueryBar renders the query, search or bulk edit prompt in place of the help/status bar
+func (m *Model) renderQueryBar() string {
+	errorStyle := lipgloss.NewStyle().
+		Foreground(m.currentTheme.Danger)
+
+	bar := m.textInput.View()
+	if m.queryError != "" {
+		bar += "  " + errorStyle.Render(m.queryError)
+	}
+
+	return lipgloss.NewStyle().
+		Background(m.currentTheme.Background).
+		Width(m.width).
+		MaxWidth(m.width).
+		Render(bar)
+}
@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// typeText sends text to the model one key at a time
+func typeText(m *Model, text string) {
+	for _, r := range text {
+		m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+}
+
+// filteredTodos returns the descriptions of the tasks shown in the task pane
+func filteredTodos(m *Model) []string {
+	var todos []string
+	for _, task := range m.filteredTasks.ToTaskList() {
+		todos = append(todos, task.Todo)
+	}
+	return todos
+}
+
+const queryTestContent = `(A) Write report +work @office
+(B) Review budget +work @home
+Buy milk @store
+x 2025-01-14 Send invoice +work
+`
+
+func TestModel_QueryPrompt(t *testing.T) {
+	model, _ := newTestModelWithFile(t, queryTestContent)
+	selectFilter(t, model, FilterAllTasks)
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(colonKey)})
+	if model.viewMode != ViewQuery {
+		t.Fatalf("view mode = %v, expected the query prompt", model.viewMode)
+	}
+
+	// 入力中にタスク一覧が絞り込まれる
+	typeText(model, "+work")
+	if got := strings.Join(filteredTodos(model), ","); got != "Write report,Review budget,Send invoice" {
+		t.Errorf("tasks while typing = %q", got)
+	}
+	typeText(model, " -status:done")
+	if got := strings.Join(filteredTodos(model), ","); got != "Write report,Review budget" {
+		t.Errorf("tasks while typing = %q", got)
+	}
+
+	// 解析できない間は直前の有効なクエリが使われる
+	typeText(model, " (")
+	if model.queryError == "" {
+		t.Error("an unclosed parenthesis should be reported")
+	}
+	if got := strings.Join(filteredTodos(model), ","); got != "Write report,Review budget" {
+		t.Errorf("tasks with an invalid query = %q", got)
+	}
+	typeText(model, "@home OR pri:A)")
+
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.viewMode != ViewFilter {
+		t.Fatalf("view mode = %v, expected the prompt to close", model.viewMode)
+	}
+	if got := model.selectedFilterName(); got != FilterQueryPrefix+"+work -status:done (@home OR pri:A)" {
+		t.Errorf("selected filter = %q", got)
+	}
+	if got := strings.Join(filteredTodos(model), ","); got != "Write report,Review budget" {
+		t.Errorf("tasks after enter = %q", got)
+	}
+	if model.activePane != paneTask {
+		t.Error("the task pane should be active after applying a query")
+	}
+
+	// キャンセルすると元のクエリに戻る
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(colonKey)})
+	typeText(model, " @store")
+	if model.filteredTasks.Len() != 0 {
+		t.Errorf("no task should match, got %v", filteredTodos(model))
+	}
+	model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if got := strings.Join(filteredTodos(model), ","); got != "Write report,Review budget" {
+		t.Errorf("tasks after cancel = %q", got)
+	}
+
+	// 空のクエリで元のフィルタに戻る
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(colonKey)})
+	model.textInput.SetValue("")
+	model.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.query != nil {
+		t.Errorf("query = %q, expected it to be removed", model.query.String())
+	}
+	if got := model.selectedFilterName(); got != FilterAllTasks {
+		t.Errorf("selected filter = %q, expected %q", got, FilterAllTasks)
+	}
+	for _, filter := range model.filters {
+		if strings.HasPrefix(filter.name, FilterQueryPrefix) {
+			t.Errorf("query filter %q is still listed", filter.name)
+		}
+	}
+}
+
+func TestModel_QueryWithoutMatches(t *testing.T) {
+	model, _ := newTestModelWithFile(t, queryTestContent)
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(colonKey)})
+	typeText(model, "+garden")
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	// 一致するタスクがなければ空のまま（全タスクに戻さない）
+	if model.filteredTasks.Len() != 0 {
+		t.Errorf("tasks = %v, expected none", filteredTodos(model))
+	}
+}
+
+func TestModel_SavedFilters(t *testing.T) {
+	model, _ := newTestModelWithFile(t, queryTestContent)
+	model.appConfig.Filters = []SavedFilterConfig{
+		{Name: "Work in progress", Query: "+work status:open"},
+		{Name: "Errands", Contexts: []string{"store"}},
+		{Name: "Nothing", Query: "+garden"},
+	}
+	model.refreshLists()
+
+	// 「Saved」見出しの下に件数付きで表示される
+	var items []string
+	for i, filter := range model.filters {
+		if filter.name == FilterHeaderSaved {
+			items = model.filterList.items[i+1 : i+4]
+			break
+		}
+	}
+	expected := []string{"  Work in progress (2)", "  Errands (1)", "  Nothing (0)"}
+	if strings.Join(items, ",") != strings.Join(expected, ",") {
+		t.Fatalf("saved filter items = %q, expected %q", items, expected)
+	}
+
+	selectFilter(t, model, "  Work in progress")
+	if got := strings.Join(filteredTodos(model), ","); got != "Write report,Review budget" {
+		t.Errorf("tasks = %q", got)
+	}
+	selectFilter(t, model, "  Nothing")
+	if model.filteredTasks.Len() != 0 {
+		t.Errorf("tasks = %v, expected none", filteredTodos(model))
+	}
+
+	// 件数はタスクの変更に追従する
+	selectFilter(t, model, "  Work in progress")
+	model.activePane = paneTask
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := model.filterList.items[model.filterList.selected]; got != "  Work in progress (1)" {
+		t.Errorf("selected item after completing a task = %q", got)
+	}
+}
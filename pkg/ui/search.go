@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yuucu/todotui/pkg/domain"
+)
+
+// startSearch opens the search prompt with the current search
+func (m *Model) startSearch() {
+	m.searchBefore = m.search
+	m.viewMode = ViewSearch
+	m.textInput.Prompt = SearchPrompt
+	m.textInput.PromptStyle = lipgloss.NewStyle().Foreground(m.currentTheme.Warning).Bold(true)
+	m.textInput.Placeholder = SearchPlaceholder
+	m.textInput.SetValue(m.search)
+	m.textInput.CursorEnd()
+	m.textInput.Focus()
+}
+
+// endSearch closes the search prompt
+func (m *Model) endSearch() {
+	m.viewMode = ViewFilter
+	m.resetTextInput()
+}
+
+// handleSearchKey handles key input while the search prompt is shown.
+// The task list is narrowed down as the text is typed; Enter keeps the search
+// and Esc restores the previous one.
+func (m *Model) handleSearchKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case escKey, ctrlCKey:
+		m.endSearch()
+		m.setSearch(m.searchBefore)
+		return nil
+	case enterKey:
+		m.endSearch()
+		if m.search != "" {
+			m.activePane = paneTask
+		}
+		return nil
+	case downKey:
+		m.taskList.MoveDown()
+		return nil
+	case upKey:
+		m.taskList.MoveUp()
+		return nil
+	}
+
+	var cmd tea.Cmd
+	m.textInput, cmd = m.textInput.Update(msg)
+	if m.textInput.Value() != m.search {
+		m.setSearch(m.textInput.Value())
+		// 入力が変わったら最初の一致に移動する
+		m.taskList.SetSelectedIndex(0)
+	}
+	return cmd
+}
+
+// setSearch replaces the search text and shows the matching tasks
+func (m *Model) setSearch(search string) {
+	m.search = search
+	m.refreshTaskList()
+}
+
+// clearSearch removes the search, showing every task of the filter again
+func (m *Model) clearSearch() tea.Cmd {
+	m.setSearch("")
+	return m.setStatusMessage("🔎 Search cleared", 2*time.Second)
+}
+
+// jumpToMatch moves the selection step matches forward (or backward when
+// negative), wrapping around the ends of the list like n/N in vim
+func (m *Model) jumpToMatch(step int) tea.Cmd {
+	if m.search == "" {
+		return m.setStatusMessage("🔎 No search; press / to search", 2*time.Second)
+	}
+	count := m.filteredTasks.Len()
+	if count == 0 {
+		return m.setStatusMessage("🔎 No tasks match "+SearchPrompt+m.search, 2*time.Second)
+	}
+	m.activePane = paneTask
+	index := ((m.taskList.selected+step)%count + count) % count
+	m.taskList.SetSelectedIndex(index)
+	return m.setStatusMessage(fmt.Sprintf("🔎 Match %d/%d", index+1, count), 2*time.Second)
+}
+
+// searchTasks returns the tasks that match the search
+func (m *Model) searchTasks(tasks domain.Tasks) domain.Tasks {
+	if m.search == "" {
+		return tasks
+	}
+	return tasks.Filter(func(task domain.Task, _ int) bool {
+		_, ok := domain.FuzzyMatch(m.search, task.SearchText())
+		return ok
+	})
+}
+
+// searchHighlights returns the rune positions of the characters matched by
+// the search in the display string of task, whose search text starts after prefix
+func (m *Model) searchHighlights(task *domain.Task, prefix string) []int {
+	if m.search == "" {
+		return nil
+	}
+	positions, ok := domain.FuzzyMatch(m.search, task.SearchText())
+	if !ok {
+		return nil
+	}
+	offset := utf8.RuneCountInString(prefix)
+	for i := range positions {
+		positions[i] += offset
+	}
+	return positions
+}
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestModel_SearchPrompt(t *testing.T) {
+	model, _ := newTestModelWithFile(t, queryTestContent)
+	selectFilter(t, model, FilterAllTasks)
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(slashKey)})
+	if model.viewMode != ViewSearch {
+		t.Fatalf("view mode = %v, expected the search prompt", model.viewMode)
+	}
+
+	// 入力中にタスク一覧が絞り込まれる
+	typeText(model, "rprt")
+	if got := strings.Join(filteredTodos(model), ","); got != "Write report" {
+		t.Errorf("tasks while typing = %q", got)
+	}
+	// "(A) Write report +work @office" の "report" の r, p, r, t が強調される
+	if got := model.taskList.highlights[0]; !slices.Equal(got, []int{10, 12, 14, 15}) {
+		t.Errorf("highlights = %v", got)
+	}
+
+	// プロジェクトとコンテキストも検索対象
+	for range "rprt" {
+		model.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	typeText(model, "+wk")
+	if got := strings.Join(filteredTodos(model), ","); got != "Write report,Review budget" {
+		t.Errorf("tasks matching projects = %q", got)
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.viewMode != ViewFilter || model.activePane != paneTask {
+		t.Fatalf("enter should close the prompt and move to the tasks, mode = %v", model.viewMode)
+	}
+	if model.search != "+wk" {
+		t.Errorf("search = %q", model.search)
+	}
+
+	// n / N で一致したタスクの間を移動し、端で折り返す
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(nKey)})
+	if model.taskList.selected != 1 {
+		t.Errorf("n selected %d, expected 1", model.taskList.selected)
+	}
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(nKey)})
+	if model.taskList.selected != 0 {
+		t.Errorf("n should wrap around to 0, selected %d", model.taskList.selected)
+	}
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(NKey)})
+	if model.taskList.selected != 1 {
+		t.Errorf("N should wrap around to 1, selected %d", model.taskList.selected)
+	}
+
+	// キャンセルすると直前の検索に戻る
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(slashKey)})
+	typeText(model, "zzz")
+	if model.filteredTasks.Len() != 0 {
+		t.Errorf("no task should match, got %v", filteredTodos(model))
+	}
+	model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.search != "+wk" || model.filteredTasks.Len() != 2 {
+		t.Errorf("esc should restore the search, got %q with %v", model.search, filteredTodos(model))
+	}
+
+	// Esc で検索を解除する
+	model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.search != "" {
+		t.Errorf("esc should clear the search, got %q", model.search)
+	}
+	if got := strings.Join(filteredTodos(model), ","); got != "Write report,Review budget,Buy milk" {
+		t.Errorf("tasks after clearing = %q", got)
+	}
+	if model.taskList.highlights[0] != nil {
+		t.Error("nothing should be highlighted without a search")
+	}
+}
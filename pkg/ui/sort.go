@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yuucu/todotui/pkg/domain"
+)
+
+// sortPresets are the sort orders cycled through with s.
+// The first one keeps the sort of the selected saved filter, or the file order.
+var sortPresets = []string{
+	"",
+	"priority,due",
+	"due,priority",
+	"urgency",
+	"-created",
+	"project,priority",
+	"alphabetical",
+}
+
+// cycleSort switches to the next sort preset, keeping the selected task selected
+func (m *Model) cycleSort() tea.Cmd {
+	selected, hasSelection := m.filteredTasks.SafeGet(m.taskList.selected)
+
+	m.sortIndex = (m.sortIndex + 1) % len(sortPresets)
+	m.refreshTaskList()
+
+	if hasSelection {
+		if index, ok := m.filteredTasks.IndexOf(selected.ID()); ok {
+			m.taskList.SetSelectedIndex(index)
+		}
+	}
+	return m.setStatusMessage("↕ Sort: "+m.sortName(), 2*time.Second)
+}
+
+// currentSortOrder returns the sort order of the task list
+func (m *Model) currentSortOrder() domain.SortOrder {
+	if m.sortIndex > 0 && m.sortIndex < len(sortPresets) {
+		// プリセットは固定の文字列なので解析に失敗しない
+		order, _ := domain.ParseSortOrder(sortPresets[m.sortIndex])
+		return order
+	}
+	if m.filterList.selected < len(m.filters) {
+		return m.filters[m.filterList.selected].sort
+	}
+	return nil
+}
+
+// sortName returns the current sort order for display
+func (m *Model) sortName() string {
+	order := m.currentSortOrder()
+	if len(order) == 0 {
+		return SortNameFileOrder
+	}
+	return order.String()
+}
+
+// sortTasks sorts tasks by the current sort order
+func (m *Model) sortTasks(tasks domain.Tasks) domain.Tasks {
+	return tasks.Sort(m.currentSortOrder(), m.urgencyFn(time.Now()))
+}
@@ -0,0 +1,76 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const sortTestContent = `Write report +work due:2099-01-20
+(B) Buy milk @store
+(A) Call plumber +home due:2099-01-10
+x 2025-01-14 Send invoice +work
+`
+
+func TestModel_CycleSort(t *testing.T) {
+	model, _ := newTestModelWithFile(t, sortTestContent)
+	selectFilter(t, model, FilterAllTasks)
+
+	// 最初はファイルの順序
+	if got := strings.Join(filteredTodos(model), ","); got != "Write report,Buy milk,Call plumber" {
+		t.Fatalf("tasks = %q", got)
+	}
+	if !strings.Contains(model.getStatusInfo(), "↕ "+SortNameFileOrder) {
+		t.Errorf("status = %q, expected the file order", model.getStatusInfo())
+	}
+
+	// s で並び順を切り替える。選択中のタスクは選択されたまま
+	model.activePane = paneTask
+	model.taskList.SetSelectedIndex(2)
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(sKey)})
+	if got := strings.Join(filteredTodos(model), ","); got != "Call plumber,Buy milk,Write report" {
+		t.Errorf("tasks sorted by priority = %q", got)
+	}
+	if selected, _ := model.filteredTasks.SafeGet(model.taskList.selected); selected.ToTodoTxtTask().Todo != "Call plumber" {
+		t.Errorf("selected task = %q, expected Call plumber", selected.ToTodoTxtTask().Todo)
+	}
+	if !strings.Contains(model.statusMessage, "priority,due") {
+		t.Errorf("status message = %q", model.statusMessage)
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(sKey)})
+	if got := strings.Join(filteredTodos(model), ","); got != "Call plumber,Write report,Buy milk" {
+		t.Errorf("tasks sorted by due date = %q", got)
+	}
+
+	// 一周するとファイルの順序に戻る
+	for range len(sortPresets) - 2 {
+		model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(sKey)})
+	}
+	if got := strings.Join(filteredTodos(model), ","); got != "Write report,Buy milk,Call plumber" {
+		t.Errorf("tasks after a full cycle = %q", got)
+	}
+}
+
+func TestModel_SavedFilterSort(t *testing.T) {
+	model, _ := newTestModelWithFile(t, sortTestContent)
+	model.appConfig.Filters = []SavedFilterConfig{
+		{Name: "By due date", Query: "status:open", Sort: "due,-alpha"},
+	}
+	model.refreshLists()
+
+	selectFilter(t, model, "  By due date")
+	if got := strings.Join(filteredTodos(model), ","); got != "Call plumber,Write report,Buy milk" {
+		t.Errorf("tasks = %q", got)
+	}
+	if !strings.Contains(model.getStatusInfo(), "↕ due,-alphabetical") {
+		t.Errorf("status = %q, expected the sort of the saved filter", model.getStatusInfo())
+	}
+
+	// s で選んだ並び順が保存されたフィルタの並び順より優先される
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(sKey)})
+	if got := strings.Join(filteredTodos(model), ","); got != "Call plumber,Buy milk,Write report" {
+		t.Errorf("tasks sorted by priority = %q", got)
+	}
+}
@@ -3,10 +3,10 @@ package ui
 import (
 	"time"
 
-	todotxt "github.com/1set/todotxt"
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/fsnotify/fsnotify"
 	"github.com/yuucu/todotui/pkg/domain"
+	"github.com/yuucu/todotui/pkg/todo"
 )
 
 // ViewMode represents the current view mode
@@ -18,6 +18,11 @@ const (
 	ViewHelp
 	ViewEdit
 	ViewAdd
+	ViewConflict
+	ViewQuery
+	ViewSearch
+	ViewVisual
+	ViewBulkEdit
 )
 
 // Pane represents which pane is active
@@ -33,6 +38,8 @@ type FilterData struct {
 	name     string
 	filterFn func(domain.Tasks) domain.Tasks
 	count    int
+	query    *domain.Query    // Query of a query or saved filter, nil for built-in filters
+	sort     domain.SortOrder // Sort order of a saved filter, nil to use the file order
 }
 
 // StatusMessageClearMsg is a message to clear the status message
@@ -41,6 +48,18 @@ type StatusMessageClearMsg struct{}
 // TaskListChangedMsg is sent when the task list file changes
 type TaskListChangedMsg struct{}
 
+// SaveRetryMsg is sent to retry a save that was blocked by another instance's lock
+type SaveRetryMsg struct{}
+
+// ReloadRetryMsg is sent to retry a reload that was blocked by another instance's lock
+type ReloadRetryMsg struct{}
+
+// EditorFinishedMsg is sent when the external editor opened with E or ctrl+e exits
+type EditorFinishedMsg struct {
+	session *editorSession
+	err     error
+}
+
 // HelpContent represents help information for key bindings
 type HelpContent struct {
 	Category string
@@ -57,6 +76,7 @@ type HelpItem struct {
 type Model struct {
 	appConfig        AppConfig
 	tasks            domain.Tasks
+	doneTasks        domain.Tasks // Archived tasks read from done.txt
 	filterList       SimpleList
 	taskList         SimpleList
 	filters          []FilterData
@@ -67,12 +87,30 @@ type Model struct {
 	height           int
 	currentTheme     *Theme
 	todoFilePath     string
+	store            *todo.Store // Storage that tracks the file state as of the last load
 	statusMessage    string
 	statusMessageEnd time.Time
-	originalTask     string
 	watcher          *fsnotify.Watcher
-	helpContent      []HelpContent   // Help content for key bindings
-	helpScroll       int             // Current scroll position in help view
-	textInput        textinput.Model // Text input for adding/editing tasks
-	editingTask      *todotxt.Task   // Currently editing task
+	watchPath        string                 // Resolved todo file path matched against watcher events
+	helpContent      []HelpContent          // Help content for key bindings
+	helpScroll       int                    // Current scroll position in help view
+	textInput        textinput.Model        // Text input for adding/editing tasks
+	editingTask      *domain.TaskID         // Identity of the task being edited
+	pendingConflict  *todo.ConflictError    // Conflict waiting for the user's decision
+	savePending      bool                   // A save is waiting for the file lock
+	lockRetries      int                    // Number of save attempts blocked by the file lock
+	unsaved          bool                   // The last save failed, so the tasks in memory are not on disk
+	history          history                // Changes that can be undone and redone
+	changeGroup      int                    // Group of the bulk operation being recorded, 0 if none
+	pendingJournal   []todo.JournalEntry    // Journal entries waiting for the next successful save
+	query            *domain.Query          // Query typed at the : prompt, nil if none
+	queryBefore      *domain.Query          // Query restored when the prompt is cancelled
+	queryPrevFilter  string                 // Filter selected before the prompt was opened
+	queryError       string                 // Why the text in the prompt is not a valid query
+	search           string                 // Text typed at the / prompt, "" if not searching
+	searchBefore     string                 // Search restored when the prompt is cancelled
+	sortIndex        int                    // Index of the sort preset selected with s
+	completion       completionState        // Completion popup of the add/edit input box
+	visualAnchor     int                    // Task where the range selected with v starts, noVisualRange if none
+	marked           map[domain.TaskID]bool // Tasks marked one by one with V
 }
@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yuucu/todotui/pkg/domain"
+)
+
+// nextSortOrder lists the tasks of the Next filter most urgent first
+var nextSortOrder = domain.SortOrder{{Key: domain.SortUrgency}}
+
+// urgencyFn returns a function scoring tasks with the configured coefficients at now
+func (m *Model) urgencyFn(now time.Time) func(*domain.Task) float64 {
+	coefficients := m.appConfig.Urgency.Coefficients()
+	calendar := m.appConfig.Calendar()
+	return func(task *domain.Task) float64 {
+		return task.Urgency(coefficients, calendar, now)
+	}
+}
+
+// getNextFilter returns the Next filter listing the most urgent open tasks,
+// or nil if there are no open tasks
+func (m *Model) getNextFilter() *FilterData {
+	filter := m.addFilterIfNotEmpty(FilterNext, m.getNextFilterFn())
+	if filter != nil {
+		filter.sort = nextSortOrder
+	}
+	return filter
+}
+
+// getNextFilterFn returns the filter function for the Next filter
+func (m *Model) getNextFilterFn() func(domain.Tasks) domain.Tasks {
+	return func(tasks domain.Tasks) domain.Tasks {
+		now := time.Now()
+		open := tasks.Filter(func(task domain.Task, _ int) bool {
+			return !task.IsDeleted() && !task.IsCompleted() && !task.IsUpcoming(now)
+		})
+		sorted := open.Sort(nextSortOrder, m.urgencyFn(now))
+		if count := m.appConfig.Urgency.NextCount; count > 0 && sorted.Len() > count {
+			sorted = sorted[:count]
+		}
+		return sorted
+	}
+}
+
+// formatUrgency formats an urgency score for the task pane
+func formatUrgency(score float64) string {
+	return fmt.Sprintf("%s%.1f", UrgencyPrefix, score)
+}
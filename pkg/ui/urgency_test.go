@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+const urgencyTestContent = `Write report +work
+(C) Buy milk @store
+(A) Call plumber +home
+Wait for reply waiting:bob
+Renew passport t:2099-01-01
+x 2025-01-14 (A) Send invoice +work
+`
+
+func TestModel_NextFilter(t *testing.T) {
+	model, _ := newTestModelWithFile(t, urgencyTestContent)
+	model.appConfig.Urgency.NextCount = 3
+	model.appConfig.Urgency.Tags = map[string]float64{"waiting": -5}
+	model.refreshLists()
+
+	// 緊急度の高い順に上位N件だけが表示される。完了済みと未来のタスクは含まない
+	selectFilter(t, model, FilterNext)
+	if got := strings.Join(filteredTodos(model), ","); got != "Call plumber,Buy milk,Write report" {
+		t.Errorf("tasks = %q", got)
+	}
+	if !strings.Contains(model.getStatusInfo(), "↕ urgency") {
+		t.Errorf("status = %q, expected the urgency sort", model.getStatusInfo())
+	}
+	if got := model.filterList.items[model.filterList.selected]; got != FilterNext+" (3)" {
+		t.Errorf("filter item = %q", got)
+	}
+
+	// 係数の変更が反映される
+	model.appConfig.Urgency.Tags = map[string]float64{"waiting": 10}
+	model.refreshLists()
+	if got := strings.Join(filteredTodos(model), ","); got != "Wait for reply,Call plumber,Buy milk" {
+		t.Errorf("tasks with a tag coefficient = %q", got)
+	}
+}
+
+func TestModel_ShowUrgency(t *testing.T) {
+	model, _ := newTestModelWithFile(t, urgencyTestContent)
+	selectFilter(t, model, FilterAllTasks)
+	if strings.Contains(model.taskList.items[0], UrgencyPrefix) {
+		t.Errorf("the score should be hidden by default, got %q", model.taskList.items[0])
+	}
+
+	model.appConfig.Urgency.Show = true
+	model.refreshTaskList()
+	expected := []string{
+		"Write report +work " + UrgencyPrefix + "1.0",
+		"(C) Buy milk @store " + UrgencyPrefix + "1.8",
+		"(A) Call plumber +home " + UrgencyPrefix + "7.0",
+	}
+	for i, item := range expected {
+		if model.taskList.items[i] != item {
+			t.Errorf("item %d = %q, expected %q", i, model.taskList.items[i], item)
+		}
+	}
+}
@@ -116,6 +116,11 @@ func (m *Model) View() string {
 		return m.renderHelpView()
 	}
 
+	// If a save conflicted with external changes, ask how to resolve it
+	if m.viewMode == ViewConflict {
+		return m.renderConflictView()
+	}
+
 	// If in add/edit mode, show textarea (keeping existing behavior as full screen)
 	if m.viewMode == ViewAdd || m.viewMode == ViewEdit {
 		var title string
@@ -139,11 +144,15 @@ func (m *Model) View() string {
 			Foreground(m.currentTheme.TextSubtle).
 			Padding(0, 1)
 
-		return lipgloss.JoinVertical(lipgloss.Left,
+		sections := []string{
 			titleStyle.Render(title),
 			inputStyle.Render(m.textInput.View()),
-			helpStyle.Render(EditModeHelp),
-		)
+		}
+		if popup := m.renderCompletions(); popup != "" {
+			sections = append(sections, popup)
+		}
+		sections = append(sections, helpStyle.Render(EditModeHelp))
+		return lipgloss.JoinVertical(lipgloss.Left, sections...)
 	}
 
 	// Always render the main view (panes, help bar, etc.)
@@ -251,9 +260,16 @@ func (m *Model) renderCombinedHelpStatusBar() string {
 		return ""
 	}
 
+	// The query, search and bulk edit prompts replace the bar while they are open
+	if m.viewMode == ViewQuery || m.viewMode == ViewSearch || m.viewMode == ViewBulkEdit {
+		return m.renderQueryBar()
+	}
+
 	// Get help text based on active pane and current filter
 	var helpText string
-	if m.activePane == paneFilter {
+	if m.viewMode == ViewVisual {
+		helpText = m.visualHelpText()
+	} else if m.activePane == paneFilter {
 		helpText = HelpFilterPane
 	} else {
 		// Check if we're viewing deleted tasks
@@ -261,11 +277,11 @@ func (m *Model) renderCombinedHelpStatusBar() string {
 		isViewingCompleted := m.filterList.selected < len(m.filters) && m.filters[m.filterList.selected].name == FilterCompletedTasks
 
 		if isViewingDeleted {
-			helpText = "j/k: navigate | r: restore task | y: copy task | Tab/h/l: switch panes | a: add | q: quit"
+			helpText = HelpDeletedTaskPane
 		} else if isViewingCompleted {
-			helpText = "j/k: navigate | r: restore task | y: copy task | Tab/h/l: switch panes | a: add | q: quit"
+			helpText = HelpCompletedTaskPane
 		} else {
-			helpText = "j/k: navigate | Enter: toggle completion | e: edit | p: priority toggle | d: delete | y: copy task | Tab/h/l: switch panes | a: add | q: quit"
+			helpText = HelpTaskPane
 		}
 	}
 
@@ -0,0 +1,367 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	todotxt "github.com/1set/todotxt"
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/yuucu/todotui/pkg/domain"
+)
+
+// noVisualRange is the anchor while no range is being selected with v
+const noVisualRange = -1
+
+// startVisual enters visual mode in the task pane.
+// v marks the range between the anchor and the cursor; V marks single tasks.
+func (m *Model) startVisual() {
+	m.viewMode = ViewVisual
+	m.activePane = paneTask
+	m.visualAnchor = noVisualRange
+	m.marked = make(map[domain.TaskID]bool)
+}
+
+// endVisual leaves visual mode and clears the marks
+func (m *Model) endVisual() {
+	m.viewMode = ViewFilter
+	m.visualAnchor = noVisualRange
+	m.marked = nil
+	m.updateMarks()
+}
+
+// startRange starts marking the range from the selected task
+func (m *Model) startRange() {
+	m.visualAnchor = m.taskList.selected
+}
+
+// toggleMark marks or unmarks the selected task on its own.
+// While a range is being selected, it ends the range and keeps it marked instead.
+func (m *Model) toggleMark() {
+	if m.visualAnchor != noVisualRange {
+		for _, id := range m.rangeTaskIDs() {
+			m.marked[id] = true
+		}
+		m.visualAnchor = noVisualRange
+		return
+	}
+
+	task, ok := m.filteredTasks.SafeGet(m.taskList.selected)
+	if !ok {
+		return
+	}
+	id := task.ID()
+	if m.marked[id] {
+		delete(m.marked, id)
+	} else {
+		m.marked[id] = true
+	}
+}
+
+// rangeTaskIDs returns the tasks between the anchor and the cursor
+func (m *Model) rangeTaskIDs() []domain.TaskID {
+	if m.visualAnchor == noVisualRange {
+		return nil
+	}
+	start, end := min(m.visualAnchor, m.taskList.selected), max(m.visualAnchor, m.taskList.selected)
+	var ids []domain.TaskID
+	for i := start; i <= end; i++ {
+		if task, ok := m.filteredTasks.SafeGet(i); ok {
+			ids = append(ids, task.ID())
+		}
+	}
+	return ids
+}
+
+// markedTasks returns the marked tasks in the order of the task list
+func (m *Model) markedTasks() domain.Tasks {
+	inRange := make(map[domain.TaskID]bool)
+	for _, id := range m.rangeTaskIDs() {
+		inRange[id] = true
+	}
+	return m.filteredTasks.Filter(func(task domain.Task, _ int) bool {
+		id := task.ID()
+		return inRange[id] || m.marked[id]
+	})
+}
+
+// updateMarks shows the marked tasks in the task list
+func (m *Model) updateMarks() {
+	if m.viewMode != ViewVisual && m.viewMode != ViewBulkEdit {
+		m.taskList.SetMarkedItems(nil)
+		return
+	}
+	marked := make(map[domain.TaskID]bool)
+	for _, task := range m.markedTasks() {
+		marked[task.ID()] = true
+	}
+	items := make([]bool, m.filteredTasks.Len())
+	for i, task := range m.filteredTasks {
+		items[i] = marked[task.ID()]
+	}
+	m.taskList.SetMarkedItems(items)
+}
+
+// handleVisualKey handles key input in visual mode.
+// Actions apply to every marked task and leave visual mode.
+func (m *Model) handleVisualKey(msg tea.KeyMsg) tea.Cmd {
+	defer m.updateMarks()
+
+	key := msg.String()
+	switch key {
+	case escKey, ctrlCKey:
+		m.endVisual()
+		return nil
+	case qKey:
+		return tea.Quit
+	case vKey:
+		// v again leaves visual mode like in vim; after V it starts a range
+		if m.visualAnchor != noVisualRange {
+			m.endVisual()
+			return nil
+		}
+		m.startRange()
+		return nil
+	case VKey:
+		m.toggleMark()
+		return nil
+	case jKey, downKey:
+		m.taskList.MoveDown()
+		return nil
+	case kKey, upKey:
+		m.taskList.MoveUp()
+		return nil
+	case gKey:
+		m.taskList.SetSelectedIndex(0)
+		return nil
+	case GKey:
+		m.taskList.SetSelectedIndex(len(m.taskList.items) - 1)
+		return nil
+	}
+
+	tasks := m.markedTasks()
+	if tasks.Len() == 0 {
+		return nil
+	}
+	if isTaskMutationKey(key) || key == cKey || key == plusKey || key == atKey {
+		for _, task := range tasks {
+			if m.isArchivedTask(task) {
+				return m.setStatusMessage("📦 Archived tasks are read-only", 2*time.Second)
+			}
+		}
+	}
+
+	switch key {
+	case enterKey:
+		return m.bulkToggleCompletion(tasks)
+	case dKey:
+		if m.selectedFilterName() == FilterDeletedTasks {
+			return nil
+		}
+		return m.bulkUpdate(tasks, "Delete", "🗑️ Deleted", func(task *domain.Task) error {
+			return task.SoftDelete(time.Now())
+		})
+	case pKey:
+		return m.bulkUpdate(tasks, "Change priority", "🔼 Changed the priority of", func(task *domain.Task) error {
+			return task.CyclePriority(m.appConfig.PriorityLevels)
+		})
+	case tKey:
+		return m.bulkToggleDueToday(tasks)
+	case DKey:
+		return m.bulkUpdate(tasks, "Defer", "⏳ Deferred", func(task *domain.Task) error {
+			return task.Defer(m.appConfig.DeferDays, time.Now())
+		})
+	case rKey:
+		switch m.selectedFilterName() {
+		case FilterDeletedTasks:
+			return m.bulkUpdate(tasks, "Restore", "♻️ Restored", func(task *domain.Task) error {
+				return task.RestoreFromDeleted()
+			})
+		case FilterCompletedTasks:
+			return m.bulkUpdate(tasks, "Reopen", "🔄 Reopened", func(task *domain.Task) error {
+				if task.IsCompleted() {
+					task.ToggleCompletion()
+				}
+				return nil
+			})
+		}
+		return nil
+	case yKey:
+		lines := make([]string, 0, tasks.Len())
+		for _, task := range tasks {
+			lines = append(lines, task.String())
+		}
+		m.endVisual()
+		if err := clipboard.WriteAll(strings.Join(lines, "\n")); err != nil {
+			return m.setStatusMessage("❌ Failed to copy tasks", 3*time.Second)
+		}
+		return m.setStatusMessage(fmt.Sprintf("📋 %s copied to clipboard", countTasks(tasks.Len())), 2*time.Second)
+	case cKey:
+		m.startBulkEdit("")
+		return nil
+	case plusKey, atKey:
+		m.startBulkEdit(key)
+		return nil
+	}
+	return nil
+}
+
+// bulkUpdate applies fn to every task as one change that is undone together,
+// saves once and leaves visual mode
+func (m *Model) bulkUpdate(tasks domain.Tasks, action, done string, fn func(*domain.Task) error) tea.Cmd {
+	m.endVisual()
+	m.beginGroup()
+	updated, err := m.applyToTasks(tasks, action, fn)
+	m.endGroup()
+	if err != nil {
+		return tea.Batch(m.setStatusMessage("❌ "+err.Error(), 3*time.Second), m.saveAndRefresh())
+	}
+	return tea.Batch(
+		m.setStatusMessage(fmt.Sprintf("%s %s", done, countTasks(updated)), 2*time.Second),
+		m.saveAndRefresh(),
+	)
+}
+
+// applyToTasks applies fn to every task, recording each change under action,
+// and returns the number of tasks updated. Tasks that disappeared after a
+// reload are skipped; the first other error stops the update.
+func (m *Model) applyToTasks(tasks domain.Tasks, action string, fn func(*domain.Task) error) (int, error) {
+	updated := 0
+	for _, task := range tasks {
+		err := m.updateTask(action, task.ID(), fn)
+		if errors.Is(err, errTaskNotFound) {
+			continue
+		}
+		if err != nil {
+			return updated, fmt.Errorf("failed to update %q: %w", task.String(), err)
+		}
+		updated++
+	}
+	return updated, nil
+}
+
+// bulkToggleCompletion completes the tasks, or reopens them if all of them
+// are completed. Completing recurring tasks adds their next occurrences.
+func (m *Model) bulkToggleCompletion(tasks domain.Tasks) tea.Cmd {
+	allCompleted := true
+	for _, task := range tasks {
+		if !task.IsCompleted() {
+			allCompleted = false
+			break
+		}
+	}
+	if allCompleted {
+		return m.bulkUpdate(tasks, "Reopen", "🔄 Reopened", func(task *domain.Task) error {
+			task.ToggleCompletion()
+			return nil
+		})
+	}
+
+	m.endVisual()
+	m.beginGroup()
+	defer m.endGroup()
+
+	var next []*todotxt.Task
+	var recurErr error
+	completed, err := m.applyToTasks(tasks, "Complete", func(task *domain.Task) error {
+		if task.IsCompleted() {
+			return nil
+		}
+		task.ToggleCompletion()
+		// Completing a recurring task creates its next occurrence
+		occurrence, nextErr := task.NextOccurrence(task.GetCompletedDate())
+		if nextErr != nil {
+			recurErr = nextErr
+		} else if occurrence != nil {
+			next = append(next, occurrence)
+		}
+		return nil
+	})
+	// 新しいタスクは行番号をずらさないよう最後にまとめて追加する
+	for _, task := range next {
+		m.addTask("Repeat", task)
+	}
+
+	var message tea.Cmd
+	switch {
+	case err != nil:
+		message = m.setStatusMessage("❌ "+err.Error(), 3*time.Second)
+	case recurErr != nil:
+		message = m.setStatusMessage("⚠️ Tasks completed, but not repeated: "+recurErr.Error(), 3*time.Second)
+	case len(next) > 0:
+		message = m.setStatusMessage(fmt.Sprintf("🔁 Completed %s, %d repeated", countTasks(completed), len(next)), 3*time.Second)
+	default:
+		message = m.setStatusMessage("✅ Completed "+countTasks(completed), 2*time.Second)
+	}
+	return tea.Batch(message, m.saveAndRefresh())
+}
+
+// bulkToggleDueToday sets the due date of the tasks to today, or removes it
+// if all of them are already due today
+func (m *Model) bulkToggleDueToday(tasks domain.Tasks) tea.Cmd {
+	now := time.Now()
+	allDueToday := true
+	for i := range tasks {
+		if !tasks[i].IsDueToday(now) {
+			allDueToday = false
+			break
+		}
+	}
+	return m.bulkUpdate(tasks, "Toggle due today", "📅 Toggled the due date of", func(task *domain.Task) error {
+		if allDueToday || !task.IsDueToday(now) {
+			return task.ToggleDueToday(now)
+		}
+		return nil
+	})
+}
+
+// startBulkEdit opens the prompt for adding and removing projects, contexts
+// and due dates of the marked tasks
+func (m *Model) startBulkEdit(text string) {
+	m.viewMode = ViewBulkEdit
+	m.textInput.Prompt = BulkEditPrompt
+	m.textInput.PromptStyle = lipgloss.NewStyle().Foreground(m.currentTheme.Primary).Bold(true)
+	m.textInput.Placeholder = BulkEditPlaceholder
+	m.textInput.SetValue(text)
+	m.textInput.CursorEnd()
+	m.textInput.Focus()
+}
+
+// handleBulkEditKey handles key input while the bulk edit prompt is shown.
+// Esc goes back to visual mode with the marks kept.
+func (m *Model) handleBulkEditKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case escKey, ctrlCKey:
+		m.viewMode = ViewVisual
+		m.resetTextInput()
+		return nil
+	case enterKey:
+		edits, err := m.appConfig.Calendar().ParseTaskEdits(m.textInput.Value(), time.Now())
+		if err != nil {
+			return m.setStatusMessage("❌ "+err.Error(), 3*time.Second)
+		}
+		tasks := m.markedTasks()
+		m.resetTextInput()
+		return m.bulkUpdate(tasks, "Edit", "✏️ Edited", edits.Apply)
+	}
+
+	var cmd tea.Cmd
+	m.textInput, cmd = m.textInput.Update(msg)
+	return cmd
+}
+
+// visualHelpText returns the help text shown in visual mode
+func (m *Model) visualHelpText() string {
+	return fmt.Sprintf("-- VISUAL -- %s marked | %s", countTasks(m.markedTasks().Len()), HelpVisualMode)
+}
+
+// countTasks formats a number of tasks for status messages
+func countTasks(n int) string {
+	if n == 1 {
+		return "1 task"
+	}
+	return fmt.Sprintf("%d tasks", n)
+}
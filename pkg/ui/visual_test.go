@@ -0,0 +1,152 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/yuucu/todotui/internal/testutil"
+	"github.com/yuucu/todotui/pkg/domain"
+)
+
+func pressKey(m *Model, key string) {
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+}
+
+// markedTodos returns the descriptions of the marked tasks
+func markedTodos(m *Model) string {
+	var todos []string
+	for _, task := range m.markedTasks().ToTaskList() {
+		todos = append(todos, task.Todo)
+	}
+	return strings.Join(todos, ",")
+}
+
+func TestModel_VisualMarks(t *testing.T) {
+	model, _ := newTestModelWithFile(t, "Task A\nTask B\nTask C\nTask D\n")
+	selectFilter(t, model, FilterAllTasks)
+	model.activePane = paneTask
+
+	// v で範囲を選択する
+	pressKey(model, vKey)
+	pressKey(model, jKey)
+	if model.viewMode != ViewVisual {
+		t.Fatalf("view mode = %v, expected visual mode", model.viewMode)
+	}
+	if got := model.taskList.markedItems; len(got) != 4 || !got[0] || !got[1] || got[2] || got[3] {
+		t.Errorf("marked items after v j = %v", got)
+	}
+
+	// V は範囲を確定し、その後は単独のタスクを切り替える
+	pressKey(model, VKey)
+	pressKey(model, jKey)
+	pressKey(model, jKey)
+	pressKey(model, VKey)
+	if got := markedTodos(model); got != "Task A,Task B,Task D" {
+		t.Errorf("marked tasks = %q", got)
+	}
+	pressKey(model, kKey)
+	pressKey(model, kKey)
+	pressKey(model, VKey)
+	if got := markedTodos(model); got != "Task A,Task D" {
+		t.Errorf("marked tasks after unmarking = %q", got)
+	}
+
+	model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.viewMode != ViewFilter || model.taskList.markedItems != nil {
+		t.Errorf("esc should leave visual mode and clear the marks")
+	}
+}
+
+func TestModel_VisualBulkComplete(t *testing.T) {
+	model, todoPath := newTestModelWithFile(t, "Task A\nTask B rec:1w due:2025-01-10\nTask C\n")
+	selectFilter(t, model, FilterAllTasks)
+	model.activePane = paneTask
+
+	pressKey(model, vKey)
+	pressKey(model, jKey)
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	lines := strings.Split(strings.TrimSpace(testutil.ReadFile(t, todoPath)), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "x ") || !strings.HasPrefix(lines[1], "x ") || lines[2] != "Task C" {
+		t.Fatalf("file content after completing = %q", lines)
+	}
+	if !strings.HasPrefix(lines[3], "Task B") {
+		t.Errorf("the next occurrence should be added, got %q", lines[3])
+	}
+	if model.viewMode != ViewFilter {
+		t.Errorf("the action should leave visual mode")
+	}
+
+	// 一括操作は一度の u で元に戻る
+	model.Update(undoKeyMsg)
+	if got := testutil.ReadFile(t, todoPath); got != "Task A\nTask B rec:1w due:2025-01-10\nTask C\n" {
+		t.Errorf("file content after undo = %q", got)
+	}
+	if !strings.Contains(model.statusMessage, "(3 tasks)") {
+		t.Errorf("status message = %q, expected the whole bulk change", model.statusMessage)
+	}
+
+	model.Update(redoKeyMsg)
+	if got := testutil.ReadFile(t, todoPath); strings.Count(got, "x ") != 2 || strings.Count(got, "\n") != 4 {
+		t.Errorf("file content after redo = %q", got)
+	}
+}
+
+func TestModel_VisualBulkEdit(t *testing.T) {
+	model, todoPath := newTestModelWithFile(t, "Task A +inbox\nTask B +inbox @home\nTask C\n")
+	selectFilter(t, model, FilterAllTasks)
+	model.activePane = paneTask
+
+	pressKey(model, VKey)
+	pressKey(model, jKey)
+	pressKey(model, VKey)
+	pressKey(model, cKey)
+	if model.viewMode != ViewBulkEdit {
+		t.Fatalf("view mode = %v, expected the bulk edit prompt", model.viewMode)
+	}
+
+	// 解析できない入力ではプロンプトが残る
+	typeText(model, "inbox")
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if model.viewMode != ViewBulkEdit || !strings.Contains(model.statusMessage, "❌") {
+		t.Fatalf("an invalid edit should keep the prompt, status = %q", model.statusMessage)
+	}
+
+	model.textInput.SetValue("")
+	typeText(model, "-+inbox +work due:2025-02-01")
+	model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	expected := "Task A +work due:2025-02-01\nTask B @home +work due:2025-02-01\nTask C\n"
+	if got := testutil.ReadFile(t, todoPath); got != expected {
+		t.Errorf("file content after the bulk edit = %q, expected %q", got, expected)
+	}
+	if model.viewMode != ViewFilter || model.textInput.Prompt != TextInputPrompt {
+		t.Errorf("the edit should leave visual mode and reset the prompt")
+	}
+}
+
+func TestModel_VisualBulkDueToday(t *testing.T) {
+	today := time.Now().Format(domain.DateFormat)
+	model, todoPath := newTestModelWithFile(t, "Task A due:"+today+"\nTask B\n")
+	selectFilter(t, model, FilterAllTasks)
+	model.activePane = paneTask
+
+	// 一部だけ今日が期限なら、残りも今日にする
+	pressKey(model, vKey)
+	pressKey(model, GKey)
+	pressKey(model, tKey)
+	expected := "Task A due:" + today + "\nTask B due:" + today + "\n"
+	if got := testutil.ReadFile(t, todoPath); got != expected {
+		t.Fatalf("file content = %q, expected %q", got, expected)
+	}
+
+	// すべて今日が期限なら期限を外す
+	pressKey(model, kKey)
+	pressKey(model, vKey)
+	pressKey(model, GKey)
+	pressKey(model, tKey)
+	if got := testutil.ReadFile(t, todoPath); got != "Task A\nTask B\n" {
+		t.Errorf("file content = %q", got)
+	}
+}